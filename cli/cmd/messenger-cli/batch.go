@@ -0,0 +1,703 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+
+	"cli/pkg/contracts"
+)
+
+var (
+	batchFile   string
+	batchOut    string
+	concurrency int
+)
+
+var sendBatchCmd = &cobra.Command{
+	Use:   "send-batch",
+	Short: "Send many messages from a manifest",
+	Long: `Send every message in a JSONL or CSV manifest from one source chain.
+
+Each JSONL line is an object with dest_chain and payload (UTF-8 text) or
+payload_hex. A CSV file has a header row naming the same columns.
+
+Account nonces are assigned locally so up to --concurrency transactions are
+in flight at once. Each message's transaction, nonce, hash and status are
+written to --out as JSONL as it is mined. With --wait the command then
+waits for every delivery and prints a summary; destination chains come
+from --dest-rpc and --dest-contract pairs, or from --config by chain ID.`,
+	Example: `  messenger-cli send-batch --from sepolia --key-env SENDER_KEY --file messages.jsonl
+
+  # messages.jsonl
+  {"dest_chain": 80002, "payload": "Hello Amoy"}
+  {"dest_chain": 421614, "payload_hex": "0xdeadbeef"}
+
+  messenger-cli send-batch --from sepolia --keystore key.json --file messages.csv \
+    --concurrency 8 --out results.jsonl --wait --timeout 1h`,
+	RunE: runSendBatch,
+}
+
+func init() {
+	sendBatchCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of source chain (required)")
+	sendBatchCmd.Flags().StringVar(&contractAddr, "contract", "", "Source messenger contract address (required)")
+	sendBatchCmd.Flags().StringVar(&fromChain, "from", "", "Source chain from --config, instead of --rpc and --contract")
+	sendBatchCmd.Flags().StringVar(&batchFile, "file", "", "Manifest of messages, as .jsonl or .csv (required)")
+	sendBatchCmd.Flags().StringVar(&batchOut, "out", "batch-results.jsonl", "File to record each message's hashes and status in, as JSONL")
+	sendBatchCmd.Flags().IntVar(&concurrency, "concurrency", 4, "Most transactions in flight at once")
+	sendBatchCmd.Flags().BoolVar(&noFee, "no-fee", false, "Use the free sendMessage (relayers may not deliver it)")
+	sendBatchCmd.Flags().BoolVar(&wait, "wait", false, "Wait until every message is delivered and print a summary")
+	sendBatchCmd.Flags().StringArrayVar(&destRPCs, "dest-rpc", nil, "Destination chain RPC URL (with --wait, repeatable; default: from --config)")
+	sendBatchCmd.Flags().StringArrayVar(&destContracts, "dest-contract", nil, "Destination contract address, one per --dest-rpc")
+	sendBatchCmd.Flags().DurationVar(&waitTimeout, "timeout", 30*time.Minute, "How long to wait for all deliveries (with --wait)")
+
+	sendBatchCmd.MarkFlagRequired("file")
+	addSignerFlags(sendBatchCmd)
+}
+
+// batchMessage is one manifest entry.
+type batchMessage struct {
+	DestChain  int64  `json:"dest_chain"`
+	Payload    string `json:"payload"`
+	PayloadHex string `json:"payload_hex"`
+
+	index int
+	line  int
+	data  []byte
+}
+
+// batchRecord is the outcome of one manifest entry, as written to --out.
+type batchRecord struct {
+	Line        int             `json:"line" yaml:"line"`
+	DestChain   int64           `json:"dest_chain" yaml:"dest_chain"`
+	Status      string          `json:"status" yaml:"status"`
+	Error       string          `json:"error,omitempty" yaml:"error,omitempty"`
+	TxHash      string          `json:"tx_hash,omitempty" yaml:"tx_hash,omitempty"`
+	TxNonce     uint64          `json:"tx_nonce,omitempty" yaml:"tx_nonce,omitempty"`
+	Nonce       string          `json:"nonce,omitempty" yaml:"nonce,omitempty"`
+	MessageHash string          `json:"message_hash,omitempty" yaml:"message_hash,omitempty"`
+	SentAt      *time.Time      `json:"sent_at,omitempty" yaml:"sent_at,omitempty"`
+	Delivery    *deliveryResult `json:"delivery,omitempty" yaml:"delivery,omitempty"`
+
+	index int
+}
+
+// batchResult is the output of send-batch.
+type batchResult struct {
+	Total     int            `json:"total" yaml:"total"`
+	Sent      int            `json:"sent" yaml:"sent"`
+	Failed    int            `json:"failed" yaml:"failed"`
+	NotSent   int            `json:"not_sent" yaml:"not_sent"`
+	Delivered int            `json:"delivered" yaml:"delivered"`
+	Pending   int            `json:"pending" yaml:"pending"`
+	Latency   *latencyResult `json:"latency,omitempty" yaml:"latency,omitempty"`
+	OutFile   string         `json:"out_file" yaml:"out_file"`
+	Messages  []*batchRecord `json:"messages" yaml:"messages"`
+}
+
+// latencyResult summarises end-to-end latencies, in seconds.
+type latencyResult struct {
+	MinSeconds int64 `json:"min_seconds" yaml:"min_seconds"`
+	AvgSeconds int64 `json:"avg_seconds" yaml:"avg_seconds"`
+	MaxSeconds int64 `json:"max_seconds" yaml:"max_seconds"`
+}
+
+func runSendBatch(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if fromChain != "" {
+		chain, err := profileChain(fromChain)
+		if err != nil {
+			return err
+		}
+		setDefault(cmd, "rpc", &rpcURL, chain.GetRpcURL())
+		setDefault(cmd, "contract", &contractAddr, chain.SourceContract)
+	}
+	if rpcURL == "" || !common.IsHexAddress(contractAddr) {
+		return fmt.Errorf("--rpc and a valid --contract, or --from, are required")
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if len(destRPCs) != len(destContracts) {
+		return fmt.Errorf("give one --dest-contract for each --dest-rpc")
+	}
+
+	messages, err := readManifest(batchFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	for _, msg := range messages {
+		if msg.DestChain == chainID.Int64() {
+			return fmt.Errorf("%s line %d: dest_chain %d is the source chain", batchFile, msg.line, msg.DestChain)
+		}
+	}
+
+	// Connect to destinations first so no delivery can be missed
+	var dests map[int64]*destination
+	var fromBlocks map[int64]uint64
+	if wait {
+		dests, fromBlocks, err = connectBatchDestinations(ctx, messages)
+		defer func() {
+			for _, d := range dests {
+				d.client.Close()
+			}
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	auth, err := newTransactor(chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %w", err)
+	}
+	sender, err := newBatchSender(ctx, client, chainID, auth)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(batchOut)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", batchOut, err)
+	}
+	defer out.Close()
+
+	progressf("Sending %d message(s) from chain %s as %s, %d at a time\n\n", len(messages), chainID, auth.From.Hex(), concurrency)
+	records, err := sender.sendAll(ctx, messages, out)
+	if err != nil {
+		return err
+	}
+
+	if wait {
+		waitCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+		progressf("\n Waiting for deliveries...\n")
+		if err := waitForBatch(waitCtx, records, dests, fromBlocks); err != nil {
+			return err
+		}
+		if err := writeRecords(batchOut, records); err != nil {
+			return err
+		}
+	}
+
+	result := summarizeBatch(len(messages), records)
+	if err := printResult(result, result.print); err != nil {
+		return err
+	}
+
+	switch {
+	case result.Failed > 0:
+		return exitWith(cmd, exitFailed, fmt.Sprintf("%d message(s) failed", result.Failed))
+	case result.Pending > 0 || result.NotSent > 0:
+		return exitWith(cmd, exitPending, fmt.Sprintf("%d message(s) not delivered", result.Pending+result.NotSent))
+	}
+	return nil
+}
+
+// readManifest reads a .csv manifest, or JSONL from any other file or
+// stdin with -.
+func readManifest(path string) ([]*batchMessage, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open manifest: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var messages []*batchMessage
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		messages, err = readCSVManifest(r)
+	} else {
+		messages, err = readJSONLManifest(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("%s: no messages", path)
+	}
+
+	for i, msg := range messages {
+		msg.index = i
+		if msg.DestChain <= 0 {
+			return nil, fmt.Errorf("%s line %d: dest_chain is required", path, msg.line)
+		}
+		switch {
+		case msg.Payload != "" && msg.PayloadHex != "":
+			return nil, fmt.Errorf("%s line %d: give payload or payload_hex, not both", path, msg.line)
+		case msg.PayloadHex != "":
+			if msg.data, err = hexutil.Decode(ensure0x(msg.PayloadHex)); err != nil {
+				return nil, fmt.Errorf("%s line %d: invalid payload_hex: %w", path, msg.line, err)
+			}
+		default:
+			msg.data = []byte(msg.Payload)
+		}
+		// The contract rejects empty payloads
+		if len(msg.data) == 0 {
+			return nil, fmt.Errorf("%s line %d: payload is empty", path, msg.line)
+		}
+	}
+	return messages, nil
+}
+
+func readJSONLManifest(r io.Reader) ([]*batchMessage, error) {
+	var messages []*batchMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		msg := &batchMessage{line: line}
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(msg); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, scanner.Err()
+}
+
+func readCSVManifest(r io.Reader) ([]*batchMessage, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for name := range columns {
+		if name != "dest_chain" && name != "payload" && name != "payload_hex" {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	if _, ok := columns["dest_chain"]; !ok {
+		return nil, fmt.Errorf("header row has no dest_chain column")
+	}
+	get := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	var messages []*batchMessage
+	for i, row := range rows[1:] {
+		line := i + 2
+		destChain, err := strconv.ParseInt(strings.TrimSpace(get(row, "dest_chain")), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid dest_chain: %w", line, err)
+		}
+		messages = append(messages, &batchMessage{
+			DestChain:  destChain,
+			Payload:    get(row, "payload"),
+			PayloadHex: strings.TrimSpace(get(row, "payload_hex")),
+			line:       line,
+		})
+	}
+	return messages, nil
+}
+
+// connectBatchDestinations connects to every destination chain in messages,
+// from --dest-rpc pairs or else the --config profile with that chain ID,
+// and returns each chain's head so deliveries can be searched from it.
+func connectBatchDestinations(ctx context.Context, messages []*batchMessage) (map[int64]*destination, map[int64]uint64, error) {
+	dests, err := connectDestinations(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	destRPCs, destContracts = nil, nil
+	seen := make(map[int64]bool)
+	for _, msg := range messages {
+		if _, ok := dests[msg.DestChain]; ok || seen[msg.DestChain] {
+			continue
+		}
+		seen[msg.DestChain] = true
+		chain, err := profileChain(strconv.FormatInt(msg.DestChain, 10))
+		if err != nil {
+			return dests, nil, fmt.Errorf("--wait needs --dest-rpc and --dest-contract for chain %d: %w", msg.DestChain, err)
+		}
+		destRPCs = append(destRPCs, chain.GetRpcURL())
+		destContracts = append(destContracts, chain.DestContract)
+	}
+	more, err := connectDestinations(ctx)
+	for id, d := range more {
+		dests[id] = d
+	}
+	if err != nil {
+		return dests, nil, err
+	}
+
+	fromBlocks := make(map[int64]uint64, len(dests))
+	for id, d := range dests {
+		head, err := d.client.BlockNumber(ctx)
+		if err != nil {
+			return dests, nil, fmt.Errorf("failed to get block number of chain %d: %w", id, err)
+		}
+		fromBlocks[id] = head
+	}
+	for id := range seen {
+		if _, ok := dests[id]; !ok {
+			return dests, nil, fmt.Errorf("the --config RPC for chain %d serves a different chain", id)
+		}
+	}
+	return dests, fromBlocks, nil
+}
+
+// batchSender sends messages from one account, assigning account nonces
+// itself so transactions can be pipelined.
+type batchSender struct {
+	client   *ethclient.Client
+	chainID  *big.Int
+	address  common.Address
+	contract *contracts.SourceMessenger
+	auth     *bind.TransactOpts
+
+	mu    sync.Mutex
+	nonce uint64
+}
+
+func newBatchSender(ctx context.Context, client *ethclient.Client, chainID *big.Int, auth *bind.TransactOpts) (*batchSender, error) {
+	address := common.HexToAddress(contractAddr)
+	contract, err := contracts.NewSourceMessenger(address, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract: %w", err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, auth.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %w", err)
+	}
+	return &batchSender{
+		client:   client,
+		chainID:  chainID,
+		address:  address,
+		contract: contract,
+		auth:     auth,
+		nonce:    nonce,
+	}, nil
+}
+
+// sendAll sends messages with up to --concurrency in flight, writing each
+// record to out as it completes. Records are returned in manifest order;
+// messages not sent before ctx ended are nil.
+func (b *batchSender) sendAll(ctx context.Context, messages []*batchMessage, out io.Writer) ([]*batchRecord, error) {
+	jobs := make(chan *batchMessage)
+	done := make(chan *batchRecord)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range jobs {
+				done <- b.send(ctx, msg)
+			}
+		}()
+	}
+	go func() {
+	feed:
+		for _, msg := range messages {
+			select {
+			case jobs <- msg:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	records := make([]*batchRecord, len(messages))
+	enc := json.NewEncoder(out)
+	var count int
+	var writeErr error
+	for rec := range done {
+		count++
+		records[rec.index] = rec
+		if writeErr == nil {
+			writeErr = enc.Encode(rec)
+		}
+		if rec.Status == statusFailed {
+			progressf("[%d/%d] line %d -> chain %d: failed: %s\n", count, len(messages), rec.Line, rec.DestChain, rec.Error)
+		} else {
+			progressf("[%d/%d] line %d -> chain %d: nonce %s, hash %s\n", count, len(messages), rec.Line, rec.DestChain, rec.Nonce, rec.MessageHash)
+		}
+	}
+	if writeErr != nil {
+		return records, fmt.Errorf("failed to write %s: %w", batchOut, writeErr)
+	}
+	return records, nil
+}
+
+// send sends msg and waits for its transaction to be mined.
+func (b *batchSender) send(ctx context.Context, msg *batchMessage) *batchRecord {
+	rec := &batchRecord{Line: msg.line, DestChain: msg.DestChain, index: msg.index}
+	fail := func(err error) *batchRecord {
+		rec.Status = statusFailed
+		rec.Error = err.Error()
+		return rec
+	}
+
+	tx, err := b.submit(ctx, msg)
+	if err != nil {
+		return fail(err)
+	}
+	rec.TxHash = tx.Hash().Hex()
+	rec.TxNonce = tx.Nonce()
+
+	receipt, err := bind.WaitMined(ctx, b.client, tx)
+	if err != nil {
+		return fail(fmt.Errorf("transaction not confirmed: %w", err))
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fail(fmt.Errorf("transaction reverted"))
+	}
+	sent, err := findMessageSent(receipt, b.address, b.contract)
+	if err != nil {
+		return fail(err)
+	}
+
+	rec.Status = statusSent
+	rec.Nonce = sent.Nonce.String()
+	rec.MessageHash = computeMessageHash(sent, b.chainID).Hex()
+	rec.SentAt = unixTime(sent.Timestamp.Int64())
+	return rec
+}
+
+// submit signs and sends the transaction for msg. Nonces are handed out in
+// order under the lock and only consumed once the node accepts the
+// transaction, so a rejected message leaves no gap.
+func (b *batchSender) submit(ctx context.Context, msg *batchMessage) (*types.Transaction, error) {
+	destChain := big.NewInt(msg.DestChain)
+	var fee *big.Int
+	if !noFee {
+		var err error
+		if fee, err = b.contract.QuoteFee(&bind.CallOpts{Context: ctx}, destChain, msg.data); err != nil {
+			return nil, fmt.Errorf("failed to quote fee: %w", err)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	opts := *b.auth
+	opts.Context = ctx
+	opts.Nonce = new(big.Int).SetUint64(b.nonce)
+	opts.Value = fee
+
+	var tx *types.Transaction
+	var err error
+	if noFee {
+		tx, err = b.contract.SendMessage(&opts, destChain, msg.data)
+	} else {
+		tx, err = b.contract.SendMessageWithFee(&opts, destChain, msg.data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+	b.nonce++
+	return tx, nil
+}
+
+// waitForBatch waits for every sent record to be delivered, one poller per
+// destination chain, and marks each delivered, failed if its target call
+// reverted, or pending.
+func waitForBatch(ctx context.Context, records []*batchRecord, dests map[int64]*destination, fromBlocks map[int64]uint64) error {
+	pending := make(map[int64]map[common.Hash]bool)
+	for _, rec := range records {
+		if rec == nil || rec.Status != statusSent {
+			continue
+		}
+		if pending[rec.DestChain] == nil {
+			pending[rec.DestChain] = make(map[common.Hash]bool)
+		}
+		pending[rec.DestChain][common.HexToHash(rec.MessageHash)] = true
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	found := make(map[common.Hash]*delivery)
+	for id, hashes := range pending {
+		d := dests[id]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deliveries, err := waitForDeliveries(ctx, d.client, d.address, hashes, fromBlocks[id])
+			mu.Lock()
+			defer mu.Unlock()
+			maps.Copy(found, deliveries)
+			if err != nil && !errors.Is(err, context.DeadlineExceeded) && firstErr == nil {
+				firstErr = fmt.Errorf("chain %d: %w", id, err)
+			}
+			progressf("Chain %d: %d of %d delivered\n", id, len(deliveries), len(hashes))
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	// Latency is measured between the source and destination block times
+	blockTimes := make(map[common.Hash]uint64)
+	for _, rec := range records {
+		if rec == nil || rec.Status != statusSent {
+			continue
+		}
+		d, ok := found[common.HexToHash(rec.MessageHash)]
+		if !ok {
+			rec.Status = statusPending
+			continue
+		}
+		blockHash := d.received.Raw.BlockHash
+		blockTime, ok := blockTimes[blockHash]
+		if !ok {
+			header, err := dests[rec.DestChain].client.HeaderByHash(context.Background(), blockHash)
+			if err != nil {
+				return fmt.Errorf("failed to get destination block: %w", err)
+			}
+			blockTime = header.Time
+			blockTimes[blockHash] = blockTime
+		}
+		rec.Status = d.status()
+		rec.Delivery = d.result(blockTime, rec.SentAt.Unix())
+		if rec.Status == statusFailed {
+			rec.Error = "target call failed, returned " + rec.Delivery.ReturnData
+		}
+	}
+	return nil
+}
+
+// writeRecords replaces path with records, one JSON object per line.
+func writeRecords(path string, records []*batchRecord) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	enc := json.NewEncoder(f)
+	for _, rec := range records {
+		if rec == nil {
+			continue
+		}
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func summarizeBatch(total int, records []*batchRecord) *batchResult {
+	result := &batchResult{Total: total, OutFile: batchOut, Messages: []*batchRecord{}}
+	var latencies []int64
+	for _, rec := range records {
+		if rec == nil {
+			result.NotSent++
+			continue
+		}
+		result.Messages = append(result.Messages, rec)
+		switch rec.Status {
+		case statusFailed:
+			result.Failed++
+			if rec.Delivery == nil {
+				continue
+			}
+		case statusDelivered:
+			result.Delivered++
+			latencies = append(latencies, rec.Delivery.LatencySeconds)
+		case statusPending:
+			result.Pending++
+		}
+		result.Sent++
+	}
+
+	if len(latencies) > 0 {
+		l := &latencyResult{MinSeconds: latencies[0], MaxSeconds: latencies[0]}
+		var sum int64
+		for _, s := range latencies {
+			l.MinSeconds = min(l.MinSeconds, s)
+			l.MaxSeconds = max(l.MaxSeconds, s)
+			sum += s
+		}
+		l.AvgSeconds = sum / int64(len(latencies))
+		result.Latency = l
+	}
+	return result
+}
+
+func (r *batchResult) print() {
+	fmt.Printf("\n Batch Summary\n")
+	fmt.Printf("─────────────────\n")
+	fmt.Printf("Messages: %d\n", r.Total)
+	fmt.Printf("Sent: %d\n", r.Sent)
+	fmt.Printf("Failed: %d\n", r.Failed)
+	if r.NotSent > 0 {
+		fmt.Printf("Not Sent: %d (interrupted)\n", r.NotSent)
+	}
+	if wait {
+		fmt.Printf("Delivered: %d\n", r.Delivered)
+		fmt.Printf("Pending: %d\n", r.Pending)
+	}
+	if r.Latency != nil {
+		fmt.Printf("Latency: min %s, avg %s, max %s\n",
+			time.Duration(r.Latency.MinSeconds)*time.Second,
+			time.Duration(r.Latency.AvgSeconds)*time.Second,
+			time.Duration(r.Latency.MaxSeconds)*time.Second)
+	}
+	fmt.Printf("Results: %s\n", r.OutFile)
+
+	for _, rec := range r.Messages {
+		if rec.Status == statusFailed {
+			fmt.Printf("  line %d: %s\n", rec.Line, rec.Error)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadManifest(t *testing.T) {
+	type message struct {
+		destChain int64
+		data      string
+		line      int
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []message
+		err     string
+	}{
+		{"jsonl", "messages.jsonl", `{"dest_chain": 80002, "payload": "Hello Amoy"}
+
+{"dest_chain": 421614, "payload_hex": "deadbeef"}
+`, []message{{80002, "Hello Amoy", 1}, {421614, "\xde\xad\xbe\xef", 3}}, ""},
+		{"csv", "messages.CSV", "dest_chain,payload,payload_hex\n80002,\"Hello, Amoy\",\n 421614 ,,0xdeadbeef\n",
+			[]message{{80002, "Hello, Amoy", 2}, {421614, "\xde\xad\xbe\xef", 3}}, ""},
+		{"csv without payload_hex", "messages.csv", "payload,dest_chain\nhi,1\n", []message{{1, "hi", 2}}, ""},
+
+		{"jsonl unknown field", "m.jsonl", `{"dest_chain": 1, "payload": "a", "gas": 5}`, nil, "line 1: json: unknown field \"gas\""},
+		{"jsonl invalid", "m.jsonl", `{"dest_chain": 1, "payload": "a"}` + "\n" + `{"dest_chain": }`, nil, "line 2:"},
+		{"jsonl no dest chain", "m.jsonl", `{"payload": "a"}`, nil, "line 1: dest_chain is required"},
+		{"jsonl both payloads", "m.jsonl", `{"dest_chain": 1, "payload": "a", "payload_hex": "0x01"}`, nil, "line 1: give payload or payload_hex, not both"},
+		{"jsonl bad hex", "m.jsonl", `{"dest_chain": 1, "payload_hex": "0xzz"}`, nil, "line 1: invalid payload_hex"},
+		{"jsonl empty payload", "m.jsonl", `{"dest_chain": 1, "payload": ""}`, nil, "line 1: payload is empty"},
+		{"jsonl empty file", "m.jsonl", "\n\n", nil, "no messages"},
+
+		{"csv unknown column", "m.csv", "dest_chain,payload,gas\n1,a,5\n", nil, `unknown column "gas"`},
+		{"csv no dest chain column", "m.csv", "payload\na\n", nil, "header row has no dest_chain column"},
+		{"csv bad dest chain", "m.csv", "dest_chain,payload\nsepolia,a\n", nil, "line 2: invalid dest_chain"},
+		{"csv ragged row", "m.csv", "dest_chain,payload\n1,a,extra\n", nil, "wrong number of fields"},
+		{"csv empty payload", "m.csv", "dest_chain,payload\n1,a\n2,\n", nil, "line 3: payload is empty"},
+		{"csv header only", "m.csv", "dest_chain,payload\n", nil, "no messages"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			messages, err := readManifest(path)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("readManifest error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readManifest: %v", err)
+			}
+			if len(messages) != len(tt.want) {
+				t.Fatalf("readManifest returned %d messages, want %d", len(messages), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := messages[i]
+				if got.DestChain != want.destChain || !bytes.Equal(got.data, []byte(want.data)) || got.line != want.line || got.index != i {
+					t.Errorf("message %d = chain %d, data %q, line %d, index %d; want chain %d, data %q, line %d, index %d",
+						i, got.DestChain, got.data, got.line, got.index, want.destChain, want.data, want.line, i)
+				}
+			}
+		})
+	}
+}
+
+func TestReadManifestMissingFile(t *testing.T) {
+	_, err := readManifest(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err == nil || !strings.Contains(err.Error(), "failed to open manifest") {
+		t.Fatalf("readManifest error = %v, want failed to open manifest", err)
+	}
+}
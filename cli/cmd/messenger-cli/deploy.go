@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+
+	"cli/internal/bytecode"
+	"cli/internal/config"
+	"cli/pkg/contracts"
+)
+
+var (
+	relayerAddr string
+	deployOnly  string
+	writeConfig string
+	chainName   string
+)
+
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Deploy the messenger contracts",
+	Long: `Deploy SourceMessenger and DestinationMessenger to a chain from the
+bytecode embedded in the CLI, so Foundry is not needed.
+
+DestinationMessenger is deployed with --relayer as its relayer. Each
+deployment is checked by reading nonce() and relayer() back, and with
+--write-config the addresses are recorded under the chain's entry in a
+network profile or relayer config file.`,
+	Example: `  messenger-cli deploy --rpc https://sepolia... --keystore key.json --relayer 0xRelayer...
+
+  # Record the addresses under the amoy profile
+  messenger-cli deploy --from amoy --key-env DEPLOYER_KEY --relayer 0xRelayer... \
+    --write-config config.yaml
+
+  messenger-cli deploy --from sepolia --key-env DEPLOYER_KEY --only source --write-config relayer/config.yaml`,
+	RunE: runDeploy,
+}
+
+func init() {
+	deployCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the chain to deploy to")
+	deployCmd.Flags().StringVar(&fromChain, "from", "", "Chain from --config to deploy to, instead of --rpc")
+	deployCmd.Flags().StringVar(&relayerAddr, "relayer", "", "Relayer address for DestinationMessenger (required unless --only source)")
+	deployCmd.Flags().StringVar(&deployOnly, "only", "", "Deploy only source or destination")
+	deployCmd.Flags().StringVar(&writeConfig, "write-config", "", "Config file to record the deployed addresses in")
+	deployCmd.Flags().StringVar(&chainName, "name", "", "Chain name to record the addresses under (default: --from, or the chain ID's entry)")
+
+	addSignerFlags(deployCmd)
+}
+
+// deployResult is the output of deploy.
+type deployResult struct {
+	ChainID     int64             `json:"chain_id" yaml:"chain_id"`
+	Deployer    string            `json:"deployer" yaml:"deployer"`
+	Source      *deployedContract `json:"source,omitempty" yaml:"source,omitempty"`
+	Destination *deployedContract `json:"destination,omitempty" yaml:"destination,omitempty"`
+	ConfigFile  string            `json:"config_file,omitempty" yaml:"config_file,omitempty"`
+}
+
+// deployedContract is one deployed and verified contract.
+type deployedContract struct {
+	Address string `json:"address" yaml:"address"`
+	TxHash  string `json:"tx_hash" yaml:"tx_hash"`
+	Block   uint64 `json:"block" yaml:"block"`
+	Relayer string `json:"relayer,omitempty" yaml:"relayer,omitempty"`
+}
+
+func runDeploy(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var profileChainID int64
+	if fromChain != "" {
+		chain, err := profileChain(fromChain)
+		if err != nil {
+			return err
+		}
+		setDefault(cmd, "rpc", &rpcURL, chain.GetRpcURL())
+		profileChainID = chain.ChainID
+	}
+	if rpcURL == "" {
+		return fmt.Errorf("--rpc, or --from with a chain whose rpc_url is set, is required")
+	}
+
+	deploySource := deployOnly == "" || deployOnly == "source"
+	deployDest := deployOnly == "" || deployOnly == "destination"
+	if !deploySource && !deployDest {
+		return fmt.Errorf("invalid --only %q: want source or destination", deployOnly)
+	}
+	if deployDest && !common.IsHexAddress(relayerAddr) {
+		return fmt.Errorf("--relayer must be a valid address to deploy DestinationMessenger")
+	}
+
+	// Fail before sending anything if this build lacks the bytecode
+	var sourceCode, destCode []byte
+	var err error
+	if deploySource {
+		if sourceCode, err = bytecode.SourceMessenger(); err != nil {
+			return err
+		}
+	}
+	if deployDest {
+		if destCode, err = bytecode.DestinationMessenger(); err != nil {
+			return err
+		}
+	}
+
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if profileChainID != 0 && chainID.Int64() != profileChainID {
+		return fmt.Errorf("RPC for %s serves chain %s, not %d", fromChain, chainID.String(), profileChainID)
+	}
+
+	auth, err := newTransactor(chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %w", err)
+	}
+	auth.Context = ctx
+	progressf("Deploying to chain %s from %s\n", chainID.String(), auth.From.Hex())
+
+	result := &deployResult{ChainID: chainID.Int64(), Deployer: auth.From.Hex()}
+	opts := &bind.CallOpts{Context: ctx}
+
+	if deploySource {
+		deployed, err := deployContract(ctx, client, auth, "SourceMessenger", contracts.SourceMessengerABI, sourceCode)
+		if err != nil {
+			return err
+		}
+		source, err := contracts.NewSourceMessenger(common.HexToAddress(deployed.Address), client)
+		if err != nil {
+			return fmt.Errorf("failed to load SourceMessenger: %w", err)
+		}
+		nonce, err := source.Nonce(opts)
+		if err != nil {
+			return fmt.Errorf("failed to verify SourceMessenger: nonce(): %w", err)
+		}
+		if nonce.Sign() != 0 {
+			return fmt.Errorf("failed to verify SourceMessenger: nonce() is %s, want 0", nonce.String())
+		}
+		result.Source = deployed
+	}
+
+	if deployDest {
+		relayer := common.HexToAddress(relayerAddr)
+		deployed, err := deployContract(ctx, client, auth, "DestinationMessenger", contracts.DestinationMessengerABI, destCode, relayer)
+		if err != nil {
+			return err
+		}
+		dest, err := contracts.NewDestinationMessenger(common.HexToAddress(deployed.Address), client)
+		if err != nil {
+			return fmt.Errorf("failed to load DestinationMessenger: %w", err)
+		}
+		got, err := dest.Relayer(opts)
+		if err != nil {
+			return fmt.Errorf("failed to verify DestinationMessenger: relayer(): %w", err)
+		}
+		if got != relayer {
+			return fmt.Errorf("failed to verify DestinationMessenger: relayer() is %s, want %s", got.Hex(), relayer.Hex())
+		}
+		deployed.Relayer = got.Hex()
+		result.Destination = deployed
+	}
+
+	if writeConfig != "" {
+		d := config.Deployment{Name: chainName, ChainID: chainID.Int64()}
+		if d.Name == "" {
+			d.Name = fromChain
+		}
+		if d.Name == "" {
+			d.Name = fmt.Sprintf("chain-%d", chainID.Int64())
+		}
+		// A profile's entry already has its RPC; only a new entry needs one
+		if fromChain == "" {
+			d.RpcURL = rpcURL
+		}
+		if result.Source != nil {
+			d.SourceContract = result.Source.Address
+		}
+		if result.Destination != nil {
+			d.DestContract = result.Destination.Address
+		}
+		if err := config.SaveDeployment(writeConfig, d); err != nil {
+			return err
+		}
+		result.ConfigFile = writeConfig
+	}
+
+	return printResult(result, func() {
+		fmt.Printf("\n Deployment\n")
+		fmt.Printf("─────────────────\n")
+		fmt.Printf("Chain: %d\n", result.ChainID)
+		fmt.Printf("Deployer: %s\n", result.Deployer)
+		if c := result.Source; c != nil {
+			fmt.Printf("SourceMessenger: %s (block %d, tx %s)\n", c.Address, c.Block, c.TxHash)
+		}
+		if c := result.Destination; c != nil {
+			fmt.Printf("DestinationMessenger: %s (block %d, tx %s)\n", c.Address, c.Block, c.TxHash)
+			fmt.Printf("Relayer: %s\n", c.Relayer)
+		}
+		if result.ConfigFile != "" {
+			fmt.Printf("Addresses written to %s\n", result.ConfigFile)
+		}
+	})
+}
+
+// deployContract deploys code with the constructor params and waits until
+// the contract's code is on chain.
+func deployContract(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, name, abiJSON string, code []byte, params ...any) (*deployedContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s ABI: %w", name, err)
+	}
+	address, tx, _, err := bind.DeployContract(auth, parsed, code, client, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+	progressf("%s: tx %s, waiting for confirmation...\n", name, tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("failed to deploy %s: transaction %s reverted", name, tx.Hash().Hex())
+	}
+	deployed, err := client.CodeAt(ctx, address, receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s code: %w", name, err)
+	}
+	if len(deployed) == 0 {
+		return nil, fmt.Errorf("failed to deploy %s: no code at %s", name, address.Hex())
+	}
+	progressf("%s deployed at %s\n", name, address.Hex())
+
+	return &deployedContract{
+		Address: address.Hex(),
+		TxHash:  tx.Hash().Hex(),
+		Block:   receipt.BlockNumber.Uint64(),
+	}, nil
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+
+	"cli/internal/config"
+	"cli/pkg/contracts"
+)
+
+var (
+	historySender string
+	historyChains []string
+	historySince  time.Duration
+	historyPage   int
+	historyLimit  int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the messages an address has sent",
+	Long: `List every message sent by an address across the chains in --config,
+newest first, with its route, timestamps, status and transactions.
+
+MessageSent events are looked up on each chain's source contract and
+MessageReceived events on its dest contract, both by the indexed sender.
+Logs are fetched in chunks so public RPCs can serve the queries. Only
+messages sent within --since are listed; messages delivered within it but
+sent earlier are listed without their send.`,
+	Example: `  messenger-cli history --sender 0x1234...
+
+  messenger-cli history --sender 0x1234... --chain sepolia --chain amoy --since 720h --page 2
+
+  messenger-cli history --sender 0x1234... --output json`,
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historySender, "sender", "", "Address whose messages to list (required)")
+	historyCmd.Flags().StringArrayVar(&historyChains, "chain", nil, "Only search this chain from --config (repeatable; default: all)")
+	historyCmd.Flags().DurationVar(&historySince, "since", 7*24*time.Hour, "How far back to search")
+	historyCmd.Flags().IntVar(&historyPage, "page", 1, "Page of results to show")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Messages per page")
+
+	historyCmd.MarkFlagRequired("sender")
+}
+
+// historyEntry is one message in a sender's history. A message delivered
+// in the searched window but sent before it has no send fields.
+type historyEntry struct {
+	Hash         string     `json:"hash" yaml:"hash"`
+	Nonce        string     `json:"nonce" yaml:"nonce"`
+	SourceChain  int64      `json:"source_chain" yaml:"source_chain"`
+	DestChain    int64      `json:"dest_chain" yaml:"dest_chain"`
+	Status       string     `json:"status" yaml:"status"`
+	ExecutableAt *time.Time `json:"executable_at,omitempty" yaml:"executable_at,omitempty"`
+	SentAt       *time.Time `json:"sent_at,omitempty" yaml:"sent_at,omitempty"`
+	SourceTx     string     `json:"source_tx,omitempty" yaml:"source_tx,omitempty"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty" yaml:"delivered_at,omitempty"`
+	DestTx       string     `json:"dest_tx,omitempty" yaml:"dest_tx,omitempty"`
+
+	destBlock common.Hash
+}
+
+// historyResult is the output of history: one page of entries.
+type historyResult struct {
+	Sender   string          `json:"sender" yaml:"sender"`
+	Page     int             `json:"page" yaml:"page"`
+	Pages    int             `json:"pages" yaml:"pages"`
+	Total    int             `json:"total" yaml:"total"`
+	Messages []*historyEntry `json:"messages" yaml:"messages"`
+}
+
+// historyChain is a configured chain being searched.
+type historyChain struct {
+	config   *config.ChainConfig
+	client   *ethclient.Client
+	chainID  int64
+	from     uint64
+	head     uint64
+	source   *contracts.SourceMessenger
+	dest     *contracts.DestinationMessenger
+	destAddr common.Address
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if !common.IsHexAddress(historySender) {
+		return fmt.Errorf("invalid sender address: %s", historySender)
+	}
+	if historyPage < 1 || historyLimit < 1 {
+		return fmt.Errorf("--page and --limit must be at least 1")
+	}
+	sender := common.HexToAddress(historySender)
+
+	chains, err := connectHistoryChains(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, c := range chains {
+			c.client.Close()
+		}
+	}()
+
+	entries, err := collectHistory(ctx, chains, sender)
+	if err != nil {
+		return err
+	}
+
+	total := len(entries)
+	pages := max((total+historyLimit-1)/historyLimit, 1)
+	start := min((historyPage-1)*historyLimit, total)
+	page := entries[start:min(start+historyLimit, total)]
+	if err := resolveHistory(ctx, chains, page); err != nil {
+		return err
+	}
+
+	result := &historyResult{
+		Sender:   sender.Hex(),
+		Page:     historyPage,
+		Pages:    pages,
+		Total:    total,
+		Messages: page,
+	}
+	return printResult(result, func() { printHistory(result, chains) })
+}
+
+// connectHistoryChains dials every chain to search and finds the first
+// block within --since on each.
+func connectHistoryChains(ctx context.Context) ([]*historyChain, error) {
+	cfg, err := loadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	selected := cfg.Chains
+	if len(historyChains) > 0 {
+		selected = nil
+		for _, name := range historyChains {
+			chain, err := cfg.Chain(name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, *chain)
+		}
+	}
+
+	since := uint64(time.Now().Add(-historySince).Unix())
+	var chains []*historyChain
+	for i := range selected {
+		chain := &selected[i]
+		if chain.GetRpcURL() == "" {
+			fmt.Fprintf(os.Stderr, "Skipping %s: no rpc_url\n", chain.Name)
+			continue
+		}
+		c, err := connectHistoryChain(ctx, chain, since)
+		if err != nil {
+			for _, c := range chains {
+				c.client.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", chain.Name, err)
+		}
+		chains = append(chains, c)
+	}
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("no chains with an rpc_url in %s", configPath)
+	}
+	return chains, nil
+}
+
+func connectHistoryChain(ctx context.Context, chain *config.ChainConfig, since uint64) (*historyChain, error) {
+	client, err := ethclient.DialContext(ctx, chain.GetRpcURL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	c := &historyChain{config: chain, client: client}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if chain.ChainID != 0 && chainID.Int64() != chain.ChainID {
+		client.Close()
+		return nil, fmt.Errorf("RPC reports chain ID %s, config says %d", chainID, chain.ChainID)
+	}
+	c.chainID = chainID.Int64()
+
+	if c.head, err = client.BlockNumber(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	if c.from, err = blockAtTime(ctx, client, since); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	if chain.SourceContract != "" {
+		if c.source, err = contracts.NewSourceMessenger(common.HexToAddress(chain.SourceContract), client); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to load source contract: %w", err)
+		}
+	}
+	if chain.DestContract != "" {
+		c.destAddr = common.HexToAddress(chain.DestContract)
+		if c.dest, err = contracts.NewDestinationMessenger(c.destAddr, client); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to load destination contract: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// collectHistory finds every message sender sent or had delivered on
+// chains, matching deliveries to sends by message hash, newest first.
+func collectHistory(ctx context.Context, chains []*historyChain, sender common.Address) ([]*historyEntry, error) {
+	sourceABI, err := abi.JSON(strings.NewReader(contracts.SourceMessengerABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source ABI: %w", err)
+	}
+	destABI, err := abi.JSON(strings.NewReader(contracts.DestinationMessengerABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse destination ABI: %w", err)
+	}
+	// sender is the third indexed field of both events
+	senderTopic := common.BytesToHash(sender.Bytes())
+
+	var entries []*historyEntry
+	byHash := make(map[common.Hash]*historyEntry)
+	for _, c := range chains {
+		if c.source == nil {
+			continue
+		}
+		logs, err := filterLogs(ctx, c.client, ethereum.FilterQuery{
+			Addresses: []common.Address{common.HexToAddress(c.config.SourceContract)},
+			Topics:    [][]common.Hash{{sourceABI.Events["MessageSent"].ID}, nil, nil, {senderTopic}},
+		}, c.from, c.head)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to query sent messages: %w", c.config.Name, err)
+		}
+		for _, vLog := range logs {
+			event, err := c.source.ParseMessageSent(vLog)
+			if err != nil {
+				continue
+			}
+			hash := computeMessageHash(event, big.NewInt(c.chainID))
+			entry := &historyEntry{
+				Hash:        hash.Hex(),
+				Nonce:       event.Nonce.String(),
+				SourceChain: c.chainID,
+				DestChain:   event.DestinationChainId.Int64(),
+				SentAt:      unixTime(event.Timestamp.Int64()),
+				SourceTx:    vLog.TxHash.Hex(),
+			}
+			entries = append(entries, entry)
+			byHash[hash] = entry
+		}
+	}
+
+	for _, c := range chains {
+		if c.dest == nil {
+			continue
+		}
+		logs, err := filterLogs(ctx, c.client, ethereum.FilterQuery{
+			Addresses: []common.Address{c.destAddr},
+			Topics:    [][]common.Hash{{destABI.Events["MessageReceived"].ID}, nil, nil, {senderTopic}},
+		}, c.from, c.head)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to query delivered messages: %w", c.config.Name, err)
+		}
+		for _, vLog := range logs {
+			event, err := c.dest.ParseMessageReceived(vLog)
+			if err != nil {
+				continue
+			}
+			entry, ok := byHash[event.MessageHash]
+			if !ok {
+				entry = &historyEntry{
+					Hash:        common.Hash(event.MessageHash).Hex(),
+					Nonce:       event.Nonce.String(),
+					SourceChain: event.SourceChainId.Int64(),
+					DestChain:   c.chainID,
+				}
+				entries = append(entries, entry)
+				byHash[event.MessageHash] = entry
+			}
+			entry.Status = statusDelivered
+			entry.DestTx = vLog.TxHash.Hex()
+			entry.destBlock = vLog.BlockHash
+		}
+	}
+
+	// Newest first; deliveries of messages sent before --since go last
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].SentAt, entries[j].SentAt
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+	return entries, nil
+}
+
+// resolveHistory fills in delivery times and the status of undelivered
+// messages, for the shown page only since each costs RPC calls.
+func resolveHistory(ctx context.Context, chains []*historyChain, page []*historyEntry) error {
+	byID := make(map[int64]*historyChain, len(chains))
+	for _, c := range chains {
+		byID[c.chainID] = c
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	for _, entry := range page {
+		dest, ok := byID[entry.DestChain]
+		if entry.Status == statusDelivered {
+			header, err := dest.client.HeaderByHash(ctx, entry.destBlock)
+			if err != nil {
+				return fmt.Errorf("failed to get destination block: %w", err)
+			}
+			entry.DeliveredAt = unixTime(int64(header.Time))
+			continue
+		}
+		if !ok || dest.dest == nil {
+			entry.Status = statusUnknown
+			continue
+		}
+
+		hash := common.HexToHash(entry.Hash)
+		processed, err := dest.dest.IsProcessed(opts, hash)
+		if err != nil {
+			return fmt.Errorf("failed to check status: %w", err)
+		}
+		if processed {
+			// Delivered by another sender's transaction or a reorged one
+			entry.Status = statusDelivered
+			continue
+		}
+		if entry.Status, entry.ExecutableAt, err = undeliveredStatus(opts, dest.dest, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printHistory(result *historyResult, chains []*historyChain) {
+	names := make(map[int64]string)
+	if profiles != nil {
+		for _, chain := range profiles.Chains {
+			names[chain.ChainID] = chain.Name
+		}
+	}
+	for _, c := range chains {
+		names[c.chainID] = c.config.Name
+	}
+	chainName := func(id int64) string {
+		if name := names[id]; name != "" {
+			return name
+		}
+		return fmt.Sprint(id)
+	}
+
+	fmt.Printf("\n Message History\n")
+	fmt.Printf("─────────────────\n")
+	fmt.Printf("Sender: %s\n", result.Sender)
+	fmt.Printf("Page %d of %d (%d messages)\n\n", result.Page, result.Pages, result.Total)
+	if len(result.Messages) == 0 {
+		fmt.Printf("No messages found\n")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NONCE\tROUTE\tSENT\tSTATUS\tDELIVERED\tSOURCE TX\tDEST TX")
+	for _, e := range result.Messages {
+		fmt.Fprintf(w, "%s\t%s -> %s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Nonce, chainName(e.SourceChain), chainName(e.DestChain),
+			formatTime(e.SentAt), statusText(e.Status, e.ExecutableAt), formatTime(e.DeliveredAt),
+			shortHash(e.SourceTx), shortHash(e.DestTx))
+	}
+	w.Flush()
+}
+
+// shortHash abbreviates a hash for table output.
+func shortHash(h string) string {
+	if len(h) <= 14 {
+		if h == "" {
+			return "-"
+		}
+		return h
+	}
+	return h[:8] + "…" + h[len(h)-4:]
+}
@@ -0,0 +1,709 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+
+	"cli/pkg/contracts"
+)
+
+// defaultLookback is how many recent blocks --hash searches without
+// --from-block.
+const defaultLookback = 50000
+
+var (
+	inspectRPC       string
+	inspectChain     string
+	inspectContracts []string
+	inspectTx        string
+	inspectHash      string
+	fromBlock        int64
+	toBlock          int64
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Decode messenger events",
+	Long: `Decode the MessageSent and MessageReceived events of a transaction, a
+block range or a message, on a source or destination chain.
+
+Each event is shown with its indexed fields and payload, and its message
+hash is recomputed. On the source chain the hash is checked against the
+contract's FeePaid event; on the destination chain against the hash
+recomputed from the delivery call. Any mismatch is flagged and makes the
+command fail.
+
+A delivered message is also checked against the MessageSent event with its
+nonce and sender on the source chain, reached with --source-rpc or the
+--config chain with its chain ID. A delivery that does not match what was
+sent is flagged as a mismatch.`,
+	Example: `  messenger-cli inspect --rpc https://sepolia... --tx 0x9876...
+
+  messenger-cli inspect --chain amoy --hash 0xabcd...
+
+  messenger-cli inspect --rpc https://amoy... --tx 0x5432... --source-rpc https://sepolia...
+
+  messenger-cli inspect --chain sepolia --from-block 5000000 --to-block 5001000 \
+    --abi 'function mint(address to, uint256 amount)'`,
+	RunE: runInspect,
+}
+
+func init() {
+	inspectCmd.Flags().StringVar(&inspectRPC, "rpc", "", "RPC URL of the chain to inspect")
+	inspectCmd.Flags().StringVar(&inspectChain, "chain", "", "Chain from --config, instead of --rpc and --contract")
+	inspectCmd.Flags().StringArrayVar(&inspectContracts, "contract", nil, "Only decode events from this messenger contract (repeatable)")
+	inspectCmd.Flags().StringVar(&inspectTx, "tx", "", "Transaction to decode")
+	inspectCmd.Flags().StringVar(&inspectHash, "hash", "", "Message hash to find in the searched blocks")
+	inspectCmd.Flags().Int64Var(&fromBlock, "from-block", -1, "First block to search")
+	inspectCmd.Flags().Int64Var(&toBlock, "to-block", -1, "Last block to search (default: latest)")
+	inspectCmd.Flags().StringVar(&abiSig, "abi", "", "Decode payloads as calls to this function signature")
+	inspectCmd.Flags().StringVar(&sourceRPC, "source-rpc", "", "Source chain RPC URL, to check delivered messages against their MessageSent")
+	inspectCmd.Flags().StringVar(&sourceContract, "source-contract", "", "Source messenger contract address (with --source-rpc)")
+
+	inspectCmd.MarkFlagsMutuallyExclusive("tx", "hash")
+	inspectCmd.MarkFlagsMutuallyExclusive("tx", "from-block")
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if inspectChain != "" {
+		chain, err := profileChain(inspectChain)
+		if err != nil {
+			return err
+		}
+		setDefault(cmd, "rpc", &inspectRPC, chain.GetRpcURL())
+		if !cmd.Flags().Changed("contract") {
+			for _, addr := range []string{chain.SourceContract, chain.DestContract} {
+				if addr != "" {
+					inspectContracts = append(inspectContracts, addr)
+				}
+			}
+		}
+	}
+	if inspectRPC == "" {
+		return fmt.Errorf("--rpc or --chain is required")
+	}
+	if inspectTx == "" && inspectHash == "" && fromBlock < 0 {
+		return fmt.Errorf("one of --tx, --hash or --from-block is required")
+	}
+	var addresses []common.Address
+	for _, addr := range inspectContracts {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid contract address: %s", addr)
+		}
+		addresses = append(addresses, common.HexToAddress(addr))
+	}
+
+	client, err := ethclient.DialContext(ctx, inspectRPC)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	defer client.Close()
+
+	in, err := newInspector(ctx, client, addresses)
+	if err != nil {
+		return err
+	}
+	defer in.close()
+	if sourceRPC != "" {
+		if err := in.addSource(ctx, sourceRPC, sourceContract); err != nil {
+			return err
+		}
+	}
+
+	var logs []types.Log
+	if inspectTx != "" {
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(inspectTx))
+		if err != nil {
+			return fmt.Errorf("failed to get receipt for %s: %w", inspectTx, err)
+		}
+		for _, vLog := range receipt.Logs {
+			logs = append(logs, *vLog)
+		}
+	} else if logs, err = in.search(ctx); err != nil {
+		return err
+	}
+
+	result, err := in.inspect(ctx, logs)
+	if err != nil {
+		return err
+	}
+	if err := printResult(result, result.print); err != nil {
+		return err
+	}
+	if result.Mismatches > 0 {
+		return exitWith(cmd, exitFailed, fmt.Sprintf("found %d hash mismatch(es)", result.Mismatches))
+	}
+	return nil
+}
+
+// Hash check outcomes of an inspected event.
+const (
+	hashOK       = "ok"
+	hashMismatch = "mismatch"
+	hashSkipped  = "skipped"
+)
+
+// inspectResult is the output of inspect.
+type inspectResult struct {
+	ChainID    int64           `json:"chain_id" yaml:"chain_id"`
+	Events     []*inspectEvent `json:"events" yaml:"events"`
+	Mismatches int             `json:"mismatches" yaml:"mismatches"`
+}
+
+// inspectEvent is a decoded MessageSent or MessageReceived event.
+// MessageHash is the hash the contract reported, from FeePaid or the
+// indexed topic, and RecomputedHash the one derived from the message. For
+// a MessageReceived, SourceHash is the hash of the matching MessageSent.
+type inspectEvent struct {
+	Event           string         `json:"event" yaml:"event"`
+	Contract        string         `json:"contract" yaml:"contract"`
+	Block           uint64         `json:"block" yaml:"block"`
+	TxHash          string         `json:"tx_hash" yaml:"tx_hash"`
+	LogIndex        uint           `json:"log_index" yaml:"log_index"`
+	Nonce           string         `json:"nonce" yaml:"nonce"`
+	SourceChain     int64          `json:"source_chain" yaml:"source_chain"`
+	DestChain       int64          `json:"dest_chain" yaml:"dest_chain"`
+	Sender          string         `json:"sender" yaml:"sender"`
+	Timestamp       *time.Time     `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	Target          string         `json:"target,omitempty" yaml:"target,omitempty"`
+	GasLimit        string         `json:"gas_limit,omitempty" yaml:"gas_limit,omitempty"`
+	DeliveredBy     string         `json:"delivered_by,omitempty" yaml:"delivered_by,omitempty"`
+	Payload         *payloadResult `json:"payload" yaml:"payload"`
+	ReceiverData    *payloadResult `json:"receiver_data,omitempty" yaml:"receiver_data,omitempty"`
+	MessageHash     string         `json:"message_hash,omitempty" yaml:"message_hash,omitempty"`
+	RecomputedHash  string         `json:"recomputed_hash,omitempty" yaml:"recomputed_hash,omitempty"`
+	HashCheck       string         `json:"hash_check" yaml:"hash_check"`
+	HashCheckNote   string         `json:"hash_check_note,omitempty" yaml:"hash_check_note,omitempty"`
+	SourceTx        string         `json:"source_tx,omitempty" yaml:"source_tx,omitempty"`
+	SourceHash      string         `json:"source_hash,omitempty" yaml:"source_hash,omitempty"`
+	SourceCheck     string         `json:"source_check,omitempty" yaml:"source_check,omitempty"`
+	SourceCheckNote string         `json:"source_check_note,omitempty" yaml:"source_check_note,omitempty"`
+}
+
+// inspector decodes messenger events on one chain.
+type inspector struct {
+	client    *ethclient.Client
+	chainID   *big.Int
+	addresses []common.Address
+	source    *contracts.SourceMessenger
+	dest      *contracts.DestinationMessenger
+	sourceABI abi.ABI
+	destABI   abi.ABI
+
+	// Source chains of delivered messages by chain ID, connected on first
+	// use unless given with --source-rpc
+	sources    map[int64]*sourceChain
+	sourceErrs map[int64]error
+}
+
+// sourceChain is where delivered messages are looked up. A zero address
+// matches MessageSent from any contract.
+type sourceChain struct {
+	client  *ethclient.Client
+	address common.Address
+}
+
+func newInspector(ctx context.Context, client *ethclient.Client, addresses []common.Address) (*inspector, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	sourceABI, err := abi.JSON(strings.NewReader(contracts.SourceMessengerABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source ABI: %w", err)
+	}
+	destABI, err := abi.JSON(strings.NewReader(contracts.DestinationMessengerABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse destination ABI: %w", err)
+	}
+
+	// The bindings are only used to decode logs, so any address works
+	source, err := contracts.NewSourceMessenger(common.Address{}, client)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := contracts.NewDestinationMessenger(common.Address{}, client)
+	if err != nil {
+		return nil, err
+	}
+	return &inspector{
+		client:     client,
+		chainID:    chainID,
+		addresses:  addresses,
+		source:     source,
+		dest:       dest,
+		sourceABI:  sourceABI,
+		destABI:    destABI,
+		sources:    make(map[int64]*sourceChain),
+		sourceErrs: make(map[int64]error),
+	}, nil
+}
+
+func (in *inspector) close() {
+	for _, src := range in.sources {
+		src.client.Close()
+	}
+}
+
+// addSource connects to a source chain given by flags.
+func (in *inspector) addSource(ctx context.Context, rpcURL, contract string) error {
+	if contract != "" && !common.IsHexAddress(contract) {
+		return fmt.Errorf("invalid source contract address: %s", contract)
+	}
+	client, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to source RPC: %w", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to get source chain ID: %w", err)
+	}
+	if old := in.sources[chainID.Int64()]; old != nil {
+		old.client.Close()
+	}
+	src := &sourceChain{client: client}
+	if contract != "" {
+		src.address = common.HexToAddress(contract)
+	}
+	in.sources[chainID.Int64()] = src
+	return nil
+}
+
+// sourceFor returns the connection to chainID, from --source-rpc or else the
+// --config chain with that chain ID.
+func (in *inspector) sourceFor(ctx context.Context, chainID int64) (*sourceChain, error) {
+	if src, ok := in.sources[chainID]; ok {
+		return src, nil
+	}
+	if err, ok := in.sourceErrs[chainID]; ok {
+		return nil, err
+	}
+
+	err := in.connectProfileSource(ctx, chainID)
+	if err != nil {
+		in.sourceErrs[chainID] = err
+		return nil, err
+	}
+	return in.sources[chainID], nil
+}
+
+func (in *inspector) connectProfileSource(ctx context.Context, chainID int64) error {
+	chain, err := profileChain(strconv.FormatInt(chainID, 10))
+	if err != nil {
+		return fmt.Errorf("source chain %d unknown, give --source-rpc or a --config chain with its chain_id", chainID)
+	}
+	if chain.GetRpcURL() == "" {
+		return fmt.Errorf("--config chain %s has no rpc_url", chain.Name)
+	}
+	if err := in.addSource(ctx, chain.GetRpcURL(), chain.SourceContract); err != nil {
+		return err
+	}
+	if _, ok := in.sources[chainID]; !ok {
+		return fmt.Errorf("the --config RPC for chain %d serves a different chain", chainID)
+	}
+	return nil
+}
+
+// search returns the logs of every transaction in the searched blocks that
+// sent or delivered a message, or only the message --hash.
+func (in *inspector) search(ctx context.Context) ([]types.Log, error) {
+	head, err := in.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	to := head
+	if toBlock >= 0 && uint64(toBlock) < head {
+		to = uint64(toBlock)
+	}
+	var from uint64
+	switch {
+	case fromBlock >= 0:
+		from = uint64(fromBlock)
+	case to > defaultLookback:
+		from = to - defaultLookback
+	}
+
+	sent := in.sourceABI.Events["MessageSent"].ID
+	received := in.destABI.Events["MessageReceived"].ID
+	feePaid := in.sourceABI.Events["FeePaid"].ID
+
+	var queries []ethereum.FilterQuery
+	if inspectHash == "" {
+		queries = append(queries, ethereum.FilterQuery{Addresses: in.addresses, Topics: [][]common.Hash{{sent, received}}})
+	} else {
+		// Only FeePaid carries the hash on the source chain
+		hash := common.HexToHash(inspectHash)
+		queries = append(queries,
+			ethereum.FilterQuery{Addresses: in.addresses, Topics: [][]common.Hash{{received}, {hash}}},
+			ethereum.FilterQuery{Addresses: in.addresses, Topics: [][]common.Hash{{feePaid}, nil, {hash}}},
+		)
+	}
+
+	// Fetch whole receipts so related events in the same transaction are
+	// available for cross-checks
+	var logs []types.Log
+	seen := make(map[common.Hash]bool)
+	for _, query := range queries {
+		matches, err := filterLogs(ctx, in.client, query, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query logs: %w", err)
+		}
+		for _, match := range matches {
+			if seen[match.TxHash] {
+				continue
+			}
+			seen[match.TxHash] = true
+			receipt, err := in.client.TransactionReceipt(ctx, match.TxHash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get receipt for %s: %w", match.TxHash.Hex(), err)
+			}
+			for _, vLog := range receipt.Logs {
+				logs = append(logs, *vLog)
+			}
+		}
+	}
+	return logs, nil
+}
+
+// inspect decodes every MessageSent and MessageReceived event in logs and
+// checks its hash.
+func (in *inspector) inspect(ctx context.Context, logs []types.Log) (*inspectResult, error) {
+	fees := make(map[targetKey]common.Hash)
+	targets := make(map[targetKey]*contracts.SourceMessengerMessageTargeted)
+	for _, vLog := range logs {
+		if event, err := in.source.ParseFeePaid(vLog); err == nil {
+			fees[targetKey{source: vLog.Address, nonce: event.Nonce.String()}] = event.MessageHash
+		}
+		if event, err := in.source.ParseMessageTargeted(vLog); err == nil {
+			targets[targetKey{source: vLog.Address, nonce: event.Nonce.String()}] = event
+		}
+	}
+
+	result := &inspectResult{ChainID: in.chainID.Int64(), Events: []*inspectEvent{}}
+	for _, vLog := range logs {
+		if !in.watched(vLog.Address) {
+			continue
+		}
+		var event *inspectEvent
+		if sent, err := in.source.ParseMessageSent(vLog); err == nil {
+			key := targetKey{source: vLog.Address, nonce: sent.Nonce.String()}
+			event = in.sentEvent(sent, fees[key], targets[key])
+		} else if received, err := in.dest.ParseMessageReceived(vLog); err == nil {
+			event = in.receivedEvent(ctx, received)
+		} else {
+			continue
+		}
+		result.Events = append(result.Events, event)
+		if event.HashCheck == hashMismatch || event.SourceCheck == hashMismatch {
+			result.Mismatches++
+		}
+	}
+	return result, nil
+}
+
+func (in *inspector) watched(addr common.Address) bool {
+	if len(in.addresses) == 0 {
+		return true
+	}
+	for _, a := range in.addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// sentEvent decodes a MessageSent event and checks its recomputed hash
+// against the contract's FeePaid event, if there is one.
+func (in *inspector) sentEvent(event *contracts.SourceMessengerMessageSent, feeHash common.Hash, target *contracts.SourceMessengerMessageTargeted) *inspectEvent {
+	e := newInspectEvent("MessageSent", event.Raw)
+	e.Nonce = event.Nonce.String()
+	e.SourceChain = in.chainID.Int64()
+	e.DestChain = event.DestinationChainId.Int64()
+	e.Sender = event.Sender.Hex()
+	e.Timestamp = unixTime(event.Timestamp.Int64())
+	if target != nil {
+		e.Target = target.Target.Hex()
+		e.GasLimit = target.GasLimit.String()
+	}
+	e.setPayload(event.Payload, target != nil)
+
+	e.RecomputedHash = computeMessageHash(event, in.chainID).Hex()
+	switch {
+	case feeHash == (common.Hash{}):
+		e.HashCheck = hashSkipped
+		e.HashCheckNote = "no FeePaid event to compare with"
+	case feeHash.Hex() != e.RecomputedHash:
+		e.MessageHash = feeHash.Hex()
+		e.HashCheck = hashMismatch
+	default:
+		e.MessageHash = feeHash.Hex()
+		e.HashCheck = hashOK
+	}
+	return e
+}
+
+// receivedEvent decodes a MessageReceived event and checks its hash
+// against the one recomputed from the delivery call and the one sent on
+// the source chain.
+func (in *inspector) receivedEvent(ctx context.Context, event *contracts.DestinationMessengerMessageReceived) *inspectEvent {
+	e := newInspectEvent("MessageReceived", event.Raw)
+	e.MessageHash = common.Hash(event.MessageHash).Hex()
+	e.Nonce = event.Nonce.String()
+	e.SourceChain = event.SourceChainId.Int64()
+	e.DestChain = in.chainID.Int64()
+	e.Sender = event.Sender.Hex()
+
+	method, timestamp, err := in.deliveryCall(ctx, event.Raw)
+	if method != nil {
+		e.DeliveredBy = method.Name
+	}
+	e.setPayload(event.Payload, method != nil && strings.Contains(method.Name, "Execute"))
+	if err != nil {
+		e.HashCheck = hashSkipped
+		e.HashCheckNote = err.Error()
+		in.checkSource(ctx, e, event, nil)
+		return e
+	}
+
+	e.Timestamp = unixTime(timestamp.Int64())
+	e.RecomputedHash = computeMessageHash(&contracts.SourceMessengerMessageSent{
+		Nonce:              event.Nonce,
+		DestinationChainId: in.chainID,
+		Sender:             event.Sender,
+		Payload:            event.Payload,
+		Timestamp:          timestamp,
+	}, event.SourceChainId).Hex()
+	e.HashCheck = hashOK
+	if e.RecomputedHash != e.MessageHash {
+		e.HashCheck = hashMismatch
+	}
+	in.checkSource(ctx, e, event, timestamp)
+	return e
+}
+
+// checkSource compares the hash of a delivered message with that of the
+// MessageSent event with the same nonce, sender and destination on its
+// source chain. timestamp narrows the search to the send block if known.
+func (in *inspector) checkSource(ctx context.Context, e *inspectEvent, event *contracts.DestinationMessengerMessageReceived, timestamp *big.Int) {
+	src, err := in.sourceFor(ctx, event.SourceChainId.Int64())
+	if err != nil {
+		e.SourceCheck = hashSkipped
+		e.SourceCheckNote = err.Error()
+		return
+	}
+	sent, err := in.findSent(ctx, src, event, timestamp)
+	if err != nil {
+		e.SourceCheck = hashSkipped
+		e.SourceCheckNote = err.Error()
+		return
+	}
+	if len(sent) == 0 && timestamp == nil {
+		e.SourceCheck = hashSkipped
+		e.SourceCheckNote = fmt.Sprintf("no MessageSent with nonce %s from %s in the last %d blocks of chain %d", event.Nonce, event.Sender.Hex(), defaultLookback, event.SourceChainId)
+		return
+	}
+	if len(sent) == 0 {
+		e.SourceCheck = hashMismatch
+		e.SourceCheckNote = fmt.Sprintf("no MessageSent with nonce %s from %s on chain %d", event.Nonce, event.Sender.Hex(), event.SourceChainId)
+		return
+	}
+
+	// Without a source contract several messengers may share the nonce
+	e.SourceCheck = hashMismatch
+	for _, s := range sent {
+		hash := computeMessageHash(s, event.SourceChainId)
+		if e.SourceHash == "" || hash == common.Hash(event.MessageHash) {
+			e.SourceTx = s.Raw.TxHash.Hex()
+			e.SourceHash = hash.Hex()
+		}
+		if hash == common.Hash(event.MessageHash) {
+			e.SourceCheck = hashOK
+			return
+		}
+	}
+}
+
+// findSent returns the MessageSent events on src with the nonce, sender
+// and destination of a delivered message, searching from the first block
+// at timestamp or, if it is unknown, the last defaultLookback blocks.
+func (in *inspector) findSent(ctx context.Context, src *sourceChain, event *contracts.DestinationMessengerMessageReceived, timestamp *big.Int) ([]*contracts.SourceMessengerMessageSent, error) {
+	head, err := src.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source block number: %w", err)
+	}
+	var from uint64
+	if timestamp != nil {
+		if from, err = blockAtTime(ctx, src.client, timestamp.Uint64()); err != nil {
+			return nil, err
+		}
+	} else if head > defaultLookback {
+		from = head - defaultLookback
+	}
+	to := min(head, from+logChunkSize-1)
+
+	query := ethereum.FilterQuery{
+		Topics: [][]common.Hash{
+			{in.sourceABI.Events["MessageSent"].ID},
+			{common.BigToHash(event.Nonce)},
+			{common.BigToHash(in.chainID)},
+			{common.BytesToHash(event.Sender.Bytes())},
+		},
+	}
+	if src.address != (common.Address{}) {
+		query.Addresses = []common.Address{src.address}
+	}
+	logs, err := filterLogs(ctx, src.client, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source logs: %w", err)
+	}
+	var sent []*contracts.SourceMessengerMessageSent
+	for _, vLog := range logs {
+		if s, err := in.source.ParseMessageSent(vLog); err == nil {
+			sent = append(sent, s)
+		}
+	}
+	return sent, nil
+}
+
+// deliveryCall decodes the destination messenger call that emitted vLog.
+// Every delivery method takes (nonce, sourceChainId, sender, payload,
+// timestamp, ...), so the source timestamp can be read from it.
+func (in *inspector) deliveryCall(ctx context.Context, vLog types.Log) (*abi.Method, *big.Int, error) {
+	tx, _, err := in.client.TransactionByHash(ctx, vLog.TxHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx.To() == nil || *tx.To() != vLog.Address || len(tx.Data()) < 4 {
+		return nil, nil, fmt.Errorf("the transaction does not call the messenger directly")
+	}
+	method, err := in.destABI.MethodById(tx.Data()[:4])
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown delivery call: %w", err)
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil || len(args) < 5 {
+		return method, nil, fmt.Errorf("failed to decode %s call", method.Name)
+	}
+	timestamp, ok := args[4].(*big.Int)
+	if !ok || method.Inputs[4].Name != "_timestamp" {
+		return method, nil, fmt.Errorf("%s takes no timestamp", method.Name)
+	}
+	return method, timestamp, nil
+}
+
+func newInspectEvent(name string, vLog types.Log) *inspectEvent {
+	return &inspectEvent{
+		Event:    name,
+		Contract: vLog.Address.Hex(),
+		Block:    vLog.BlockNumber,
+		TxHash:   vLog.TxHash.Hex(),
+		LogIndex: vLog.Index,
+	}
+}
+
+// setPayload records the payload and, for targeted messages, the receiver
+// data inside it, which is what --abi then decodes. A payload that cannot
+// be unwrapped is shown as is.
+func (e *inspectEvent) setPayload(payload []byte, targeted bool) {
+	if targeted {
+		if data, err := decodeTargetPayload(payload); err == nil {
+			e.Payload = &payloadResult{Hex: hexutil.Encode(payload), Size: len(payload)}
+			e.ReceiverData = newPayloadResult(data)
+			return
+		}
+	}
+	e.Payload = newPayloadResult(payload)
+}
+
+func (r *inspectResult) print() {
+	for _, e := range r.Events {
+		fmt.Printf("\n %s\n", e.Event)
+		fmt.Printf("─────────────────\n")
+		fmt.Printf("Contract: %s\n", e.Contract)
+		fmt.Printf("Block: %d, Tx: %s, Log Index: %d\n", e.Block, e.TxHash, e.LogIndex)
+		if e.Event == "MessageSent" {
+			fmt.Printf("Nonce: %s (indexed)\n", e.Nonce)
+			fmt.Printf("Destination Chain: %d (indexed)\n", e.DestChain)
+			fmt.Printf("Sender: %s (indexed)\n", e.Sender)
+			fmt.Printf("Timestamp: %s\n", formatTime(e.Timestamp))
+			if e.Target != "" {
+				fmt.Printf("Target: %s (gas %s)\n", e.Target, e.GasLimit)
+			}
+		} else {
+			fmt.Printf("Message Hash: %s (indexed)\n", e.MessageHash)
+			fmt.Printf("Source Chain: %d (indexed)\n", e.SourceChain)
+			fmt.Printf("Sender: %s (indexed)\n", e.Sender)
+			fmt.Printf("Nonce: %s\n", e.Nonce)
+			if e.DeliveredBy != "" {
+				fmt.Printf("Delivered By: %s\n", e.DeliveredBy)
+			}
+		}
+
+		fmt.Printf("Payload (hex): %s\n", e.Payload.Hex)
+		payload := e.Payload
+		if e.ReceiverData != nil {
+			fmt.Printf("Receiver Data (hex): %s\n", e.ReceiverData.Hex)
+			payload = e.ReceiverData
+		}
+		if payload.Text != "" {
+			fmt.Printf("Payload (UTF-8): %q\n", payload.Text)
+		}
+		if payload.DecodeError != "" {
+			fmt.Printf("Decoded: %s\n", payload.DecodeError)
+		}
+		if len(payload.Decoded) > 0 {
+			fmt.Printf("Decoded:\n")
+			for _, arg := range payload.Decoded {
+				fmt.Printf("  %s\n", arg)
+			}
+		}
+
+		if e.Event == "MessageSent" {
+			fmt.Printf("Message Hash: %s (recomputed)\n", e.RecomputedHash)
+		} else if e.RecomputedHash != "" {
+			fmt.Printf("Recomputed Hash: %s (from calldata, timestamp %s)\n", e.RecomputedHash, formatTime(e.Timestamp))
+		}
+		switch {
+		case e.HashCheck == hashSkipped:
+			fmt.Printf("Hash Check: skipped, %s\n", e.HashCheckNote)
+		case e.HashCheck == hashMismatch && e.Event == "MessageSent":
+			fmt.Printf("Hash Check: MISMATCH, FeePaid has %s\n", e.MessageHash)
+		case e.HashCheck == hashMismatch:
+			fmt.Printf("Hash Check: MISMATCH\n")
+		case e.Event == "MessageSent":
+			fmt.Printf("Hash Check: OK, matches FeePaid\n")
+		default:
+			fmt.Printf("Hash Check: OK\n")
+		}
+
+		switch e.SourceCheck {
+		case hashSkipped:
+			fmt.Printf("Source Check: skipped, %s\n", e.SourceCheckNote)
+		case hashMismatch:
+			if e.SourceHash == "" {
+				fmt.Printf("Source Check: MISMATCH, %s\n", e.SourceCheckNote)
+			} else {
+				fmt.Printf("Source Check: MISMATCH, MessageSent in %s has %s\n", e.SourceTx, e.SourceHash)
+			}
+		case hashOK:
+			fmt.Printf("Source Check: OK, matches MessageSent in %s\n", e.SourceTx)
+		}
+	}
+	fmt.Printf("\n%d event(s) on chain %d, %d hash mismatch(es)\n", len(r.Events), r.ChainID, r.Mismatches)
+}
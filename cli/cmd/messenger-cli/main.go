@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -10,7 +11,6 @@ import (
 var (
 	rpcURL       string
 	contractAddr string
-	privateKey   string
 	configPath   string
 )
 
@@ -18,16 +18,35 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use:   "messenger-cli",
 		Short: "Cross-chain messenger CLI",
-		Long:  "A CLI tool for sending and tracking cross-chain messages",
+		Long: `A CLI tool for sending and tracking cross-chain messages.
+
+Every command prints its result as text, json or yaml (--output). Commands
+that report delivery exit with 0 when delivered, 2 when still pending and
+3 when delivery failed; any other error exits with 1.`,
+		// main prints errors itself, once
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutput()
+		},
 	}
 
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "config.yaml", "Path to config file")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json or yaml")
 
 	rootCmd.AddCommand(sendCmd)
+	rootCmd.AddCommand(sendBatchCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(deployCmd)
 
 	if err := rootCmd.Execute(); err != nil {
+		var exit *exitCodeError
+		if errors.As(err, &exit) {
+			fmt.Fprintln(os.Stderr, exit.reason)
+			os.Exit(exit.code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"cli/pkg/contracts"
+)
+
+// deliveryPollInterval is how often the destination chain is checked while
+// waiting for a message to be delivered.
+const deliveryPollInterval = 5 * time.Second
+
+// logChunkSize is the most blocks asked for in one eth_getLogs call, to stay
+// under common provider limits.
+const logChunkSize = 5000
+
+// findMessageSent returns the MessageSent event that source emitted in
+// receipt.
+func findMessageSent(receipt *types.Receipt, source common.Address, contract *contracts.SourceMessenger) (*contracts.SourceMessengerMessageSent, error) {
+	events := messagesSent(receipt, source, contract)
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no MessageSent event from %s in transaction %s", source.Hex(), receipt.TxHash.Hex())
+	}
+	return events[0], nil
+}
+
+// messagesSent returns every MessageSent event in receipt emitted by
+// source, or by any contract if source is the zero address.
+func messagesSent(receipt *types.Receipt, source common.Address, contract *contracts.SourceMessenger) []*contracts.SourceMessengerMessageSent {
+	var events []*contracts.SourceMessengerMessageSent
+	for _, vLog := range receipt.Logs {
+		if source != (common.Address{}) && vLog.Address != source {
+			continue
+		}
+		if event, err := contract.ParseMessageSent(*vLog); err == nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// targetKey identifies the send a MessageTargeted event belongs to.
+type targetKey struct {
+	source common.Address
+	nonce  string
+}
+
+// messagesTargeted returns the MessageTargeted events in receipt by the
+// send they belong to, so targeted payloads can be unwrapped.
+func messagesTargeted(receipt *types.Receipt, contract *contracts.SourceMessenger) map[targetKey]*contracts.SourceMessengerMessageTargeted {
+	events := make(map[targetKey]*contracts.SourceMessengerMessageTargeted)
+	for _, vLog := range receipt.Logs {
+		if event, err := contract.ParseMessageTargeted(*vLog); err == nil {
+			events[targetKey{source: vLog.Address, nonce: event.Nonce.String()}] = event
+		}
+	}
+	return events
+}
+
+// computeMessageHash mirrors the contracts' keccak256(abi.encodePacked(...)),
+// where every uint256 is packed as a full 32-byte word.
+func computeMessageHash(event *contracts.SourceMessengerMessageSent, sourceChainID *big.Int) common.Hash {
+	return crypto.Keccak256Hash(
+		common.BigToHash(event.Nonce).Bytes(),
+		common.BigToHash(sourceChainID).Bytes(),
+		common.BigToHash(event.DestinationChainId).Bytes(),
+		event.Sender.Bytes(),
+		event.Payload,
+		common.BigToHash(event.Timestamp).Bytes(),
+	)
+}
+
+// delivery is a message's MessageReceived event and, for a targeted
+// message, the MessageExecuted event emitted with it.
+type delivery struct {
+	received *contracts.DestinationMessengerMessageReceived
+	executed *contracts.DestinationMessengerMessageExecuted
+}
+
+// status is delivered, or failed if the target call reverted.
+func (d *delivery) status() string {
+	if d.executed != nil && !d.executed.Success {
+		return statusFailed
+	}
+	return statusDelivered
+}
+
+// result describes d for output, with the latency from sentAt to the
+// destination block time.
+func (d *delivery) result(blockTime uint64, sentAt int64) *deliveryResult {
+	r := &deliveryResult{
+		TxHash:         d.received.Raw.TxHash.Hex(),
+		Block:          d.received.Raw.BlockNumber,
+		DeliveredAt:    unixTime(int64(blockTime)),
+		LatencySeconds: int64(blockTime) - sentAt,
+	}
+	if d.executed != nil {
+		success := d.executed.Success
+		r.Executed = &success
+		r.ReturnData = hexutil.Encode(d.executed.ReturnData)
+	}
+	return r
+}
+
+// deliveryLogs decodes dest's logs into deliveries by message hash.
+type deliveryLogs struct {
+	contract *contracts.DestinationMessenger
+	query    ethereum.FilterQuery
+}
+
+// newDeliveryLogs builds a query for the MessageReceived and
+// MessageExecuted events of dest, restricted to hash unless it is zero.
+func newDeliveryLogs(client *ethclient.Client, dest common.Address, hash common.Hash) (*deliveryLogs, error) {
+	destABI, err := abi.JSON(strings.NewReader(contracts.DestinationMessengerABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse destination ABI: %w", err)
+	}
+	contract, err := contracts.NewDestinationMessenger(dest, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load destination contract: %w", err)
+	}
+
+	// Both events index the message hash first
+	topics := [][]common.Hash{{destABI.Events["MessageReceived"].ID, destABI.Events["MessageExecuted"].ID}}
+	if hash != (common.Hash{}) {
+		topics = append(topics, []common.Hash{hash})
+	}
+	return &deliveryLogs{
+		contract: contract,
+		query:    ethereum.FilterQuery{Addresses: []common.Address{dest}, Topics: topics},
+	}, nil
+}
+
+// collect fetches blocks from to to and adds the events found to found,
+// for the hashes wanted reports true for.
+func (l *deliveryLogs) collect(ctx context.Context, client *ethclient.Client, from, to uint64, wanted func(common.Hash) bool, found map[common.Hash]*delivery) error {
+	logs, err := filterLogs(ctx, client, l.query, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to query destination logs: %w", err)
+	}
+	for _, vLog := range logs {
+		if len(vLog.Topics) < 2 || !wanted(vLog.Topics[1]) {
+			continue
+		}
+		hash := vLog.Topics[1]
+		d := found[hash]
+		if d == nil {
+			d = &delivery{}
+		}
+		if received, err := l.contract.ParseMessageReceived(vLog); err == nil && d.received == nil {
+			d.received = received
+		} else if executed, err := l.contract.ParseMessageExecuted(vLog); err == nil && d.executed == nil {
+			d.executed = executed
+		}
+		found[hash] = d
+	}
+	return nil
+}
+
+// waitForDelivery polls dest from fromBlock until it delivers hash, and
+// returns the delivery.
+func waitForDelivery(ctx context.Context, client *ethclient.Client, dest common.Address, hash common.Hash, fromBlock uint64) (*delivery, error) {
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get destination block number: %w", err)
+		}
+		if head >= fromBlock {
+			d, err := findDelivery(ctx, client, dest, hash, fromBlock, head)
+			if err != nil || d != nil {
+				return d, err
+			}
+			fromBlock = head + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForDeliveries polls dest from fromBlock until it has delivered every
+// hash in pending, and returns the deliveries found by hash. If ctx ends
+// first, the deliveries found so far are returned with its error.
+func waitForDeliveries(ctx context.Context, client *ethclient.Client, dest common.Address, pending map[common.Hash]bool, fromBlock uint64) (map[common.Hash]*delivery, error) {
+	deliveries, err := newDeliveryLogs(client, dest, common.Hash{})
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[common.Hash]*delivery, len(pending))
+	wanted := func(hash common.Hash) bool { return pending[hash] }
+	ticker := time.NewTicker(deliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return delivered(found), fmt.Errorf("failed to get destination block number: %w", err)
+		}
+		if head >= fromBlock {
+			if err := deliveries.collect(ctx, client, fromBlock, head, wanted, found); err != nil {
+				return delivered(found), err
+			}
+			if len(delivered(found)) == len(pending) {
+				return delivered(found), nil
+			}
+			fromBlock = head + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return delivered(found), ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// delivered returns the entries of found whose MessageReceived was seen.
+func delivered(found map[common.Hash]*delivery) map[common.Hash]*delivery {
+	out := make(map[common.Hash]*delivery, len(found))
+	for hash, d := range found {
+		if d.received != nil {
+			out[hash] = d
+		}
+	}
+	return out
+}
+
+// findDelivery searches blocks from to to of dest for the delivery of
+// hash. It returns nil if there is none.
+func findDelivery(ctx context.Context, client *ethclient.Client, dest common.Address, hash common.Hash, from, to uint64) (*delivery, error) {
+	deliveries, err := newDeliveryLogs(client, dest, hash)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[common.Hash]*delivery, 1)
+	wanted := func(h common.Hash) bool { return h == hash }
+	if err := deliveries.collect(ctx, client, from, to, wanted, found); err != nil {
+		return nil, err
+	}
+	return delivered(found)[hash], nil
+}
+
+// filterLogs runs query over blocks from to to in chunks of logChunkSize
+// blocks, skipping removed logs.
+func filterLogs(ctx context.Context, client *ethclient.Client, query ethereum.FilterQuery, from, to uint64) ([]types.Log, error) {
+	var out []types.Log
+	for start := from; start <= to; start += logChunkSize {
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(min(start+logChunkSize-1, to))
+		logs, err := client.FilterLogs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, vLog := range logs {
+			if !vLog.Removed {
+				out = append(out, vLog)
+			}
+		}
+	}
+	return out, nil
+}
+
+// blockAtTime returns the first block of the chain with a timestamp at or
+// after t, or the head if there is none.
+func blockAtTime(ctx context.Context, client *ethclient.Client, t uint64) (uint64, error) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	lo, hi := uint64(0), head
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get block %d: %w", mid, err)
+		}
+		if header.Time < t {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cli/pkg/contracts"
+)
+
+// fixtureMessage is the message contracts/test/StorageProof.t.sol delivers:
+// nonce 7 from 0x123 on chain 11155111 to chain 31337. DestinationMessenger
+// accepts its storage proof only under fixtureHash.
+func fixtureMessage() *contracts.SourceMessengerMessageSent {
+	return &contracts.SourceMessengerMessageSent{
+		Nonce:              big.NewInt(7),
+		DestinationChainId: big.NewInt(31337),
+		Sender:             common.HexToAddress("0x123"),
+		Payload:            []byte("Hello"),
+		Timestamp:          big.NewInt(1000),
+	}
+}
+
+var fixtureHash = common.HexToHash("0xa37a3fce5b1265fe91c86c48b068c47fd0f994079c619e603cb7d92e591e3a02")
+
+func TestComputeMessageHash(t *testing.T) {
+	if got := computeMessageHash(fixtureMessage(), big.NewInt(11155111)); got != fixtureHash {
+		t.Fatalf("computeMessageHash = %s, want %s", got.Hex(), fixtureHash.Hex())
+	}
+
+	// Every field is part of the hash
+	tests := []struct {
+		name   string
+		modify func(m *contracts.SourceMessengerMessageSent)
+	}{
+		{"nonce", func(m *contracts.SourceMessengerMessageSent) { m.Nonce = big.NewInt(8) }},
+		{"dest chain", func(m *contracts.SourceMessengerMessageSent) { m.DestinationChainId = big.NewInt(1) }},
+		{"sender", func(m *contracts.SourceMessengerMessageSent) { m.Sender = common.HexToAddress("0x124") }},
+		{"payload", func(m *contracts.SourceMessengerMessageSent) { m.Payload = []byte("Hello!") }},
+		{"timestamp", func(m *contracts.SourceMessengerMessageSent) { m.Timestamp = big.NewInt(1001) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := fixtureMessage()
+			tt.modify(m)
+			if computeMessageHash(m, big.NewInt(11155111)) == fixtureHash {
+				t.Errorf("changing the %s kept the hash", tt.name)
+			}
+		})
+	}
+	if computeMessageHash(fixtureMessage(), big.NewInt(1)) == fixtureHash {
+		t.Error("changing the source chain kept the hash")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Exit codes, so scripts and CI pipelines can gate on delivery. Any other
+// failure exits with exitError.
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitPending = 2
+	exitFailed  = 3
+)
+
+// Message statuses, as they appear in json and yaml output.
+const (
+	statusSent       = "sent"
+	statusDelivered  = "delivered"
+	statusPending    = "pending"
+	statusProposed   = "proposed"
+	statusChallenged = "challenged"
+	statusUnknown    = "unknown"
+	statusFailed     = "failed"
+)
+
+var outputFormat string
+
+func validateOutput() error {
+	switch outputFormat {
+	case "text", "json", "yaml":
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q: want text, json or yaml", outputFormat)
+	}
+}
+
+// progressf prints progress meant for people. With json or yaml output it
+// goes to stderr, so stdout holds only the result.
+func progressf(format string, args ...any) {
+	if outputFormat == "text" {
+		fmt.Printf(format, args...)
+		return
+	}
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// printResult prints a command's result in the --output format, calling
+// text for the human-readable form.
+func printResult(result any, text func()) error {
+	switch outputFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		text()
+		return nil
+	}
+}
+
+// exitCodeError ends the process with code once the command's result has
+// been printed.
+type exitCodeError struct {
+	code   int
+	reason string
+}
+
+func (e *exitCodeError) Error() string { return e.reason }
+
+// exitWith returns an error that exits with code, without cobra's usage
+// text since the command itself succeeded.
+func exitWith(cmd *cobra.Command, code int, reason string) error {
+	cmd.SilenceUsage = true
+	return &exitCodeError{code: code, reason: reason}
+}
+
+// statusExitCode maps message statuses to the exit code for all of them:
+// failed if any failed or was challenged, pending if any is not yet
+// delivered.
+func statusExitCode(statuses ...string) int {
+	code := exitOK
+	for _, status := range statuses {
+		switch status {
+		case statusChallenged, statusFailed:
+			return exitFailed
+		case statusPending, statusProposed, statusUnknown:
+			code = exitPending
+		}
+	}
+	return code
+}
+
+// statusText renders a status for text output.
+func statusText(status string, executableAt *time.Time) string {
+	text := strings.ToUpper(status[:1]) + status[1:]
+	if status == statusProposed && executableAt != nil {
+		text += ", executable at " + executableAt.Format(time.RFC3339)
+	}
+	return text
+}
+
+// formatTime renders an optional time for text output.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// unixTime converts a block or event timestamp for output.
+func unixTime(seconds int64) *time.Time {
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
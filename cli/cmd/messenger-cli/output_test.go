@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cli/pkg/contracts"
+)
+
+func TestStatusExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		want     int
+	}{
+		{"none", nil, exitOK},
+		{"delivered", []string{statusDelivered}, exitOK},
+		{"sent", []string{statusSent, statusDelivered}, exitOK},
+		{"pending", []string{statusDelivered, statusPending}, exitPending},
+		{"proposed", []string{statusProposed}, exitPending},
+		{"unknown destination", []string{statusUnknown, statusDelivered}, exitPending},
+		{"challenged", []string{statusChallenged}, exitFailed},
+		{"failed", []string{statusFailed}, exitFailed},
+		{"failed wins over pending", []string{statusPending, statusFailed, statusDelivered}, exitFailed},
+		{"challenged after pending", []string{statusPending, statusChallenged}, exitFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusExitCode(tt.statuses...); got != tt.want {
+				t.Errorf("statusExitCode(%v) = %d, want %d", tt.statuses, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusText(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		status       string
+		executableAt *time.Time
+		want         string
+	}{
+		{statusDelivered, nil, "Delivered"},
+		{statusFailed, nil, "Failed"},
+		{statusProposed, &at, "Proposed, executable at 2026-01-02T03:04:05Z"},
+		{statusPending, &at, "Pending"},
+	}
+
+	for _, tt := range tests {
+		if got := statusText(tt.status, tt.executableAt); got != tt.want {
+			t.Errorf("statusText(%s) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestDeliveryStatus(t *testing.T) {
+	received := &contracts.DestinationMessengerMessageReceived{}
+	tests := []struct {
+		name     string
+		executed *contracts.DestinationMessengerMessageExecuted
+		want     string
+		result   *bool
+		data     string
+	}{
+		{"not targeted", nil, statusDelivered, nil, ""},
+		{"target succeeded", &contracts.DestinationMessengerMessageExecuted{Success: true}, statusDelivered, ptr(true), "0x"},
+		{"target reverted", &contracts.DestinationMessengerMessageExecuted{ReturnData: common.FromHex("0x08c379a0")}, statusFailed, ptr(false), "0x08c379a0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &delivery{received: received, executed: tt.executed}
+			if got := d.status(); got != tt.want {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+			r := d.result(1100, 1000)
+			if r.LatencySeconds != 100 {
+				t.Errorf("latency = %d, want 100", r.LatencySeconds)
+			}
+			if (r.Executed == nil) != (tt.result == nil) || (r.Executed != nil && *r.Executed != *tt.result) || r.ReturnData != tt.data {
+				t.Errorf("result executed = %v, return data %q; want %v, %q", r.Executed, r.ReturnData, tt.result, tt.data)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	payloadHex  string
+	payloadFile string
+	abiSig      string
+	abiArgs     string
+)
+
+// readPayload returns the message payload from whichever of --message,
+// --payload-hex, --payload-file or --abi was given.
+func readPayload() ([]byte, error) {
+	switch {
+	case payloadHex != "":
+		data, err := hexutil.Decode(ensure0x(strings.TrimSpace(payloadHex)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --payload-hex: %w", err)
+		}
+		return data, nil
+	case payloadFile == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload from stdin: %w", err)
+		}
+		return data, nil
+	case payloadFile != "":
+		data, err := os.ReadFile(payloadFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload file: %w", err)
+		}
+		return data, nil
+	case abiSig != "":
+		return encodeCall(abiSig, abiArgs)
+	case message != "":
+		return []byte(message), nil
+	default:
+		return nil, fmt.Errorf("a payload is required: use --message, --payload-hex, --payload-file or --abi")
+	}
+}
+
+func ensure0x(s string) string {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return s
+	}
+	return "0x" + s
+}
+
+// parseSignature parses a function signature such as
+// "function transfer(address to, uint256 amount)". Parameter names and the
+// function keyword are optional.
+func parseSignature(sig string) (abi.Method, error) {
+	sig = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sig), "function "))
+	open, close := strings.Index(sig, "("), strings.LastIndex(sig, ")")
+	if open <= 0 || close < open {
+		return abi.Method{}, fmt.Errorf("invalid signature %q, want e.g. 'foo(uint256,address)'", sig)
+	}
+
+	// Keep only the type of each parameter, as the selector parser expects
+	var types []string
+	for _, param := range splitTopLevel(sig[open+1 : close]) {
+		if fields := strings.Fields(param); len(fields) > 0 {
+			types = append(types, fields[0])
+		}
+	}
+	selector, err := abi.ParseSelector(strings.TrimSpace(sig[:open]) + "(" + strings.Join(types, ",") + ")")
+	if err != nil {
+		return abi.Method{}, err
+	}
+
+	inputs := make(abi.Arguments, len(selector.Inputs))
+	for i, in := range selector.Inputs {
+		t, err := abi.NewType(in.Type, in.InternalType, in.Components)
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("invalid type %s: %w", in.Type, err)
+		}
+		inputs[i] = abi.Argument{Type: t}
+	}
+	return abi.NewMethod(selector.Name, selector.Name, abi.Function, "", false, false, inputs, nil), nil
+}
+
+// encodeCall ABI-encodes a call to sig with the comma-separated args,
+// selector first, as a contract would receive it.
+func encodeCall(sig, args string) ([]byte, error) {
+	method, err := parseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	if strings.TrimSpace(args) != "" {
+		values = splitTopLevel(args)
+	}
+	if len(values) != len(method.Inputs) {
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", method.Sig, len(method.Inputs), len(values))
+	}
+
+	parsed := make([]any, len(values))
+	for i, value := range values {
+		if parsed[i], err = parseArg(method.Inputs[i].Type, value); err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i+1, method.Inputs[i].Type, err)
+		}
+	}
+	packed, err := method.Inputs.Pack(parsed...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", method.Sig, err)
+	}
+	return append(method.ID, packed...), nil
+}
+
+// decodeCall decodes data encoded by encodeCall for sig and returns each
+// argument formatted for display.
+func decodeCall(sig string, data []byte) ([]string, error) {
+	method, err := parseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, fmt.Errorf("payload is not a call to %s", method.Sig)
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", method.Sig, err)
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = fmt.Sprintf("%s: %s", method.Inputs[i].Type, formatValue(v))
+	}
+	return out, nil
+}
+
+// parseArg converts a command-line value to the Go type go-ethereum packs
+// for t.
+func parseArg(t abi.Type, value string) (any, error) {
+	value = strings.TrimSpace(value)
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return nil, fmt.Errorf("%s is negative", value)
+		}
+		goType := t.GetType()
+		if goType == reflect.TypeOf(n) {
+			return n, nil
+		}
+		// Sizes with a native Go type must be passed as that type
+		v := reflect.New(goType).Elem()
+		if t.T == abi.UintTy {
+			if !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+				return nil, fmt.Errorf("%s overflows %s", value, t)
+			}
+			v.SetUint(n.Uint64())
+		} else {
+			if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+				return nil, fmt.Errorf("%s overflows %s", value, t)
+			}
+			v.SetInt(n.Int64())
+		}
+		return v.Interface(), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(value)
+	case abi.StringTy:
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted, nil
+		}
+		return value, nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(value) {
+			return nil, fmt.Errorf("%q is not an address", value)
+		}
+		return common.HexToAddress(value), nil
+	case abi.BytesTy:
+		return hexutil.Decode(ensure0x(value))
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(ensure0x(value))
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("want %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v.Interface(), nil
+	case abi.SliceTy, abi.ArrayTy:
+		inner := strings.TrimSpace(value)
+		if !strings.HasPrefix(inner, "[") || !strings.HasSuffix(inner, "]") {
+			return nil, fmt.Errorf("%q is not a list like [a,b]", value)
+		}
+		var items []string
+		if inner = inner[1 : len(inner)-1]; strings.TrimSpace(inner) != "" {
+			items = splitTopLevel(inner)
+		}
+		if t.T == abi.ArrayTy && len(items) != t.Size {
+			return nil, fmt.Errorf("want %d items, got %d", t.Size, len(items))
+		}
+
+		v := reflect.New(t.GetType()).Elem()
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		}
+		for i, item := range items {
+			elem, err := parseArg(*t.Elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			v.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return v.Interface(), nil
+	default:
+		return nil, fmt.Errorf("%s arguments are not supported; use --payload-hex", t)
+	}
+}
+
+// splitTopLevel splits s on commas outside brackets, parentheses and
+// double quotes.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start, quoted := 0, 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' && (i == 0 || s[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// formatValue renders a decoded ABI value, with addresses checksummed and
+// bytes in hex.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case *big.Int:
+		return v.String()
+	case string:
+		return strconv.Quote(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = formatValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+// printableText returns data as text if it is printable UTF-8.
+func printableText(data []byte) (string, bool) {
+	if len(data) == 0 || !utf8.Valid(data) || strings.IndexFunc(string(data), func(r rune) bool {
+		return r < ' ' && r != '\n' && r != '\t'
+	}) >= 0 {
+		return "", false
+	}
+	return string(data), true
+}
+
+// describePayload shows a payload as text when it is printable UTF-8, and
+// as hex otherwise.
+func describePayload(data []byte) string {
+	if text, ok := printableText(data); ok {
+		return strconv.Quote(text)
+	}
+	return hexutil.Encode(data)
+}
+
+// payloadResult is a payload in command output. Decoded holds the
+// arguments when --abi is given and matches.
+type payloadResult struct {
+	Hex         string   `json:"hex" yaml:"hex"`
+	Text        string   `json:"text,omitempty" yaml:"text,omitempty"`
+	Size        int      `json:"size" yaml:"size"`
+	Decoded     []string `json:"decoded,omitempty" yaml:"decoded,omitempty"`
+	DecodeError string   `json:"decode_error,omitempty" yaml:"decode_error,omitempty"`
+}
+
+func newPayloadResult(data []byte) *payloadResult {
+	result := &payloadResult{Hex: hexutil.Encode(data), Size: len(data)}
+	result.Text, _ = printableText(data)
+	if abiSig != "" {
+		args, err := decodeCall(abiSig, data)
+		if err != nil {
+			result.DecodeError = err.Error()
+		}
+		result.Decoded = args
+	}
+	return result
+}
+
+// print shows the payload as text if possible, then any decoded arguments.
+func (p *payloadResult) print(label string) {
+	if p.Text != "" {
+		fmt.Printf("%s: %s (%d bytes)\n", label, strconv.Quote(p.Text), p.Size)
+	} else {
+		fmt.Printf("%s: %s (%d bytes)\n", label, p.Hex, p.Size)
+	}
+	if p.DecodeError != "" {
+		fmt.Printf("Decoded: %s\n", p.DecodeError)
+	}
+	if len(p.Decoded) > 0 {
+		fmt.Printf("Decoded:\n")
+		for _, arg := range p.Decoded {
+			fmt.Printf("  %s\n", arg)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"cli/internal/config"
+)
+
+var (
+	fromChain string
+	toChain   string
+	toChains  []string
+)
+
+// profiles is the --config file, loaded on first use so commands given
+// every flag by hand need no config file.
+var profiles *config.Config
+
+func loadProfiles() (*config.Config, error) {
+	if profiles == nil {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("--from, --to and --chain need network profiles: %w", err)
+		}
+		profiles = cfg
+	}
+	return profiles, nil
+}
+
+// profileChain returns the chain named by --from or --to.
+func profileChain(name string) (*config.ChainConfig, error) {
+	cfg, err := loadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Chain(name)
+}
+
+// setDefault sets a flag from a profile unless it was given explicitly.
+func setDefault(cmd *cobra.Command, flag string, target *string, value string) {
+	if !cmd.Flags().Changed(flag) {
+		*target = value
+	}
+}
@@ -1,12 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"os/signal"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
@@ -17,6 +24,11 @@ import (
 var (
 	destChainID int64
 	message     string
+	noFee       bool
+	target      string
+	execGas     uint64
+	wait        bool
+	waitTimeout time.Duration
 )
 
 var sendCmd = &cobra.Command{
@@ -26,28 +38,143 @@ var sendCmd = &cobra.Command{
 	Example: `  messenger-cli send \
     --rpc https://sepolia // ky
     --contract 0x1234... \
-    --key YOUR_PRIVATE_KEY \
+    --keystore ~/.ethereum/keystore/UTC--... \
     --dest-chain 80001 \
-    --message "Hello!"`,
+    --message "Hello!"
+
+  # With chains from --config
+  messenger-cli send --from sepolia --to amoy --key-env PRIVATE_KEY --message "Hello!"
+
+  # Block until the message is delivered
+  messenger-cli send ... \
+    --wait \
+    --dest-rpc https://rpc-amoy.polygon.technology \
+    --dest-contract 0x5678...
+
+  # An ABI-encoded call as the payload
+  messenger-cli send ... --abi 'function mint(address to, uint256 amount)' --args 0xabcd...,1000`,
 	RunE: runSend,
 }
 
 func init() {
 	sendCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of source chain (required)")
 	sendCmd.Flags().StringVar(&contractAddr, "contract", "", "Source messenger contract address (required)")
-	sendCmd.Flags().StringVar(&privateKey, "key", "", "Private key (required)")
 	sendCmd.Flags().Int64Var(&destChainID, "dest-chain", 0, "Destination chain ID (required)")
-	sendCmd.Flags().StringVar(&message, "message", "", "Message to send (required)")
+	sendCmd.Flags().StringVar(&message, "message", "", "Message to send, as UTF-8 text")
+	sendCmd.Flags().StringVar(&payloadHex, "payload-hex", "", "Payload as hex bytes, instead of --message")
+	sendCmd.Flags().StringVar(&payloadFile, "payload-file", "", "Read the payload from a file, or from stdin with -")
+	sendCmd.Flags().StringVar(&abiSig, "abi", "", "ABI-encode the payload as a call to this function signature, e.g. 'foo(uint256,address)'")
+	sendCmd.Flags().StringVar(&abiArgs, "args", "", "Comma-separated arguments for --abi; lists as [a,b]")
+	sendCmd.Flags().BoolVar(&noFee, "no-fee", false, "Use the free sendMessage (relayers may not deliver it)")
+	sendCmd.Flags().StringVar(&target, "target", "", "Receiver contract to call with onMessage on the destination chain")
+	sendCmd.Flags().Uint64Var(&execGas, "exec-gas", 200000, "Gas limit for the receiver call (with --target)")
+	sendCmd.Flags().BoolVar(&wait, "wait", false, "Wait until the message is delivered on the destination chain")
+	sendCmd.Flags().StringVar(&destRPC, "dest-rpc", "", "Destination chain RPC URL (with --wait)")
+	sendCmd.Flags().StringVar(&destContract, "dest-contract", "", "Destination messenger contract address (with --wait)")
+	sendCmd.Flags().DurationVar(&waitTimeout, "timeout", 30*time.Minute, "How long to wait for delivery (with --wait)")
+
+	sendCmd.Flags().StringVar(&fromChain, "from", "", "Source chain from --config, instead of --rpc and --contract")
+	sendCmd.Flags().StringVar(&toChain, "to", "", "Destination chain from --config, instead of --dest-chain, --dest-rpc and --dest-contract")
+
+	sendCmd.MarkFlagsMutuallyExclusive("message", "payload-hex", "payload-file", "abi")
+	addSignerFlags(sendCmd)
+}
+
+// applySendProfiles fills in flags not given from the --from and --to
+// profiles. It returns the chain ID --rpc is expected to serve, or zero if
+// there is no --from profile.
+func applySendProfiles(cmd *cobra.Command) (int64, error) {
+	var sourceChainID int64
+	if fromChain != "" {
+		chain, err := profileChain(fromChain)
+		if err != nil {
+			return 0, err
+		}
+		setDefault(cmd, "rpc", &rpcURL, chain.GetRpcURL())
+		setDefault(cmd, "contract", &contractAddr, chain.SourceContract)
+		sourceChainID = chain.ChainID
+	}
+	if toChain != "" {
+		chain, err := profileChain(toChain)
+		if err != nil {
+			return 0, err
+		}
+		if !cmd.Flags().Changed("dest-chain") {
+			destChainID = chain.ChainID
+		}
+		setDefault(cmd, "dest-rpc", &destRPC, chain.GetRpcURL())
+		setDefault(cmd, "dest-contract", &destContract, chain.DestContract)
+	}
 
-	sendCmd.MarkFlagRequired("rpc")
-	sendCmd.MarkFlagRequired("contract")
-	sendCmd.MarkFlagRequired("key")
-	sendCmd.MarkFlagRequired("dest-chain")
-	sendCmd.MarkFlagRequired("message")
+	switch {
+	case rpcURL == "":
+		return 0, fmt.Errorf("--rpc, or --from with a chain whose rpc_url is set, is required")
+	case contractAddr == "":
+		return 0, fmt.Errorf("--contract, or --from with a chain whose source_contract is set, is required")
+	case destChainID == 0:
+		return 0, fmt.Errorf("--dest-chain or --to is required")
+	}
+	return sourceChainID, nil
+}
+
+// sendResult is the output of send. Delivery is set once --wait sees the
+// message delivered.
+type sendResult struct {
+	SourceChain int64           `json:"source_chain" yaml:"source_chain"`
+	DestChain   int64           `json:"dest_chain" yaml:"dest_chain"`
+	TxHash      string          `json:"tx_hash" yaml:"tx_hash"`
+	Block       uint64          `json:"block" yaml:"block"`
+	Nonce       string          `json:"nonce" yaml:"nonce"`
+	MessageHash string          `json:"message_hash" yaml:"message_hash"`
+	SentAt      *time.Time      `json:"sent_at" yaml:"sent_at"`
+	Fee         string          `json:"fee_wei,omitempty" yaml:"fee_wei,omitempty"`
+	Target      string          `json:"target,omitempty" yaml:"target,omitempty"`
+	Payload     *payloadResult  `json:"payload" yaml:"payload"`
+	Status      string          `json:"status" yaml:"status"`
+	Delivery    *deliveryResult `json:"delivery,omitempty" yaml:"delivery,omitempty"`
+}
+
+// deliveryResult is where and when a message was delivered. For a
+// targeted message it also holds whether the target call succeeded and
+// what it returned, the revert data if it failed.
+type deliveryResult struct {
+	TxHash         string     `json:"tx_hash" yaml:"tx_hash"`
+	Block          uint64     `json:"block" yaml:"block"`
+	DeliveredAt    *time.Time `json:"delivered_at" yaml:"delivered_at"`
+	LatencySeconds int64      `json:"latency_seconds,omitempty" yaml:"latency_seconds,omitempty"`
+	Executed       *bool      `json:"executed,omitempty" yaml:"executed,omitempty"`
+	ReturnData     string     `json:"return_data,omitempty" yaml:"return_data,omitempty"`
 }
 
 func runSend(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	profileChainID, err := applySendProfiles(cmd)
+	if err != nil {
+		return err
+	}
+	payload, err := readPayload()
+	if err != nil {
+		return err
+	}
+	if abiArgs != "" && abiSig == "" {
+		return fmt.Errorf("--args requires --abi")
+	}
+
+	// Connect to the destination first so no delivery can be missed
+	var destClient *ethclient.Client
+	var destFromBlock uint64
+	if wait {
+		if destRPC == "" || !common.IsHexAddress(destContract) {
+			return fmt.Errorf("--wait requires --dest-rpc and a valid --dest-contract")
+		}
+		destClient, destFromBlock, err = connectDest(ctx)
+		if err != nil {
+			return err
+		}
+		defer destClient.Close()
+	}
 
 	// Connect to chain
 	client, err := ethclient.Dial(rpcURL)
@@ -61,17 +188,14 @@ func runSend(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get chain ID: %w", err)
 	}
-
-	fmt.Printf("Connected to chain ID: %s\n", chainID.String())
-
-	// Parse private key
-	key, err := crypto.HexToECDSA(privateKey)
-	if err != nil {
-		return fmt.Errorf("invalid private key: %w", err)
+	if profileChainID != 0 && chainID.Int64() != profileChainID {
+		return fmt.Errorf("RPC for %s serves chain %s, not %d", fromChain, chainID.String(), profileChainID)
 	}
 
+	progressf("Connected to chain ID: %s\n", chainID.String())
+
 	// Create transactor
-	auth, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	auth, err := newTransactor(chainID)
 	if err != nil {
 		return fmt.Errorf("failed to create transactor: %w", err)
 	}
@@ -85,36 +209,219 @@ func runSend(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load contract: %w", err)
 	}
 
-	fmt.Printf("\n Sending message to chain %d...\n", destChainID)
-	fmt.Printf("Message: %s\n\n", message)
+	result := &sendResult{
+		SourceChain: chainID.Int64(),
+		DestChain:   destChainID,
+		Payload:     newPayloadResult(payload),
+	}
+	progressf("\n Sending message to chain %d...\n", destChainID)
+	progressf("Payload: %s (%d bytes)\n\n", describePayload(payload), len(payload))
+
+	// Send message, paying the quoted relay fee unless disabled
+	var tx *types.Transaction
+	if target != "" {
+		if !common.IsHexAddress(target) {
+			return fmt.Errorf("invalid target address: %s", target)
+		}
+		targetAddr := common.HexToAddress(target)
 
-	// Send message
-	tx, err := contract.SendMessage(
-		auth,
-		big.NewInt(destChainID),
-		[]byte(message),
-	)
+		// The contract quotes on the encoded envelope, not the raw data
+		envelope, err := encodeTargetPayload(targetAddr, execGas, payload)
+		if err != nil {
+			return err
+		}
+		fee, err := contract.QuoteFee(&bind.CallOpts{Context: ctx}, big.NewInt(destChainID), envelope)
+		if err != nil {
+			return fmt.Errorf("failed to quote fee: %w", err)
+		}
+		progressf("Target: %s (gas %d)\n", targetAddr.Hex(), execGas)
+		progressf("Fee: %s wei\n\n", fee.String())
+		result.Target = targetAddr.Hex()
+		result.Fee = fee.String()
+
+		auth.Value = fee
+		tx, err = contract.SendMessageToTarget(
+			auth,
+			big.NewInt(destChainID),
+			targetAddr,
+			new(big.Int).SetUint64(execGas),
+			payload,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	} else if noFee {
+		tx, err = contract.SendMessage(
+			auth,
+			big.NewInt(destChainID),
+			payload,
+		)
+	} else {
+		var fee *big.Int
+		fee, err = contract.QuoteFee(&bind.CallOpts{Context: ctx}, big.NewInt(destChainID), payload)
+		if err != nil {
+			return fmt.Errorf("failed to quote fee: %w", err)
+		}
+		progressf("Fee: %s wei\n\n", fee.String())
+		result.Fee = fee.String()
+
+		auth.Value = fee
+		tx, err = contract.SendMessageWithFee(
+			auth,
+			big.NewInt(destChainID),
+			payload,
+		)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
-	fmt.Printf(" Transaction sent!\n")
-	fmt.Printf("Tx Hash: %s\n", tx.Hash().Hex())
+	progressf(" Transaction sent!\n")
+	progressf("Tx Hash: %s\n", tx.Hash().Hex())
+	result.TxHash = tx.Hash().Hex()
 
 	// Wait for receipt
-	fmt.Printf("\n Waiting for confirmation...\n")
+	progressf("\n Waiting for confirmation...\n")
 	receipt, err := bind.WaitMined(ctx, client, tx)
 	if err != nil {
 		return fmt.Errorf("transaction failed: %w", err)
 	}
 
-	if receipt.Status == 1 {
-		fmt.Printf(" Transaction confirmed in block %d\n", receipt.BlockNumber.Uint64())
-		fmt.Printf("\n Message sent successfully!\n")
-		fmt.Printf("The relayer will now pick it up and deliver it to chain %d\n", destChainID)
-	} else {
-		return fmt.Errorf("transaction reverted")
+	if receipt.Status != 1 {
+		return exitWith(cmd, exitFailed, fmt.Sprintf("transaction %s reverted", tx.Hash().Hex()))
+	}
+	progressf(" Transaction confirmed in block %d\n", receipt.BlockNumber.Uint64())
+
+	sent, err := findMessageSent(receipt, common.HexToAddress(contractAddr), contract)
+	if err != nil {
+		return err
+	}
+	hash := computeMessageHash(sent, chainID)
+	result.Block = receipt.BlockNumber.Uint64()
+	result.Nonce = sent.Nonce.String()
+	result.MessageHash = hash.Hex()
+	result.SentAt = unixTime(sent.Timestamp.Int64())
+	result.Status = statusSent
+
+	if !wait {
+		return printResult(result, func() {
+			fmt.Printf("\n Message sent successfully!\n")
+			fmt.Printf("Nonce: %s\n", result.Nonce)
+			fmt.Printf("Message Hash: %s\n", result.MessageHash)
+			fmt.Printf("Destination: chain %d\n", result.DestChain)
+			fmt.Printf("The relayer will now pick it up and deliver it to chain %d\n", destChainID)
+			fmt.Printf("Check delivery with: messenger-cli status --rpc <dest-rpc> --contract <dest-contract> --hash %s\n", result.MessageHash)
+		})
+	}
+
+	progressf("\n Message sent successfully!\n")
+	progressf("Nonce: %s\n", result.Nonce)
+	progressf("Message Hash: %s\n", result.MessageHash)
+	progressf("Destination: chain %d\n", result.DestChain)
+	progressf("\n Waiting for delivery on chain %d...\n", destChainID)
+	waitCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	found, err := waitForDelivery(waitCtx, destClient, common.HexToAddress(destContract), hash, destFromBlock)
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		result.Status = statusPending
+		if err := printResult(result, func() {
+			fmt.Printf("Status: Pending\n")
+		}); err != nil {
+			return err
+		}
+		return exitWith(cmd, exitPending, fmt.Sprintf("message not delivered within %s", waitTimeout))
+	}
+
+	// Latency is measured between the source and destination block times
+	header, err := destClient.HeaderByHash(ctx, found.received.Raw.BlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to get destination block: %w", err)
 	}
+	result.Status = found.status()
+	result.Delivery = found.result(header.Time, sent.Timestamp.Int64())
 
+	if err := printResult(result, func() {
+		if result.Status == statusFailed {
+			fmt.Printf(" Message delivered, but the target call failed\n")
+		} else {
+			fmt.Printf(" Message delivered!\n")
+		}
+		fmt.Printf("Destination Tx: %s\n", result.Delivery.TxHash)
+		fmt.Printf("Destination Block: %d\n", result.Delivery.Block)
+		fmt.Printf("End-to-end latency: %s\n", time.Duration(result.Delivery.LatencySeconds)*time.Second)
+		result.Delivery.printExecution()
+	}); err != nil {
+		return err
+	}
+	if result.Status == statusFailed {
+		return exitWith(cmd, exitFailed, "target call failed")
+	}
 	return nil
 }
+
+// connectDest dials the destination chain for --wait, checks it is the
+// chain given by --dest-chain and returns its current head, from which
+// delivery is searched.
+func connectDest(ctx context.Context) (*ethclient.Client, uint64, error) {
+	client, err := ethclient.DialContext(ctx, destRPC)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to connect to destination RPC: %w", err)
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, 0, fmt.Errorf("failed to get destination chain ID: %w", err)
+	}
+	if chainID.Int64() != destChainID {
+		client.Close()
+		return nil, 0, fmt.Errorf("--dest-rpc is chain %s, not --dest-chain %d", chainID.String(), destChainID)
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		client.Close()
+		return nil, 0, fmt.Errorf("failed to get destination block number: %w", err)
+	}
+	return client, head, nil
+}
+
+// targetedPayloadTag starts every payload sent with
+// SourceMessenger.sendMessageToTarget (TARGETED_PAYLOAD in IMessenger.sol).
+var targetedPayloadTag = crypto.Keccak256([]byte("TargetedMessage(address,uint256,bytes)"))[:4]
+
+// targetPayloadArgs is the abi.encode(target, gasLimit, data) envelope of
+// SourceMessenger.sendMessageToTarget, after the tag.
+func targetPayloadArgs() abi.Arguments {
+	addressType, _ := abi.NewType("address", "", nil)
+	uintType, _ := abi.NewType("uint256", "", nil)
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: addressType}, {Type: uintType}, {Type: bytesType}}
+}
+
+// encodeTargetPayload mirrors abi.encodePacked(TARGETED_PAYLOAD,
+// abi.encode(target, gasLimit, data)) in SourceMessenger.sendMessageToTarget.
+func encodeTargetPayload(target common.Address, gasLimit uint64, data []byte) ([]byte, error) {
+	encoded, err := targetPayloadArgs().Pack(target, new(big.Int).SetUint64(gasLimit), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return append(append([]byte{}, targetedPayloadTag...), encoded...), nil
+}
+
+// decodeTargetPayload returns the receiver data inside an envelope built by
+// encodeTargetPayload.
+func decodeTargetPayload(envelope []byte) ([]byte, error) {
+	if !bytes.HasPrefix(envelope, targetedPayloadTag) {
+		return nil, fmt.Errorf("failed to decode target payload: missing targeted message tag")
+	}
+	values, err := targetPayloadArgs().Unpack(envelope[len(targetedPayloadTag):])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode target payload: %w", err)
+	}
+	return values[2].([]byte), nil
+}
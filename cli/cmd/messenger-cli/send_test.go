@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestTargetPayloadRoundTrip(t *testing.T) {
+	target := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"short", []byte("hi")},
+		{"one word", bytes.Repeat([]byte{0xab}, 32)},
+		{"over a word", bytes.Repeat([]byte{0xcd}, 33)},
+		{"call", hexutil.MustDecode("0x40c10f190000000000000000000000005fbdb2315678afecb367f032d93f642f64180aa300000000000000000000000000000000000000000000000000000000000003e8")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := encodeTargetPayload(target, 200000, tt.data)
+			if err != nil {
+				t.Fatalf("encodeTargetPayload: %v", err)
+			}
+			// bytes4(keccak256("TargetedMessage(address,uint256,bytes)"))
+			if !bytes.HasPrefix(envelope, hexutil.MustDecode("0x21c74917")) {
+				t.Errorf("envelope starts with %x, want the TARGETED_PAYLOAD tag", envelope[:4])
+			}
+			// abi.encode(address, uint256, bytes) is word aligned after the tag
+			if (len(envelope)-4)%32 != 0 {
+				t.Errorf("envelope is %d bytes, want a whole number of words after the tag", len(envelope))
+			}
+
+			data, err := decodeTargetPayload(envelope)
+			if err != nil {
+				t.Fatalf("decodeTargetPayload: %v", err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Errorf("decodeTargetPayload = %x, want %x", data, tt.data)
+			}
+		})
+	}
+}
+
+func TestDecodeTargetPayloadErrors(t *testing.T) {
+	envelope, err := encodeTargetPayload(common.HexToAddress("0x1"), 1, []byte("data"))
+	if err != nil {
+		t.Fatalf("encodeTargetPayload: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		envelope []byte
+		want     string
+	}{
+		{"plain payload", []byte("Hello"), "missing targeted message tag"},
+		{"tag only", envelope[:4], "failed to decode target payload"},
+		{"data cut off", envelope[:len(envelope)-32], "failed to decode target payload"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeTargetPayload(tt.envelope)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("decodeTargetPayload error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
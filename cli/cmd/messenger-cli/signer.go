@@ -0,0 +1,179 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	insecureKey   string
+	removedKey    string
+	keystorePath  string
+	passwordFile  string
+	keyEnv        string
+	keyFD         int
+	signerURL     string
+	signerAccount string
+)
+
+// addSignerFlags registers the ways a command can be given its signing key.
+// Exactly one must be used.
+func addSignerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&keystorePath, "keystore", "", "Encrypted keystore JSON file; the passphrase is prompted for")
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "File holding the keystore passphrase, instead of prompting")
+	cmd.Flags().StringVar(&keyEnv, "key-env", "", "Environment variable holding the hex private key")
+	cmd.Flags().IntVar(&keyFD, "key-fd", -1, "File descriptor to read the hex private key from, e.g. 3 with 3<key.txt")
+	cmd.Flags().StringVar(&signerURL, "signer", "", "External signer URL, such as clef's http://localhost:8550 or an IPC path")
+	cmd.Flags().StringVar(&signerAccount, "signer-account", "", "Account to use on the external signer (default: its first account)")
+	cmd.Flags().StringVar(&insecureKey, "insecure-key", "", "Raw hex private key; visible in shell history and ps, use only for local testing")
+
+	// Point users of the old flag at the alternatives rather than accepting it
+	cmd.Flags().StringVar(&removedKey, "key", "", "Removed; see --keystore, --key-env, --key-fd, --signer and --insecure-key")
+	cmd.Flags().MarkHidden("key")
+
+	cmd.MarkFlagsMutuallyExclusive("keystore", "key-env", "key-fd", "signer", "insecure-key", "key")
+}
+
+// newTransactor returns transact options signing with the key chosen on
+// the command line.
+func newTransactor(chainID *big.Int) (*bind.TransactOpts, error) {
+	switch {
+	case removedKey != "":
+		return nil, fmt.Errorf("--key has been removed since it exposes the key in shell history; use --keystore, --key-env, --key-fd or --signer, or --insecure-key for local testing")
+	case signerURL != "":
+		return externalTransactor(chainID)
+	}
+
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewKeyedTransactorWithChainID(key, chainID)
+}
+
+// loadKey reads the private key from whichever local source was chosen.
+func loadKey() (*ecdsa.PrivateKey, error) {
+	switch {
+	case keystorePath != "":
+		return loadKeystore()
+	case keyEnv != "":
+		value, ok := os.LookupEnv(keyEnv)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", keyEnv)
+		}
+		return parseKey(value)
+	case keyFD >= 0:
+		f := os.NewFile(uintptr(keyFD), "key-fd")
+		if f == nil {
+			return nil, fmt.Errorf("invalid file descriptor %d", keyFD)
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key from fd %d: %w", keyFD, err)
+		}
+		return parseKey(string(data))
+	case insecureKey != "":
+		return parseKey(insecureKey)
+	default:
+		return nil, fmt.Errorf("a signing key is required: use --keystore, --key-env, --key-fd, --signer or --insecure-key")
+	}
+}
+
+// parseKey accepts a hex private key with or without 0x and surrounding
+// whitespace, as found in env vars and files.
+func parseKey(value string) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(value), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return key, nil
+}
+
+func loadKeystore() (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	passphrase, err := readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	return key.PrivateKey, nil
+}
+
+// readPassphrase reads the keystore passphrase from --password-file, or
+// prompts for it without echo.
+func readPassphrase() (string, error) {
+	if passwordFile != "" {
+		data, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("cannot prompt for the keystore passphrase without a terminal; use --password-file")
+	}
+	fmt.Fprintf(os.Stderr, "Passphrase for %s: ", keystorePath)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
+
+// externalTransactor signs through an external signer such as clef, so the
+// key never reaches this process.
+func externalTransactor(chainID *big.Int) (*bind.TransactOpts, error) {
+	signer, err := external.NewExternalSigner(signerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to signer: %w", err)
+	}
+
+	var account accounts.Account
+	if signerAccount != "" {
+		if !common.IsHexAddress(signerAccount) {
+			return nil, fmt.Errorf("invalid signer account: %s", signerAccount)
+		}
+		account = accounts.Account{Address: common.HexToAddress(signerAccount)}
+	} else {
+		accts := signer.Accounts()
+		if len(accts) == 0 {
+			return nil, fmt.Errorf("signer %s lists no accounts; set --signer-account", signerURL)
+		}
+		account = accts[0]
+	}
+
+	return &bind.TransactOpts{
+		From: account.Address,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != account.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(account, tx, chainID)
+		},
+	}, nil
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
@@ -12,67 +16,461 @@ import (
 )
 
 var (
-	messageHash  string
-	destRPC      string
-	destContract string
+	messageHash    string
+	destRPC        string
+	destContract   string
+	destRPCs       []string
+	destContracts  []string
+	sourceTx       string
+	sourceRPC      string
+	sourceContract string
+	statusSince    time.Duration
 )
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Check message status",
-	Long:  "Check if a message has been processed on the destination chain",
+	Long: `Check if a message has been processed on the destination chain.
+
+Look a message up by its hash, or by the source transaction that sent it.
+With --source-tx every message sent in the transaction is reported, with
+its timeline from source to destination. Repeat --rpc and --contract, in
+pairs, to check messages sent to several destination chains.
+
+A targeted message whose target call reverted is reported as failed, with
+the call's return data. For --hash the delivery is searched for within
+--since.`,
 	Example: `  messenger-cli status \
     --rpc https://Amoy.polygonscan.com/... \
     --contract 0x5678... \
-    --hash 0xabcd...`,
+    --hash 0xabcd...
+
+  messenger-cli status \
+    --source-rpc https://sepolia... \
+    --source-tx 0x9876... \
+    --rpc https://Amoy.polygonscan.com/... \
+    --contract 0x5678...
+
+  # With chains from --config
+  messenger-cli status --from sepolia --source-tx 0x9876...`,
 	RunE: runStatus,
 }
 
 func init() {
-	statusCmd.Flags().StringVar(&destRPC, "rpc", "", "Destination chain RPC URL (required)")
-	statusCmd.Flags().StringVar(&destContract, "contract", "", "Destination contract address (required)")
-	statusCmd.Flags().StringVar(&messageHash, "hash", "", "Message hash to check (required)")
+	statusCmd.Flags().StringArrayVar(&destRPCs, "rpc", nil, "Destination chain RPC URL (required, repeatable)")
+	statusCmd.Flags().StringArrayVar(&destContracts, "contract", nil, "Destination contract address, one per --rpc (required)")
+	statusCmd.Flags().StringVar(&messageHash, "hash", "", "Message hash to check")
+	statusCmd.Flags().DurationVar(&statusSince, "since", 7*24*time.Hour, "How far back to look for the delivery of a --hash")
+	statusCmd.Flags().StringVar(&sourceTx, "source-tx", "", "Source transaction that sent the message(s), instead of --hash")
+	statusCmd.Flags().StringVar(&sourceRPC, "source-rpc", "", "Source chain RPC URL (with --source-tx)")
+	statusCmd.Flags().StringVar(&sourceContract, "source-contract", "", "Only report messages sent by this source contract (with --source-tx)")
+
+	statusCmd.Flags().StringVar(&abiSig, "abi", "", "Decode payloads as calls to this function signature (with --source-tx)")
+	statusCmd.Flags().StringVar(&fromChain, "from", "", "Source chain from --config, instead of --source-rpc and --source-contract")
+	statusCmd.Flags().StringArrayVar(&toChains, "to", nil, "Destination chain from --config, instead of --rpc and --contract (repeatable)")
+
+	statusCmd.MarkFlagsMutuallyExclusive("hash", "source-tx")
+}
+
+// applyStatusProfiles fills in the source from --from and adds a
+// destination for each --to. Looking up a source transaction with --from
+// but no destinations checks every chain in the config.
+func applyStatusProfiles(cmd *cobra.Command) error {
+	if fromChain != "" {
+		chain, err := profileChain(fromChain)
+		if err != nil {
+			return err
+		}
+		setDefault(cmd, "source-rpc", &sourceRPC, chain.GetRpcURL())
+		setDefault(cmd, "source-contract", &sourceContract, chain.SourceContract)
+	}
+
+	to := toChains
+	if len(to) == 0 && len(destRPCs) == 0 && fromChain != "" && sourceTx != "" {
+		for _, chain := range profiles.Chains {
+			if chain.Name != "" && !strings.EqualFold(chain.Name, fromChain) {
+				to = append(to, chain.Name)
+			}
+		}
+	}
+	for _, name := range to {
+		chain, err := profileChain(name)
+		if err != nil {
+			return err
+		}
+		destRPCs = append(destRPCs, chain.GetRpcURL())
+		destContracts = append(destContracts, chain.DestContract)
+	}
+	return nil
+}
+
+// destination is a destination chain given with --rpc and --contract.
+type destination struct {
+	address  common.Address
+	client   *ethclient.Client
+	contract *contracts.DestinationMessenger
+}
 
-	statusCmd.MarkFlagRequired("rpc")
-	statusCmd.MarkFlagRequired("contract")
-	statusCmd.MarkFlagRequired("hash")
+// statusResult is the output of status: one entry per message checked.
+type statusResult struct {
+	Messages []*messageStatus `json:"messages" yaml:"messages"`
+}
+
+// messageStatus is the timeline of one message. Only Hash, Status and
+// Delivery are known for a message looked up by --hash.
+type messageStatus struct {
+	Hash         string          `json:"hash" yaml:"hash"`
+	Nonce        string          `json:"nonce,omitempty" yaml:"nonce,omitempty"`
+	Sender       string          `json:"sender,omitempty" yaml:"sender,omitempty"`
+	SourceChain  int64           `json:"source_chain,omitempty" yaml:"source_chain,omitempty"`
+	DestChain    int64           `json:"dest_chain,omitempty" yaml:"dest_chain,omitempty"`
+	SourceTx     string          `json:"source_tx,omitempty" yaml:"source_tx,omitempty"`
+	SentBlock    uint64          `json:"sent_block,omitempty" yaml:"sent_block,omitempty"`
+	SentAt       *time.Time      `json:"sent_at,omitempty" yaml:"sent_at,omitempty"`
+	Target       string          `json:"target,omitempty" yaml:"target,omitempty"`
+	GasLimit     string          `json:"gas_limit,omitempty" yaml:"gas_limit,omitempty"`
+	Payload      *payloadResult  `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Status       string          `json:"status" yaml:"status"`
+	ExecutableAt *time.Time      `json:"executable_at,omitempty" yaml:"executable_at,omitempty"`
+	Delivery     *deliveryResult `json:"delivery,omitempty" yaml:"delivery,omitempty"`
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
-	_ = context.Background() // Reserved for future use
+	ctx := context.Background()
 
+	if err := applyStatusProfiles(cmd); err != nil {
+		return err
+	}
+	if len(destRPCs) == 0 {
+		return fmt.Errorf("--rpc and --contract, or --to, are required")
+	}
+	if len(destRPCs) != len(destContracts) {
+		return fmt.Errorf("give one --contract for each --rpc")
+	}
+
+	var result *statusResult
+	var err error
+	switch {
+	case messageHash != "":
+		if len(destRPCs) != 1 {
+			return fmt.Errorf("--hash takes a single --rpc and --contract")
+		}
+		result, err = statusByHash(ctx, destRPCs[0], destContracts[0])
+	case sourceTx != "":
+		if sourceRPC == "" {
+			return fmt.Errorf("--source-tx requires --source-rpc, or --from with a chain whose rpc_url is set")
+		}
+		result, err = statusBySourceTx(ctx)
+	default:
+		return fmt.Errorf("either --hash or --source-tx is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := printResult(result, result.print); err != nil {
+		return err
+	}
+	statuses := make([]string, len(result.Messages))
+	for i, m := range result.Messages {
+		statuses[i] = m.Status
+	}
+	switch statusExitCode(statuses...) {
+	case exitFailed:
+		return exitWith(cmd, exitFailed, "delivery failed")
+	case exitPending:
+		return exitWith(cmd, exitPending, "not delivered yet")
+	}
+	return nil
+}
+
+func statusByHash(ctx context.Context, rpcURL, contractAddr string) (*statusResult, error) {
 	// Connect to destination chain
-	client, err := ethclient.Dial(destRPC)
+	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
-		return fmt.Errorf("failed to connect to RPC: %w", err)
+		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
 	defer client.Close()
 
 	// Load contract
 	contract, err := contracts.NewDestinationMessenger(
-		common.HexToAddress(destContract),
+		common.HexToAddress(contractAddr),
 		client,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to load contract: %w", err)
+		return nil, fmt.Errorf("failed to load contract: %w", err)
 	}
 
 	// Check if processed
 	hash := common.HexToHash(messageHash)
-	processed, err := contract.IsProcessed(nil, hash)
+	opts := &bind.CallOpts{Context: ctx}
+	processed, err := contract.IsProcessed(opts, hash)
 	if err != nil {
-		return fmt.Errorf("failed to check status: %w", err)
+		return nil, fmt.Errorf("failed to check status: %w", err)
 	}
 
-	fmt.Printf("\n Message Status\n")
-	fmt.Printf("─────────────────\n")
-	fmt.Printf("Hash: %s\n", messageHash)
+	m := &messageStatus{Hash: hash.Hex(), Status: statusDelivered}
+	if !processed {
+		if m.Status, m.ExecutableAt, err = undeliveredStatus(opts, contract, hash); err != nil {
+			return nil, err
+		}
+		return &statusResult{Messages: []*messageStatus{m}}, nil
+	}
 
-	if processed {
-		fmt.Printf("Status: Processed\n")
-	} else {
-		fmt.Printf("Status: Pending\n")
+	// The send time is unknown, so look for the delivery within --since
+	from, err := blockAtTime(ctx, client, uint64(time.Now().Add(-statusSince).Unix()))
+	if err != nil {
+		return nil, err
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination block number: %w", err)
+	}
+	found, err := findDelivery(ctx, client, common.HexToAddress(contractAddr), hash, from, head)
+	if err != nil || found == nil {
+		return &statusResult{Messages: []*messageStatus{m}}, err
 	}
+	header, err := client.HeaderByHash(ctx, found.received.Raw.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination block: %w", err)
+	}
+	m.Status = found.status()
+	// Without the send time there is no latency to report
+	m.Delivery = found.result(header.Time, int64(header.Time))
+	return &statusResult{Messages: []*messageStatus{m}}, nil
+}
 
-	return nil
+// statusBySourceTx reports every message sent in --source-tx, checking
+// each against the destination configured for its chain.
+func statusBySourceTx(ctx context.Context) (*statusResult, error) {
+	if sourceContract != "" && !common.IsHexAddress(sourceContract) {
+		return nil, fmt.Errorf("invalid source contract address: %s", sourceContract)
+	}
+
+	source, err := ethclient.DialContext(ctx, sourceRPC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to source RPC: %w", err)
+	}
+	defer source.Close()
+
+	sourceChainID, err := source.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source chain ID: %w", err)
+	}
+	receipt, err := source.TransactionReceipt(ctx, common.HexToHash(sourceTx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt for %s: %w", sourceTx, err)
+	}
+
+	// Any address works here since the filterer only decodes logs
+	decoder, err := contracts.NewSourceMessenger(common.Address{}, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract: %w", err)
+	}
+	var filter common.Address
+	if sourceContract != "" {
+		filter = common.HexToAddress(sourceContract)
+	}
+	events := messagesSent(receipt, filter, decoder)
+	targets := messagesTargeted(receipt, decoder)
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no MessageSent event in transaction %s", sourceTx)
+	}
+
+	dests, err := connectDestinations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, d := range dests {
+			d.client.Close()
+		}
+	}()
+
+	result := &statusResult{}
+	for _, event := range events {
+		target := targets[targetKey{source: event.Raw.Address, nonce: event.Nonce.String()}]
+		m, err := checkMessage(ctx, event, target, sourceChainID.Int64(), dests)
+		if err != nil {
+			return nil, err
+		}
+		result.Messages = append(result.Messages, m)
+	}
+	return result, nil
+}
+
+// connectDestinations dials every --rpc and keys it by the chain ID it
+// reports.
+func connectDestinations(ctx context.Context) (map[int64]*destination, error) {
+	dests := make(map[int64]*destination, len(destRPCs))
+	for i, url := range destRPCs {
+		if !common.IsHexAddress(destContracts[i]) {
+			return nil, fmt.Errorf("invalid destination contract address: %s", destContracts[i])
+		}
+		client, err := ethclient.DialContext(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to destination RPC: %w", err)
+		}
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to get destination chain ID: %w", err)
+		}
+
+		address := common.HexToAddress(destContracts[i])
+		contract, err := contracts.NewDestinationMessenger(address, client)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to load contract: %w", err)
+		}
+		dests[chainID.Int64()] = &destination{address: address, client: client, contract: contract}
+	}
+	return dests, nil
+}
+
+// checkMessage builds the timeline of one sent message: when it was sent
+// and, if its destination was given, whether and when it was delivered.
+func checkMessage(ctx context.Context, event *contracts.SourceMessengerMessageSent, target *contracts.SourceMessengerMessageTargeted, sourceChainID int64, dests map[int64]*destination) (*messageStatus, error) {
+	hash := computeMessageHash(event, big.NewInt(sourceChainID))
+	m := &messageStatus{
+		Hash:        hash.Hex(),
+		Nonce:       event.Nonce.String(),
+		Sender:      event.Sender.Hex(),
+		SourceChain: sourceChainID,
+		DestChain:   event.DestinationChainId.Int64(),
+		SourceTx:    event.Raw.TxHash.Hex(),
+		SentBlock:   event.Raw.BlockNumber,
+		SentAt:      unixTime(event.Timestamp.Int64()),
+	}
+
+	// Targeted messages carry the receiver call inside an envelope
+	payload := event.Payload
+	if target != nil {
+		data, err := decodeTargetPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		m.Target = target.Target.Hex()
+		m.GasLimit = target.GasLimit.String()
+		payload = data
+	}
+	m.Payload = newPayloadResult(payload)
+
+	dest, ok := dests[m.DestChain]
+	if !ok {
+		m.Status = statusUnknown
+		return m, nil
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	processed, err := dest.contract.IsProcessed(opts, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check status: %w", err)
+	}
+	if !processed {
+		if m.Status, m.ExecutableAt, err = undeliveredStatus(opts, dest.contract, hash); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	m.Status = statusDelivered
+
+	// Delivery cannot precede the send, so start looking from there
+	from, err := blockAtTime(ctx, dest.client, event.Timestamp.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	head, err := dest.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination block number: %w", err)
+	}
+	found, err := findDelivery(ctx, dest.client, dest.address, hash, from, head)
+	if err != nil || found == nil {
+		return m, err
+	}
+	header, err := dest.client.HeaderByHash(ctx, found.received.Raw.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get destination block: %w", err)
+	}
+	m.Status = found.status()
+	m.Delivery = found.result(header.Time, event.Timestamp.Int64())
+	return m, nil
+}
+
+func (r *statusResult) print() {
+	if len(r.Messages) == 1 && r.Messages[0].Nonce == "" {
+		m := r.Messages[0]
+		fmt.Printf("\n Message Status\n")
+		fmt.Printf("─────────────────\n")
+		fmt.Printf("Hash: %s\n", m.Hash)
+		fmt.Printf("Status: %s\n", statusText(m.Status, m.ExecutableAt))
+		if m.Delivery != nil {
+			fmt.Printf("Delivered: block %d at %s\n", m.Delivery.Block, formatTime(m.Delivery.DeliveredAt))
+			fmt.Printf("Destination Tx: %s\n", m.Delivery.TxHash)
+			m.Delivery.printExecution()
+		}
+		return
+	}
+
+	for i, m := range r.Messages {
+		fmt.Printf("\n Message %d of %d\n", i+1, len(r.Messages))
+		fmt.Printf("─────────────────\n")
+		fmt.Printf("Hash: %s\n", m.Hash)
+		fmt.Printf("Nonce: %s\n", m.Nonce)
+		fmt.Printf("Sender: %s\n", m.Sender)
+		fmt.Printf("Route: chain %d -> chain %d\n", m.SourceChain, m.DestChain)
+		fmt.Printf("Sent: block %d at %s\n", m.SentBlock, formatTime(m.SentAt))
+		if m.Target != "" {
+			fmt.Printf("Target: %s (gas %s)\n", m.Target, m.GasLimit)
+		}
+		m.Payload.print("Payload")
+
+		switch {
+		case m.Status == statusUnknown:
+			fmt.Printf("Status: Unknown (no --rpc given for chain %d)\n", m.DestChain)
+		case m.Status != statusDelivered && m.Status != statusFailed:
+			fmt.Printf("Status: %s\n", statusText(m.Status, m.ExecutableAt))
+		case m.Delivery == nil:
+			fmt.Printf("Status: Delivered\n")
+			fmt.Printf("Delivered: delivery log not found\n")
+		default:
+			fmt.Printf("Status: %s\n", statusText(m.Status, nil))
+			fmt.Printf("Delivered: block %d at %s\n", m.Delivery.Block, formatTime(m.Delivery.DeliveredAt))
+			fmt.Printf("Destination Tx: %s\n", m.Delivery.TxHash)
+			fmt.Printf("End-to-end latency: %s\n", time.Duration(m.Delivery.LatencySeconds)*time.Second)
+			m.Delivery.printExecution()
+		}
+	}
+}
+
+// printExecution prints the outcome of a targeted message's target call.
+func (d *deliveryResult) printExecution() {
+	switch {
+	case d.Executed == nil:
+	case *d.Executed:
+		fmt.Printf("Target call: succeeded\n")
+	default:
+		fmt.Printf("Target call: failed, returned %s\n", d.ReturnData)
+	}
+}
+
+// undeliveredStatus tells a pending message from one proposed for
+// optimistic delivery, returning when a proposed one becomes executable.
+func undeliveredStatus(opts *bind.CallOpts, contract *contracts.DestinationMessenger, hash common.Hash) (string, *time.Time, error) {
+	executableAt, err := contract.ExecutableAt(opts, hash)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to check proposal: %w", err)
+	}
+	if executableAt.Sign() == 0 {
+		return statusPending, nil, nil
+	}
+
+	challenged, err := contract.Challenged(opts, hash)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to check challenge: %w", err)
+	}
+	if challenged {
+		return statusChallenged, nil, nil
+	}
+	return statusProposed, unixTime(executableAt.Int64()), nil
 }
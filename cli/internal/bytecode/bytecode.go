@@ -0,0 +1,47 @@
+// Package bytecode embeds the compiled messenger contracts so the CLI can
+// deploy them without Foundry.
+//
+// The .bin files hold the creation bytecode as hex, written by
+// `make bytecode` from the contracts in contracts/src. Regenerate them
+// whenever the contracts change and commit them; CI fails if they are stale.
+package bytecode
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+//go:embed SourceMessenger.bin
+var sourceMessenger string
+
+//go:embed DestinationMessenger.bin
+var destinationMessenger string
+
+// SourceMessenger returns the creation bytecode of SourceMessenger.
+func SourceMessenger() ([]byte, error) {
+	return decode("SourceMessenger", sourceMessenger)
+}
+
+// DestinationMessenger returns the creation bytecode of
+// DestinationMessenger, without constructor arguments.
+func DestinationMessenger() ([]byte, error) {
+	return decode("DestinationMessenger", destinationMessenger)
+}
+
+func decode(name, bin string) ([]byte, error) {
+	bin = strings.TrimSpace(bin)
+	if bin == "" {
+		return nil, fmt.Errorf("%s bytecode is not embedded in this build; run `make bytecode` and rebuild the CLI", name)
+	}
+	if !strings.HasPrefix(bin, "0x") {
+		bin = "0x" + bin
+	}
+	code, err := hexutil.Decode(bin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedded %s bytecode: %w", name, err)
+	}
+	return code, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the part of the relayer's config.yaml the CLI uses: the chains,
+// which serve as named network profiles. Other sections are ignored, so the
+// relayer's own file can be used as is.
+type Config struct {
+	Chains []ChainConfig `yaml:"chains"`
+}
+
+// ChainConfig matches the relayer's chain schema.
+type ChainConfig struct {
+	Name           string   `yaml:"name"`
+	ChainID        int64    `yaml:"chain_id"`
+	RpcURL         string   `yaml:"rpc_url"`
+	RpcURLs        []string `yaml:"rpc_urls"`
+	SourceContract string   `yaml:"source_contract"`
+	DestContract   string   `yaml:"dest_contract"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	// Load .env file if it exists (ignore error if not found)
+	_ = godotenv.Load()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// Expand environment variables like ${VAR} or $VAR
+	expanded := os.ExpandEnv(string(data))
+
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(expanded), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Chain returns the chain named name, matched case-insensitively, or with
+// that chain ID.
+func (c *Config) Chain(name string) (*ChainConfig, error) {
+	for i := range c.Chains {
+		chain := &c.Chains[i]
+		if strings.EqualFold(chain.Name, name) || fmt.Sprint(chain.ChainID) == name {
+			return chain, nil
+		}
+	}
+
+	names := make([]string, len(c.Chains))
+	for i, chain := range c.Chains {
+		names[i] = chain.Name
+	}
+	return nil, fmt.Errorf("no chain %q in config (have: %s)", name, strings.Join(names, ", "))
+}
+
+// GetRpcURL returns the first configured RPC URL. The CLI makes few calls,
+// so it does not fail over to the others.
+func (c *ChainConfig) GetRpcURL() string {
+	if c.RpcURL != "" {
+		return c.RpcURL
+	}
+	for _, u := range c.RpcURLs {
+		if u != "" {
+			return u
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Deployment is a chain's newly deployed messenger contracts. Empty
+// addresses are left as they are in the file.
+type Deployment struct {
+	Name           string
+	ChainID        int64
+	RpcURL         string
+	SourceContract string
+	DestContract   string
+}
+
+// SaveDeployment records d's contract addresses in the config file at path,
+// in the chain entry with d's name or chain ID, adding one if there is none.
+// The file is edited in place, so comments, other settings and ${VAR}
+// references are kept. A missing file is created.
+func SaveDeployment(path string, d Deployment) error {
+	var doc yaml.Node
+	mode := fs.FileMode(0o644)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read config: %w", err)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a YAML mapping", path)
+	}
+	chains := mappingValue(root, "chains")
+	if chains == nil {
+		chains = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content, keyNode("chains"), chains)
+	}
+	if chains.Kind != yaml.SequenceNode {
+		return fmt.Errorf("chains in %s is not a list", path)
+	}
+
+	entry := findChain(chains, d)
+	if entry == nil {
+		entry = &yaml.Node{Kind: yaml.MappingNode}
+		setValue(entry, "name", stringNode(d.Name))
+		setValue(entry, "chain_id", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(d.ChainID, 10)})
+		if d.RpcURL != "" {
+			setValue(entry, "rpc_url", stringNode(d.RpcURL))
+		}
+		chains.Content = append(chains.Content, entry)
+	}
+	if d.SourceContract != "" {
+		setValue(entry, "source_contract", stringNode(d.SourceContract))
+	}
+	if d.DestContract != "" {
+		setValue(entry, "dest_contract", stringNode(d.DestContract))
+	}
+
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(out.String()), mode); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// findChain returns the entry in chains named d.Name, or else with
+// d.ChainID.
+func findChain(chains *yaml.Node, d Deployment) *yaml.Node {
+	if d.Name != "" {
+		for _, entry := range chains.Content {
+			if name := mappingValue(entry, "name"); name != nil && strings.EqualFold(name.Value, d.Name) {
+				return entry
+			}
+		}
+	}
+	for _, entry := range chains.Content {
+		if id := mappingValue(entry, "chain_id"); id != nil && id.Value == strconv.FormatInt(d.ChainID, 10) {
+			return entry
+		}
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			// Keep any comment on the old value
+			value.LineComment = node.Content[i+1].LineComment
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, keyNode(key), value)
+}
+
+// stringNode is a scalar quoted like the example configs, so addresses are
+// never read back as numbers.
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s, Style: yaml.DoubleQuotedStyle}
+}
+
+func keyNode(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
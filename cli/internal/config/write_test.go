@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `# Messenger deployments
+chains:
+  - name: "sepolia"
+    chain_id: 11155111
+    rpc_url: "https://eth-sepolia.g.alchemy.com/v2/${ALCHEMY_API_KEY}" # primary
+    source_contract: "0x0000000000000000000000000000000000000001" # old source
+    dest_contract: ""
+  - name: "amoy"
+    chain_id: 80002
+    rpc_urls:
+      - "${AMOY_RPC_URL}"
+`
+
+func writeConfig(t *testing.T, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile applies the umask
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readConfig(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSaveDeploymentUpdatesChain(t *testing.T) {
+	path := writeConfig(t, testConfig, 0o600)
+
+	err := SaveDeployment(path, Deployment{
+		Name:           "Sepolia",
+		ChainID:        11155111,
+		SourceContract: "0x00000000000000000000000000000000000000aa",
+	})
+	if err != nil {
+		t.Fatalf("SaveDeployment: %v", err)
+	}
+
+	out := readConfig(t, path)
+	for _, want := range []string{
+		"# Messenger deployments",
+		`rpc_url: "https://eth-sepolia.g.alchemy.com/v2/${ALCHEMY_API_KEY}" # primary`,
+		`source_contract: "0x00000000000000000000000000000000000000aa" # old source`,
+		`dest_contract: ""`,
+		`- "${AMOY_RPC_URL}"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("config is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "0x0000000000000000000000000000000000000001") {
+		t.Errorf("old source contract kept:\n%s", out)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestSaveDeploymentMatchesChainID(t *testing.T) {
+	path := writeConfig(t, testConfig, 0o644)
+
+	if err := SaveDeployment(path, Deployment{ChainID: 80002, DestContract: "0x00000000000000000000000000000000000000bb"}); err != nil {
+		t.Fatalf("SaveDeployment: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Chains) != 2 {
+		t.Fatalf("config has %d chains, want 2", len(cfg.Chains))
+	}
+	amoy, err := cfg.Chain("amoy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amoy.DestContract != "0x00000000000000000000000000000000000000bb" || amoy.SourceContract != "" {
+		t.Errorf("amoy contracts = %q, %q", amoy.SourceContract, amoy.DestContract)
+	}
+}
+
+func TestSaveDeploymentAddsChain(t *testing.T) {
+	path := writeConfig(t, testConfig, 0o644)
+
+	err := SaveDeployment(path, Deployment{
+		Name:           "anvil",
+		ChainID:        31337,
+		RpcURL:         "http://localhost:8545",
+		SourceContract: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		DestContract:   "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512",
+	})
+	if err != nil {
+		t.Fatalf("SaveDeployment: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Chains) != 3 {
+		t.Fatalf("config has %d chains, want 3", len(cfg.Chains))
+	}
+	anvil, err := cfg.Chain("31337")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anvil.Name != "anvil" || anvil.RpcURL != "http://localhost:8545" ||
+		anvil.SourceContract != "0x5FbDB2315678afecb367f032d93F642f64180aa3" ||
+		anvil.DestContract != "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512" {
+		t.Errorf("anvil = %+v", anvil)
+	}
+}
+
+func TestSaveDeploymentCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := SaveDeployment(path, Deployment{Name: "anvil", ChainID: 31337, SourceContract: "0x5FbDB2315678afecb367f032d93F642f64180aa3"}); err != nil {
+		t.Fatalf("SaveDeployment: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Chains) != 1 || cfg.Chains[0].ChainID != 31337 || cfg.Chains[0].SourceContract != "0x5FbDB2315678afecb367f032d93F642f64180aa3" {
+		t.Errorf("chains = %+v", cfg.Chains)
+	}
+}
+
+func TestSaveDeploymentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"invalid yaml", "chains: [", "failed to parse config"},
+		{"not a mapping", "- 1\n", "is not a YAML mapping"},
+		{"chains not a list", "chains: {}\n", "is not a list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content, 0o644)
+			err := SaveDeployment(path, Deployment{Name: "anvil", ChainID: 31337})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("SaveDeployment error = %v, want %q", err, tt.want)
+			}
+			if out := readConfig(t, path); out != tt.content {
+				t.Errorf("config changed on error:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package contracts
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// StateRootOracleABI is the ABI of the StateRootOracle contract
+const StateRootOracleABI = `[{"inputs":[],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[],"name":"InvalidBlockHash","type":"error"},{"inputs":[],"name":"OnlyOwner","type":"error"},{"inputs":[],"name":"OnlyPoster","type":"error"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"chainId","type":"uint256"},{"indexed":true,"internalType":"uint256","name":"blockNumber","type":"uint256"},{"indexed":false,"internalType":"bytes32","name":"blockHash","type":"bytes32"}],"name":"BlockHashPosted","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"poster","type":"address"},{"indexed":false,"internalType":"bool","name":"allowed","type":"bool"}],"name":"PosterUpdated","type":"event"},{"inputs":[{"internalType":"uint256","name":"_chainId","type":"uint256"},{"internalType":"uint256","name":"_blockNumber","type":"uint256"}],"name":"blockHash","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"latestBlock","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"_chainId","type":"uint256"},{"internalType":"uint256","name":"_blockNumber","type":"uint256"},{"internalType":"bytes32","name":"_blockHash","type":"bytes32"}],"name":"postBlockHash","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"posters","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"_poster","type":"address"},{"internalType":"bool","name":"_allowed","type":"bool"}],"name":"setPoster","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// StateRootOracle is Go binding for the StateRootOracle contract
+type StateRootOracle struct {
+	StateRootOracleCaller
+	StateRootOracleTransactor
+}
+
+type StateRootOracleCaller struct {
+	contract *bind.BoundContract
+}
+
+type StateRootOracleTransactor struct {
+	contract *bind.BoundContract
+}
+
+// NewStateRootOracle creates a new instance of StateRootOracle bound to a contract
+func NewStateRootOracle(address common.Address, backend bind.ContractBackend) (*StateRootOracle, error) {
+	parsed, err := abi.JSON(strings.NewReader(StateRootOracleABI))
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, parsed, backend, backend, backend)
+	return &StateRootOracle{
+		StateRootOracleCaller:     StateRootOracleCaller{contract: contract},
+		StateRootOracleTransactor: StateRootOracleTransactor{contract: contract},
+	}, nil
+}
+
+// PostBlockHash records a source chain block hash
+func (t *StateRootOracleTransactor) PostBlockHash(
+	opts *bind.TransactOpts,
+	chainId *big.Int,
+	blockNumber *big.Int,
+	blockHash [32]byte,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "postBlockHash", chainId, blockNumber, blockHash)
+}
+
+// BlockHash returns the posted hash of a source chain block, or zero
+func (c *StateRootOracleCaller) BlockHash(opts *bind.CallOpts, chainId *big.Int, blockNumber *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "blockHash", chainId, blockNumber)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return *abi.ConvertType(out[0], new([32]byte)).(*[32]byte), nil
+}
+
+// LatestBlock returns the highest block posted for a source chain
+func (c *StateRootOracleCaller) LatestBlock(opts *bind.CallOpts, chainId *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "latestBlock", chainId)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
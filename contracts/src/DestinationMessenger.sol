@@ -2,23 +2,97 @@
 pragma solidity ^0.8.19;
 
 import "./interfaces/IMessenger.sol";
+import "./interfaces/IMessageReceiver.sol";
+import "./interfaces/IStateRootOracle.sol";
+import "./libraries/MerklePatricia.sol";
 
 contract DestinationMessenger is IMessenger {
     address public relayer;
     mapping(bytes32 => bool) public processedMessages;
     mapping(address => uint256) public receivedCount;
     
+    // M-of-N attestation: any account may deliver a message signed by at
+    // least `threshold` validators. Once a threshold is set, the relayer can no
+    // longer deliver on its own.
+    address public owner;
+    address[] public validators;
+    mapping(address => bool) public isValidator;
+    uint256 public threshold;
+    
+    // Storage proofs: any account may deliver a message by proving
+    // SourceMessenger.messageExists[hash] against a block hash from the oracle.
+    // Once an oracle is set, the relayer can no longer deliver on its own.
+    uint256 public constant MESSAGE_EXISTS_SLOT = 1;
+    IStateRootOracle public stateRootOracle;
+    mapping(uint256 => address) public sourceMessengers;
+    
+    // Optimistic delivery: the relayer proposes a message, which can be
+    // finalized by anyone once `challengePeriod` has passed unless a
+    // challenger (a watcher that found the message missing on the source
+    // chain) challenged it first.
+    uint256 public challengePeriod;
+    mapping(bytes32 => uint256) public executableAt;
+    mapping(bytes32 => bool) public challenged;
+    mapping(address => bool) public isChallenger;
+    
     error OnlyRelayer();
+    error OnlyOwner();
     error AlreadyProcessed();
     error InvalidSourceChain();
+    error InsufficientGas();
+    error InvalidThreshold();
+    error InvalidValidator();
+    error InvalidSignature();
+    error SignersNotSorted();
+    error InsufficientSignatures();
+    error UnknownSourceMessenger();
+    error UnknownBlock();
+    error MessageNotProven();
+    error OnlyChallenger();
+    error AlreadyProposed();
+    error NotProposed();
+    error MessageChallenged();
+    error ChallengeWindowOpen(uint256 executableAt);
+    error ChallengeWindowClosed();
+    error DirectDeliveryDisabled();
+    error OptimisticDeliveryDisabled();
+    error TargetedMessage();
+    error NotTargeted();
+    
+    event MessageExecuted(bytes32 indexed messageHash, address indexed target, bool success, bytes returnData);
+    event ValidatorsUpdated(address[] validators, uint256 threshold);
+    event StateRootOracleUpdated(address oracle);
+    event SourceMessengerUpdated(uint256 indexed chainId, address messenger);
+    event MessageProposed(
+        bytes32 indexed messageHash,
+        uint256 indexed sourceChainId,
+        address indexed sender,
+        bytes payload,
+        uint256 nonce,
+        uint256 executableAt
+    );
+    event ProposalChallenged(bytes32 indexed messageHash, address indexed challenger);
+    event ChallengePeriodUpdated(uint256 challengePeriod);
+    event ChallengerUpdated(address indexed challenger, bool allowed);
     
     modifier onlyRelayer() {
         if (msg.sender != relayer) revert OnlyRelayer();
         _;
     }
     
+    modifier onlyChallenger() {
+        if (!isChallenger[msg.sender]) revert OnlyChallenger();
+        _;
+    }
+    
+    modifier onlyOwner() {
+        if (msg.sender != owner) revert OnlyOwner();
+        _;
+    }
+    
     constructor(address _relayer) {
         relayer = _relayer;
+        owner = msg.sender;
     }
     
     function receiveMessage(
@@ -28,12 +102,330 @@ contract DestinationMessenger is IMessenger {
         bytes calldata _payload,
         uint256 _timestamp
     ) external onlyRelayer returns (bytes32) {
+        _checkDirectDelivery();
+        return _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, false);
+    }
+    
+    // Records a message sent with SourceMessenger.sendMessageToTarget and calls
+    // onMessage on its target. A failing target does not revert the delivery;
+    // the outcome is reported in MessageExecuted.
+    function receiveAndExecute(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp
+    ) external onlyRelayer returns (bytes32) {
+        _checkDirectDelivery();
+        bytes32 messageHash = _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, true);
+        _executeTarget(messageHash, _sourceChainId, _sender, _payload);
+        return messageHash;
+    }
+    
+    // Same as receiveMessage, authorised by validator signatures instead of
+    // the relayer. Signatures must be sorted by signer address.
+    function receiveAttested(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp,
+        bytes[] calldata _signatures
+    ) external returns (bytes32) {
+        bytes32 messageHash = _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, false);
+        _verifySignatures(messageHash, _signatures);
+        return messageHash;
+    }
+    
+    // Same as receiveAndExecute, authorised by validator signatures.
+    function receiveAndExecuteAttested(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp,
+        bytes[] calldata _signatures
+    ) external returns (bytes32) {
+        bytes32 messageHash = _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, true);
+        _verifySignatures(messageHash, _signatures);
+        _executeTarget(messageHash, _sourceChainId, _sender, _payload);
+        return messageHash;
+    }
+    
+    // Same as receiveMessage, authorised by a storage proof of the message on
+    // the source chain. `_proof` is abi.encode(blockNumber, rlpHeader,
+    // accountProof, storageProof) as returned by eth_getProof.
+    function receiveProven(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp,
+        bytes calldata _proof
+    ) external returns (bytes32) {
+        bytes32 messageHash = _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, false);
+        _verifyProof(_sourceChainId, messageHash, _proof);
+        return messageHash;
+    }
+    
+    // Same as receiveAndExecute, authorised by a storage proof.
+    function receiveAndExecuteProven(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp,
+        bytes calldata _proof
+    ) external returns (bytes32) {
+        bytes32 messageHash = _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, true);
+        _verifyProof(_sourceChainId, messageHash, _proof);
+        _executeTarget(messageHash, _sourceChainId, _sender, _payload);
+        return messageHash;
+    }
+    
+    // Starts the challenge window for a message; it is recorded as received
+    // only when finalized. While a challenge period is set, the relayer must
+    // use this instead of receiveMessage. Proposals are disabled without a
+    // challenge period, or once validators or an oracle are set, since the
+    // relayer could otherwise finalize without signatures or a proof.
+    function proposeMessage(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp
+    ) external onlyRelayer returns (bytes32) {
+        _checkOptimisticDelivery();
         if (_sourceChainId == block.chainid) revert InvalidSourceChain();
         
-        bytes32 messageHash = keccak256(
-            abi.encodePacked(_nonce, _sourceChainId, block.chainid, _sender, _payload, _timestamp)
+        bytes32 messageHash = _hash(_nonce, _sourceChainId, _sender, _payload, _timestamp);
+        if (processedMessages[messageHash]) revert AlreadyProcessed();
+        if (executableAt[messageHash] != 0) revert AlreadyProposed();
+        
+        uint256 readyAt = block.timestamp + challengePeriod;
+        executableAt[messageHash] = readyAt;
+        
+        emit MessageProposed(messageHash, _sourceChainId, _sender, _payload, _nonce, readyAt);
+        
+        return messageHash;
+    }
+    
+    // Records a proposed message once its challenge window has passed
+    // unchallenged. Anyone may finalize.
+    function finalizeMessage(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp
+    ) external returns (bytes32) {
+        _checkFinalizable(_hash(_nonce, _sourceChainId, _sender, _payload, _timestamp));
+        return _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, false);
+    }
+    
+    // Same as finalizeMessage for targeted messages.
+    function finalizeAndExecute(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp
+    ) external returns (bytes32) {
+        _checkFinalizable(_hash(_nonce, _sourceChainId, _sender, _payload, _timestamp));
+        bytes32 messageHash = _record(_nonce, _sourceChainId, _sender, _payload, _timestamp, true);
+        _executeTarget(messageHash, _sourceChainId, _sender, _payload);
+        return messageHash;
+    }
+    
+    // Blocks a proposed message for good. Challengers call this after
+    // SourceMessenger.verifyMessage returned false for the hash.
+    function challenge(bytes32 _messageHash) external onlyChallenger {
+        uint256 readyAt = executableAt[_messageHash];
+        if (readyAt == 0) revert NotProposed();
+        if (challenged[_messageHash]) revert MessageChallenged();
+        if (block.timestamp >= readyAt) revert ChallengeWindowClosed();
+        
+        challenged[_messageHash] = true;
+        
+        emit ProposalChallenged(_messageHash, msg.sender);
+    }
+    
+    // Clears a challenge found to be wrong so the message can be proposed
+    // again.
+    function resolveChallenge(bytes32 _messageHash) external onlyOwner {
+        if (!challenged[_messageHash]) revert NotProposed();
+        
+        challenged[_messageHash] = false;
+        executableAt[_messageHash] = 0;
+    }
+    
+    function setChallengePeriod(uint256 _challengePeriod) external onlyOwner {
+        challengePeriod = _challengePeriod;
+        emit ChallengePeriodUpdated(_challengePeriod);
+    }
+    
+    function setChallenger(address _challenger, bool _allowed) external onlyOwner {
+        isChallenger[_challenger] = _allowed;
+        emit ChallengerUpdated(_challenger, _allowed);
+    }
+    
+    function setStateRootOracle(address _oracle) external onlyOwner {
+        stateRootOracle = IStateRootOracle(_oracle);
+        emit StateRootOracleUpdated(_oracle);
+    }
+    
+    function setSourceMessenger(uint256 _chainId, address _messenger) external onlyOwner {
+        sourceMessengers[_chainId] = _messenger;
+        emit SourceMessengerUpdated(_chainId, _messenger);
+    }
+    
+    function setValidators(address[] calldata _validators, uint256 _threshold) external onlyOwner {
+        if (_threshold == 0 || _threshold > _validators.length) revert InvalidThreshold();
+        
+        for (uint256 i = 0; i < validators.length; i++) {
+            isValidator[validators[i]] = false;
+        }
+        delete validators;
+        
+        for (uint256 i = 0; i < _validators.length; i++) {
+            address validator = _validators[i];
+            if (validator == address(0) || isValidator[validator]) revert InvalidValidator();
+            isValidator[validator] = true;
+            validators.push(validator);
+        }
+        threshold = _threshold;
+        
+        emit ValidatorsUpdated(_validators, _threshold);
+    }
+    
+    // The digest validators sign: an EIP-191 personal message over this
+    // contract's address and the message hash.
+    function attestationDigest(bytes32 _messageHash) public view returns (bytes32) {
+        return keccak256(
+            abi.encodePacked("\x19Ethereum Signed Message:\n32", keccak256(abi.encodePacked(address(this), _messageHash)))
+        );
+    }
+    
+    function _verifySignatures(bytes32 _messageHash, bytes[] calldata _signatures) internal view {
+        if (threshold == 0 || _signatures.length < threshold) revert InsufficientSignatures();
+        
+        bytes32 digest = attestationDigest(_messageHash);
+        address last;
+        for (uint256 i = 0; i < _signatures.length; i++) {
+            address signer = _recover(digest, _signatures[i]);
+            if (signer <= last) revert SignersNotSorted();
+            if (!isValidator[signer]) revert InvalidSignature();
+            last = signer;
+        }
+    }
+    
+    function _verifyProof(uint256 _sourceChainId, bytes32 _messageHash, bytes calldata _proof) internal view {
+        address source = sourceMessengers[_sourceChainId];
+        if (source == address(0) || address(stateRootOracle) == address(0)) revert UnknownSourceMessenger();
+        
+        (uint256 blockNumber, bytes memory header, bytes[] memory accountProof, bytes[] memory storageProof) =
+            abi.decode(_proof, (uint256, bytes, bytes[], bytes[]));
+        
+        bytes32 blockHash = stateRootOracle.blockHash(_sourceChainId, blockNumber);
+        if (blockHash == bytes32(0) || keccak256(header) != blockHash) revert UnknownBlock();
+        
+        bytes32 storageRoot = MerklePatricia.storageRoot(MerklePatricia.stateRoot(header), source, accountProof);
+        bytes32 slot = keccak256(abi.encode(_messageHash, MESSAGE_EXISTS_SLOT));
+        if (MerklePatricia.storageValue(storageRoot, slot, storageProof) != 1) revert MessageNotProven();
+    }
+    
+    function _recover(bytes32 _digest, bytes calldata _signature) internal pure returns (address) {
+        if (_signature.length != 65) revert InvalidSignature();
+        
+        bytes32 r = bytes32(_signature[0:32]);
+        bytes32 s = bytes32(_signature[32:64]);
+        uint8 v = uint8(_signature[64]);
+        if (v < 27) v += 27;
+        
+        // Reject malleable signatures (upper-half s)
+        if (uint256(s) > 0x7FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF5D576E7357A4501DDFE92F46681B20A0) revert InvalidSignature();
+        
+        address signer = ecrecover(_digest, v, r, s);
+        if (signer == address(0)) revert InvalidSignature();
+        return signer;
+    }
+    
+    // Decodes the abi.encode(target, gasLimit, data) envelope after the
+    // TARGETED_PAYLOAD tag and calls the target. A failing target does not
+    // revert the delivery.
+    function _executeTarget(
+        bytes32 _messageHash,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload
+    ) internal {
+        (address target, uint256 gasLimit, bytes memory data) = abi.decode(_payload[4:], (address, uint256, bytes));
+        
+        // Make sure the caller forwarded enough gas for the requested limit (EIP-150)
+        if (gasleft() - gasleft() / 64 < gasLimit + 10000) revert InsufficientGas();
+        
+        (bool success, bytes memory returnData) = target.call{gas: gasLimit}(
+            abi.encodeCall(IMessageReceiver.onMessage, (_sourceChainId, _sender, data))
         );
         
+        emit MessageExecuted(_messageHash, target, success, returnData);
+    }
+    
+    // The relayer alone may deliver only while no stronger mode is configured:
+    // validator attestation, storage proofs or a challenge period.
+    function _checkDirectDelivery() internal view {
+        if (threshold != 0 || address(stateRootOracle) != address(0) || challengePeriod != 0) {
+            revert DirectDeliveryDisabled();
+        }
+    }
+    
+    // Optimistic delivery is only allowed with a challenge period and while
+    // no stronger mode is configured.
+    function _checkOptimisticDelivery() internal view {
+        if (challengePeriod == 0 || threshold != 0 || address(stateRootOracle) != address(0)) {
+            revert OptimisticDeliveryDisabled();
+        }
+    }
+    
+    // Proposals made before a stronger mode was configured cannot finalize
+    // either.
+    function _checkFinalizable(bytes32 _messageHash) internal view {
+        _checkOptimisticDelivery();
+        uint256 readyAt = executableAt[_messageHash];
+        if (readyAt == 0) revert NotProposed();
+        if (challenged[_messageHash]) revert MessageChallenged();
+        if (block.timestamp < readyAt) revert ChallengeWindowOpen(readyAt);
+    }
+    
+    function _hash(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp
+    ) internal view returns (bytes32) {
+        return keccak256(abi.encodePacked(_nonce, _sourceChainId, block.chainid, _sender, _payload, _timestamp));
+    }
+    
+    // Records a message delivered through an executing entry point when
+    // `_execute` is set. Targeted messages must be executed and others must
+    // not, so no submitter can skip a target's call.
+    function _record(
+        uint256 _nonce,
+        uint256 _sourceChainId,
+        address _sender,
+        bytes calldata _payload,
+        uint256 _timestamp,
+        bool _execute
+    ) internal returns (bytes32) {
+        if (_sourceChainId == block.chainid) revert InvalidSourceChain();
+        
+        bool targeted = _payload.length >= 4 && bytes4(_payload[:4]) == TARGETED_PAYLOAD;
+        if (targeted && !_execute) revert TargetedMessage();
+        if (!targeted && _execute) revert NotTargeted();
+        
+        bytes32 messageHash = _hash(_nonce, _sourceChainId, _sender, _payload, _timestamp);
+        
         if (processedMessages[messageHash]) revert AlreadyProcessed();
         
         processedMessages[messageHash] = true;
@@ -7,10 +7,95 @@ contract SourceMessenger is IMessenger {
     uint256 public nonce;
     mapping(bytes32 => bool) public messageExists;
     
+    address public owner;
+    uint256 public baseFee;
+    uint256 public feePerByte;
+    
+    event FeePaid(uint256 indexed nonce, bytes32 indexed messageHash, uint256 amount);
+    event FeesUpdated(uint256 baseFee, uint256 feePerByte);
+    event MessageTargeted(uint256 indexed nonce, address indexed target, uint256 gasLimit);
+    
     error InvalidDestinationChain();
     error EmptyPayload();
+    error OnlyOwner();
+    error InsufficientFee(uint256 required, uint256 paid);
+    error WithdrawFailed();
+    error InvalidTarget();
+    error ReservedPayload();
+    
+    modifier onlyOwner() {
+        if (msg.sender != owner) revert OnlyOwner();
+        _;
+    }
+    
+    constructor() {
+        owner = msg.sender;
+    }
     
     function sendMessage(uint256 _destChainId, bytes calldata _payload) external returns (bytes32) {
+        _checkUntargeted(_payload);
+        return _send(_destChainId, _payload);
+    }
+    
+    function sendMessageWithFee(uint256 _destChainId, bytes calldata _payload) external payable returns (bytes32) {
+        _checkUntargeted(_payload);
+        uint256 required = quoteFee(_destChainId, _payload);
+        if (msg.value < required) revert InsufficientFee(required, msg.value);
+        
+        uint256 messageNonce = nonce;
+        bytes32 messageHash = _send(_destChainId, _payload);
+        
+        emit FeePaid(messageNonce, messageHash, msg.value);
+        
+        return messageHash;
+    }
+    
+    // Sends a message to be executed on `_target` via IMessageReceiver.onMessage.
+    // The payload is TARGETED_PAYLOAD followed by abi.encode(_target, _gasLimit,
+    // _data), so the target, its gas limit and the fact that it must be
+    // executed are all covered by the message hash.
+    function sendMessageToTarget(
+        uint256 _destChainId,
+        address _target,
+        uint256 _gasLimit,
+        bytes calldata _data
+    ) external payable returns (bytes32) {
+        if (_target == address(0)) revert InvalidTarget();
+        
+        bytes memory payload = abi.encodePacked(TARGETED_PAYLOAD, abi.encode(_target, _gasLimit, _data));
+        uint256 required = quoteFee(_destChainId, payload);
+        if (msg.value < required) revert InsufficientFee(required, msg.value);
+        
+        uint256 messageNonce = nonce;
+        bytes32 messageHash = _send(_destChainId, payload);
+        
+        emit MessageTargeted(messageNonce, _target, _gasLimit);
+        emit FeePaid(messageNonce, messageHash, msg.value);
+        
+        return messageHash;
+    }
+    
+    function quoteFee(uint256, bytes memory _payload) public view returns (uint256) {
+        return baseFee + feePerByte * _payload.length;
+    }
+    
+    function setFees(uint256 _baseFee, uint256 _feePerByte) external onlyOwner {
+        baseFee = _baseFee;
+        feePerByte = _feePerByte;
+        emit FeesUpdated(_baseFee, _feePerByte);
+    }
+    
+    function withdrawFees(address payable _to) external onlyOwner {
+        (bool ok, ) = _to.call{value: address(this).balance}("");
+        if (!ok) revert WithdrawFailed();
+    }
+    
+    // Plain messages may not look targeted, or they could not be delivered
+    function _checkUntargeted(bytes calldata _payload) internal pure {
+        if (_payload.length >= 4 && bytes4(_payload[:4]) == TARGETED_PAYLOAD) revert ReservedPayload();
+    }
+    
+    function _send(uint256 _destChainId, bytes memory _payload) internal returns (bytes32) {
         if (_destChainId == block.chainid) revert InvalidDestinationChain();
         if (_payload.length == 0) revert EmptyPayload();
         
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+import "./interfaces/IStateRootOracle.sol";
+
+// Stores source chain block hashes posted by trusted posters (for example a
+// light client or a committee). DestinationMessenger checks storage proofs
+// against the headers these hashes commit to.
+contract StateRootOracle is IStateRootOracle {
+    address public owner;
+    mapping(address => bool) public posters;
+    mapping(uint256 => mapping(uint256 => bytes32)) internal blockHashes;
+    mapping(uint256 => uint256) public latestBlock;
+    
+    event BlockHashPosted(uint256 indexed chainId, uint256 indexed blockNumber, bytes32 blockHash);
+    event PosterUpdated(address indexed poster, bool allowed);
+    
+    error OnlyOwner();
+    error OnlyPoster();
+    error InvalidBlockHash();
+    
+    modifier onlyOwner() {
+        if (msg.sender != owner) revert OnlyOwner();
+        _;
+    }
+    
+    constructor() {
+        owner = msg.sender;
+        posters[msg.sender] = true;
+    }
+    
+    function setPoster(address _poster, bool _allowed) external onlyOwner {
+        posters[_poster] = _allowed;
+        emit PosterUpdated(_poster, _allowed);
+    }
+    
+    function postBlockHash(uint256 _chainId, uint256 _blockNumber, bytes32 _blockHash) external {
+        if (!posters[msg.sender]) revert OnlyPoster();
+        if (_blockHash == bytes32(0)) revert InvalidBlockHash();
+        
+        blockHashes[_chainId][_blockNumber] = _blockHash;
+        if (_blockNumber > latestBlock[_chainId]) {
+            latestBlock[_chainId] = _blockNumber;
+        }
+        
+        emit BlockHashPosted(_chainId, _blockNumber, _blockHash);
+    }
+    
+    function blockHash(uint256 _chainId, uint256 _blockNumber) external view returns (bytes32) {
+        return blockHashes[_chainId][_blockNumber];
+    }
+}
\ No newline at end of file
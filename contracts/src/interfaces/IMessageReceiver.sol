@@ -0,0 +1,6 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IMessageReceiver {
+    function onMessage(uint256 sourceChainId, address sender, bytes calldata payload) external;
+}
@@ -1,6 +1,11 @@
 // SPDX-License-Identifier: MIT
 pragma solidity ^0.8.19;
 
+// Tag that starts the payload of every message sent with
+// SourceMessenger.sendMessageToTarget. The payload is hashed, so the tag
+// binds delivery to the executing entry points of DestinationMessenger.
+bytes4 constant TARGETED_PAYLOAD = bytes4(keccak256("TargetedMessage(address,uint256,bytes)"));
+
 interface IMessenger {
     struct Message {
         uint256 nonce;
@@ -0,0 +1,7 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+interface IStateRootOracle {
+    function blockHash(uint256 chainId, uint256 blockNumber) external view returns (bytes32);
+    function latestBlock(uint256 chainId) external view returns (uint256);
+}
\ No newline at end of file
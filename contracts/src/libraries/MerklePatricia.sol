@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+// Minimal RLP decoding and Merkle Patricia proof verification for the
+// Ethereum state and storage tries (secure tries keyed by keccak256).
+library MerklePatricia {
+    error InvalidRLP();
+    error InvalidProof();
+    
+    // Returns the state root (field 3) of an RLP-encoded block header.
+    function stateRoot(bytes memory _header) internal pure returns (bytes32) {
+        uint256[] memory fields = _listItems(_header, 0);
+        if (fields.length < 4) revert InvalidRLP();
+        return _bytes32(_header, fields[3]);
+    }
+    
+    // Proves `_account` in the state trie and returns its storage root.
+    function storageRoot(
+        bytes32 _stateRoot,
+        address _account,
+        bytes[] memory _proof
+    ) internal pure returns (bytes32) {
+        bytes memory account = get(_stateRoot, abi.encodePacked(_account), _proof);
+        
+        // [nonce, balance, storageRoot, codeHash]
+        uint256[] memory fields = _listItems(account, 0);
+        if (fields.length != 4) revert InvalidRLP();
+        return _bytes32(account, fields[2]);
+    }
+    
+    // Proves `_slot` in a storage trie and returns its value.
+    function storageValue(
+        bytes32 _storageRoot,
+        bytes32 _slot,
+        bytes[] memory _proof
+    ) internal pure returns (uint256) {
+        bytes memory value = get(_storageRoot, abi.encodePacked(_slot), _proof);
+        
+        (uint256 offset, uint256 len, bool isList) = _decodeHeader(value, 0);
+        if (isList || len > 32) revert InvalidRLP();
+        return _readUint(value, offset, len);
+    }
+    
+    // Walks the proof from `_root` along keccak256(_key) and returns the
+    // value stored at the leaf. Reverts if the proof is invalid or the key
+    // is absent.
+    function get(bytes32 _root, bytes memory _key, bytes[] memory _proof) internal pure returns (bytes memory) {
+        bytes memory path = _toNibbles(abi.encodePacked(keccak256(_key)), 0);
+        uint256 pathPos;
+        
+        bytes memory node;
+        bytes32 nodeHash = _root;
+        bool embedded;
+        uint256 proofIndex;
+        
+        while (true) {
+            if (!embedded) {
+                if (proofIndex >= _proof.length) revert InvalidProof();
+                node = _proof[proofIndex++];
+                if (keccak256(node) != nodeHash) revert InvalidProof();
+            }
+            
+            uint256[] memory items = _listItems(node, 0);
+            
+            if (items.length == 17) {
+                if (pathPos == path.length) return _string(node, items[16]);
+                
+                uint8 nibble = uint8(path[pathPos++]);
+                (node, nodeHash, embedded) = _child(node, items[nibble]);
+            } else if (items.length == 2) {
+                (bytes memory partialPath, bool isLeaf) = _decodeCompact(_string(node, items[0]));
+                if (pathPos + partialPath.length > path.length) revert InvalidProof();
+                
+                for (uint256 i = 0; i < partialPath.length; i++) {
+                    if (path[pathPos + i] != partialPath[i]) revert InvalidProof();
+                }
+                pathPos += partialPath.length;
+                
+                if (isLeaf) {
+                    if (pathPos != path.length) revert InvalidProof();
+                    return _string(node, items[1]);
+                }
+                
+                (node, nodeHash, embedded) = _child(node, items[1]);
+            } else {
+                revert InvalidProof();
+            }
+        }
+    }
+    
+    // A child reference is either a 32-byte node hash or, for nodes shorter
+    // than 32 bytes, the node itself embedded in its parent.
+    function _child(
+        bytes memory _node,
+        uint256 _pos
+    ) private pure returns (bytes memory child, bytes32 hash, bool embedded) {
+        (uint256 offset, uint256 len, bool isList) = _decodeHeader(_node, _pos);
+        
+        if (isList) {
+            return (_slice(_node, _pos, offset + len - _pos), bytes32(0), true);
+        }
+        if (len != 32) revert InvalidProof();
+        
+        assembly {
+            hash := mload(add(add(_node, 32), offset))
+        }
+        return ("", hash, false);
+    }
+    
+    // Hex-prefix decoding: the first nibble flags leaf (2, 3) vs extension
+    // (0, 1) and odd (1, 3) vs even (0, 2) length.
+    function _decodeCompact(bytes memory _encoded) private pure returns (bytes memory nibbles, bool isLeaf) {
+        if (_encoded.length == 0) revert InvalidProof();
+        
+        uint8 flag = uint8(_encoded[0]) >> 4;
+        if (flag > 3) revert InvalidProof();
+        
+        isLeaf = flag >= 2;
+        nibbles = _toNibbles(_encoded, flag % 2 == 1 ? 1 : 2);
+    }
+    
+    // Expands bytes to nibbles, dropping the first `_skip` nibbles.
+    function _toNibbles(bytes memory _data, uint256 _skip) private pure returns (bytes memory nibbles) {
+        uint256 total = _data.length * 2;
+        if (_skip > total) revert InvalidProof();
+        
+        nibbles = new bytes(total - _skip);
+        for (uint256 i = _skip; i < total; i++) {
+            uint8 b = uint8(_data[i / 2]);
+            nibbles[i - _skip] = bytes1(i % 2 == 0 ? b >> 4 : b & 0x0f);
+        }
+    }
+    
+    // Returns the start position of every item in the RLP list at `_pos`.
+    function _listItems(bytes memory _data, uint256 _pos) private pure returns (uint256[] memory items) {
+        (uint256 offset, uint256 len, bool isList) = _decodeHeader(_data, _pos);
+        if (!isList) revert InvalidRLP();
+        
+        uint256 end = offset + len;
+        uint256 count;
+        for (uint256 p = offset; p < end; count++) {
+            p = _itemEnd(_data, p);
+        }
+        
+        items = new uint256[](count);
+        uint256 pos = offset;
+        for (uint256 i = 0; i < count; i++) {
+            items[i] = pos;
+            pos = _itemEnd(_data, pos);
+        }
+        if (pos != end) revert InvalidRLP();
+    }
+    
+    function _itemEnd(bytes memory _data, uint256 _pos) private pure returns (uint256) {
+        (uint256 offset, uint256 len, ) = _decodeHeader(_data, _pos);
+        return offset + len;
+    }
+    
+    // Returns the payload of the RLP string at `_pos`.
+    function _string(bytes memory _data, uint256 _pos) private pure returns (bytes memory) {
+        (uint256 offset, uint256 len, bool isList) = _decodeHeader(_data, _pos);
+        if (isList) revert InvalidRLP();
+        return _slice(_data, offset, len);
+    }
+    
+    function _bytes32(bytes memory _data, uint256 _pos) private pure returns (bytes32 value) {
+        (uint256 offset, uint256 len, bool isList) = _decodeHeader(_data, _pos);
+        if (isList || len != 32) revert InvalidRLP();
+        assembly {
+            value := mload(add(add(_data, 32), offset))
+        }
+    }
+    
+    // Decodes the RLP prefix at `_pos` into payload offset and length.
+    function _decodeHeader(
+        bytes memory _data,
+        uint256 _pos
+    ) private pure returns (uint256 offset, uint256 len, bool isList) {
+        if (_pos >= _data.length) revert InvalidRLP();
+        uint8 prefix = uint8(_data[_pos]);
+        
+        if (prefix < 0x80) {
+            (offset, len, isList) = (_pos, 1, false);
+        } else if (prefix < 0xb8) {
+            (offset, len, isList) = (_pos + 1, prefix - 0x80, false);
+        } else if (prefix < 0xc0) {
+            uint256 lenOfLen = prefix - 0xb7;
+            (offset, len, isList) = (_pos + 1 + lenOfLen, _readUint(_data, _pos + 1, lenOfLen), false);
+        } else if (prefix < 0xf8) {
+            (offset, len, isList) = (_pos + 1, prefix - 0xc0, true);
+        } else {
+            uint256 lenOfLen = prefix - 0xf7;
+            (offset, len, isList) = (_pos + 1 + lenOfLen, _readUint(_data, _pos + 1, lenOfLen), true);
+        }
+        
+        if (offset + len > _data.length) revert InvalidRLP();
+    }
+    
+    function _readUint(bytes memory _data, uint256 _pos, uint256 _len) private pure returns (uint256 value) {
+        if (_len > 32 || _pos + _len > _data.length) revert InvalidRLP();
+        for (uint256 i = 0; i < _len; i++) {
+            value = (value << 8) | uint8(_data[_pos + i]);
+        }
+    }
+    
+    function _slice(bytes memory _data, uint256 _start, uint256 _len) private pure returns (bytes memory out) {
+        out = new bytes(_len);
+        for (uint256 i = 0; i < _len; i++) {
+            out[i] = _data[_start + i];
+        }
+    }
+}
\ No newline at end of file
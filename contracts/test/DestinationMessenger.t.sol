@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+import "forge-std/Test.sol";
+import "../src/DestinationMessenger.sol";
+import "../src/interfaces/IMessageReceiver.sol";
+
+contract MockReceiver is IMessageReceiver {
+    uint256 public lastSourceChainId;
+    address public lastSender;
+    bytes public lastPayload;
+    
+    function onMessage(uint256 sourceChainId, address sender, bytes calldata payload) external {
+        lastSourceChainId = sourceChainId;
+        lastSender = sender;
+        lastPayload = payload;
+    }
+}
+
+contract RevertingReceiver is IMessageReceiver {
+    function onMessage(uint256, address, bytes calldata) external pure {
+        revert("nope");
+    }
+}
+
+contract DestinationMessengerTest is Test {
+    DestinationMessenger public messenger;
+    address public relayer = address(0xBEEF);
+    address public sender = address(0x123);
+    
+    function setUp() public {
+        messenger = new DestinationMessenger(relayer);
+    }
+    
+    function testReceiveMessage() public {
+        vm.prank(relayer);
+        bytes32 hash = messenger.receiveMessage(0, 11155111, sender, "Hello", 1000);
+        
+        assertTrue(messenger.isProcessed(hash));
+        assertEq(messenger.receivedCount(sender), 1);
+    }
+    
+    function testOnlyRelayer() public {
+        vm.expectRevert(DestinationMessenger.OnlyRelayer.selector);
+        messenger.receiveMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testCannotProcessTwice() public {
+        vm.startPrank(relayer);
+        messenger.receiveMessage(0, 11155111, sender, "Hello", 1000);
+        vm.expectRevert(DestinationMessenger.AlreadyProcessed.selector);
+        messenger.receiveMessage(0, 11155111, sender, "Hello", 1000);
+        vm.stopPrank();
+    }
+    
+    function _targeted(address _target, uint256 _gasLimit, bytes memory _data) internal pure returns (bytes memory) {
+        return abi.encodePacked(TARGETED_PAYLOAD, abi.encode(_target, _gasLimit, _data));
+    }
+    
+    function testReceiveAndExecute() public {
+        MockReceiver receiver = new MockReceiver();
+        bytes memory payload = _targeted(address(receiver), 100000, "ping");
+        
+        vm.prank(relayer);
+        bytes32 hash = messenger.receiveAndExecute(0, 11155111, sender, payload, 1000);
+        
+        assertTrue(messenger.isProcessed(hash));
+        assertEq(receiver.lastSourceChainId(), 11155111);
+        assertEq(receiver.lastSender(), sender);
+        assertEq(receiver.lastPayload(), bytes("ping"));
+    }
+    
+    function testFailedExecutionStillRecorded() public {
+        RevertingReceiver receiver = new RevertingReceiver();
+        bytes memory payload = _targeted(address(receiver), 100000, "ping");
+        
+        vm.prank(relayer);
+        bytes32 hash = messenger.receiveAndExecute(0, 11155111, sender, payload, 1000);
+        
+        assertTrue(messenger.isProcessed(hash));
+    }
+    
+    function testTargetedMessageMustExecute() public {
+        MockReceiver receiver = new MockReceiver();
+        bytes memory payload = _targeted(address(receiver), 100000, "ping");
+        
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.TargetedMessage.selector);
+        messenger.receiveMessage(0, 11155111, sender, payload, 1000);
+    }
+    
+    function testTargetedMessageMustExecuteWhenAttested() public {
+        uint256[] memory keys = _setupValidators();
+        bytes memory payload = _targeted(address(new MockReceiver()), 100000, "ping");
+        bytes32 hash = _hash(payload);
+        
+        bytes[] memory signatures = new bytes[](2);
+        signatures[0] = _attest(keys[0], hash);
+        signatures[1] = _attest(keys[1], hash);
+        
+        vm.expectRevert(DestinationMessenger.TargetedMessage.selector);
+        messenger.receiveAttested(0, 11155111, sender, payload, 1000, signatures);
+    }
+    
+    function testPlainMessageCannotExecute() public {
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.NotTargeted.selector);
+        messenger.receiveAndExecute(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testInsufficientGas() public {
+        MockReceiver receiver = new MockReceiver();
+        bytes memory payload = _targeted(address(receiver), 1000000, "ping");
+        
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.InsufficientGas.selector);
+        messenger.receiveAndExecute{gas: 500000}(0, 11155111, sender, payload, 1000);
+    }
+    
+    function _attest(uint256 _key, bytes32 _messageHash) internal view returns (bytes memory) {
+        (uint8 v, bytes32 r, bytes32 s) = vm.sign(_key, messenger.attestationDigest(_messageHash));
+        return abi.encodePacked(r, s, v);
+    }
+    
+    function _setupValidators() internal returns (uint256[] memory keys) {
+        keys = new uint256[](3);
+        keys[0] = 0xA11CE;
+        keys[1] = 0xB0B;
+        keys[2] = 0xC4A5;
+        
+        // Sort keys by address so signatures come out in signer order
+        for (uint256 i = 0; i < keys.length; i++) {
+            for (uint256 j = i + 1; j < keys.length; j++) {
+                if (vm.addr(keys[j]) < vm.addr(keys[i])) {
+                    (keys[i], keys[j]) = (keys[j], keys[i]);
+                }
+            }
+        }
+        
+        address[] memory validators = new address[](3);
+        for (uint256 i = 0; i < keys.length; i++) {
+            validators[i] = vm.addr(keys[i]);
+        }
+        messenger.setValidators(validators, 2);
+    }
+    
+    function _hash(bytes memory _payload) internal view returns (bytes32) {
+        return keccak256(abi.encodePacked(uint256(0), uint256(11155111), block.chainid, sender, _payload, uint256(1000)));
+    }
+    
+    function testReceiveAttested() public {
+        uint256[] memory keys = _setupValidators();
+        bytes32 hash = _hash("Hello");
+        
+        bytes[] memory signatures = new bytes[](2);
+        signatures[0] = _attest(keys[0], hash);
+        signatures[1] = _attest(keys[2], hash);
+        
+        bytes32 received = messenger.receiveAttested(0, 11155111, sender, "Hello", 1000, signatures);
+        
+        assertEq(received, hash);
+        assertTrue(messenger.isProcessed(hash));
+    }
+    
+    function testAttestedBelowThreshold() public {
+        uint256[] memory keys = _setupValidators();
+        
+        bytes[] memory signatures = new bytes[](1);
+        signatures[0] = _attest(keys[0], _hash("Hello"));
+        
+        vm.expectRevert(DestinationMessenger.InsufficientSignatures.selector);
+        messenger.receiveAttested(0, 11155111, sender, "Hello", 1000, signatures);
+    }
+    
+    function testAttestedUnsorted() public {
+        uint256[] memory keys = _setupValidators();
+        bytes32 hash = _hash("Hello");
+        
+        bytes[] memory signatures = new bytes[](2);
+        signatures[0] = _attest(keys[1], hash);
+        signatures[1] = _attest(keys[0], hash);
+        
+        vm.expectRevert(DestinationMessenger.SignersNotSorted.selector);
+        messenger.receiveAttested(0, 11155111, sender, "Hello", 1000, signatures);
+    }
+    
+    function testAttestedByNonValidator() public {
+        uint256[] memory keys = _setupValidators();
+        bytes32 hash = _hash("Hello");
+        
+        uint256 outsider = 0xDEAD;
+        bytes[] memory signatures = new bytes[](2);
+        if (vm.addr(outsider) < vm.addr(keys[0])) {
+            signatures[0] = _attest(outsider, hash);
+            signatures[1] = _attest(keys[0], hash);
+        } else {
+            signatures[0] = _attest(keys[0], hash);
+            signatures[1] = _attest(outsider, hash);
+        }
+        
+        vm.expectRevert(DestinationMessenger.InvalidSignature.selector);
+        messenger.receiveAttested(0, 11155111, sender, "Hello", 1000, signatures);
+    }
+    
+    function testDirectDeliveryDisabledWithValidators() public {
+        _setupValidators();
+        
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.DirectDeliveryDisabled.selector);
+        messenger.receiveMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testOnlyOwnerSetsValidators() public {
+        address[] memory validators = new address[](1);
+        validators[0] = address(0x1);
+        
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.OnlyOwner.selector);
+        messenger.setValidators(validators, 1);
+    }
+    
+    function _proposeHello() internal returns (bytes32) {
+        messenger.setChallengePeriod(1 hours);
+        messenger.setChallenger(address(this), true);
+        
+        vm.prank(relayer);
+        return messenger.proposeMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testProposeAndFinalize() public {
+        bytes32 hash = _proposeHello();
+        assertEq(messenger.executableAt(hash), block.timestamp + 1 hours);
+        assertFalse(messenger.isProcessed(hash));
+        
+        vm.expectRevert(abi.encodeWithSelector(DestinationMessenger.ChallengeWindowOpen.selector, block.timestamp + 1 hours));
+        messenger.finalizeMessage(0, 11155111, sender, "Hello", 1000);
+        
+        vm.warp(block.timestamp + 1 hours);
+        messenger.finalizeMessage(0, 11155111, sender, "Hello", 1000);
+        
+        assertTrue(messenger.isProcessed(hash));
+        assertEq(messenger.receivedCount(sender), 1);
+    }
+    
+    function testChallengedProposalCannotFinalize() public {
+        bytes32 hash = _proposeHello();
+        messenger.challenge(hash);
+        
+        vm.warp(block.timestamp + 1 hours);
+        vm.expectRevert(DestinationMessenger.MessageChallenged.selector);
+        messenger.finalizeMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testChallengeAfterWindow() public {
+        bytes32 hash = _proposeHello();
+        
+        vm.warp(block.timestamp + 1 hours);
+        vm.expectRevert(DestinationMessenger.ChallengeWindowClosed.selector);
+        messenger.challenge(hash);
+    }
+    
+    function testOnlyChallenger() public {
+        bytes32 hash = _proposeHello();
+        
+        vm.prank(address(0xCAFE));
+        vm.expectRevert(DestinationMessenger.OnlyChallenger.selector);
+        messenger.challenge(hash);
+    }
+    
+    function testResolveChallenge() public {
+        bytes32 hash = _proposeHello();
+        messenger.challenge(hash);
+        messenger.resolveChallenge(hash);
+        
+        vm.prank(relayer);
+        messenger.proposeMessage(0, 11155111, sender, "Hello", 1000);
+        assertFalse(messenger.challenged(hash));
+    }
+    
+    function testDirectDeliveryDisabledWhenOptimistic() public {
+        messenger.setChallengePeriod(1 hours);
+        
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.DirectDeliveryDisabled.selector);
+        messenger.receiveMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testProposeDisabledWithoutChallengePeriod() public {
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        messenger.proposeMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testProposeDisabledWithValidators() public {
+        _setupValidators();
+        
+        // Without a challenge period the relayer could finalize in one block
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        messenger.proposeMessage(0, 11155111, sender, "Hello", 1000);
+        
+        messenger.setChallengePeriod(1 hours);
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        messenger.proposeMessage(0, 11155111, sender, "Hello", 1000);
+    }
+    
+    function testFinalizeDisabledOnceValidatorsSet() public {
+        bytes32 hash = _proposeHello();
+        _setupValidators();
+        
+        vm.warp(block.timestamp + 1 hours);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        messenger.finalizeMessage(0, 11155111, sender, "Hello", 1000);
+        assertFalse(messenger.isProcessed(hash));
+    }
+}
\ No newline at end of file
@@ -33,4 +33,68 @@ contract SourceMessengerTest is Test {
         vm.expectRevert(SourceMessenger.EmptyPayload.selector);
         messenger.sendMessage(80001, "");
     }
+    
+    function testSendMessageWithFee() public {
+        messenger.setFees(1 gwei, 10 wei);
+        bytes memory payload = "Hello Amoy";
+        uint256 fee = messenger.quoteFee(80002, payload);
+        assertEq(fee, 1 gwei + 10 wei * payload.length);
+        
+        vm.deal(user, fee);
+        vm.prank(user);
+        bytes32 hash = messenger.sendMessageWithFee{value: fee}(80002, payload);
+        
+        assertTrue(messenger.messageExists(hash));
+        assertEq(address(messenger).balance, fee);
+    }
+    
+    function testCannotUnderpayFee() public {
+        messenger.setFees(1 gwei, 0);
+        vm.deal(user, 1 gwei);
+        vm.prank(user);
+        vm.expectRevert(abi.encodeWithSelector(SourceMessenger.InsufficientFee.selector, 1 gwei, 1 gwei - 1));
+        messenger.sendMessageWithFee{value: 1 gwei - 1}(80002, "test");
+    }
+    
+    function testOnlyOwnerCanSetFees() public {
+        vm.prank(user);
+        vm.expectRevert(SourceMessenger.OnlyOwner.selector);
+        messenger.setFees(1, 1);
+    }
+    
+    function testWithdrawFees() public {
+        messenger.setFees(1 gwei, 0);
+        vm.deal(user, 1 gwei);
+        vm.prank(user);
+        messenger.sendMessageWithFee{value: 1 gwei}(80002, "test");
+        
+        address payable treasury = payable(address(0x456));
+        messenger.withdrawFees(treasury);
+        assertEq(treasury.balance, 1 gwei);
+    }
+    
+    function testSendMessageToTarget() public {
+        address target = address(0x789);
+        vm.prank(user);
+        bytes32 hash = messenger.sendMessageToTarget(80002, target, 100000, "ping");
+        
+        bytes memory payload = abi.encodePacked(TARGETED_PAYLOAD, abi.encode(target, uint256(100000), bytes("ping")));
+        assertEq(hash, messenger.getMessageHash(0, block.chainid, 80002, user, payload, block.timestamp));
+        assertTrue(messenger.messageExists(hash));
+        assertEq(messenger.nonce(), 1);
+    }
+    
+    function testCannotSendTargetedPayloadAsPlain() public {
+        bytes memory payload = abi.encodePacked(TARGETED_PAYLOAD, abi.encode(address(0x789), uint256(100000), bytes("ping")));
+        
+        vm.prank(user);
+        vm.expectRevert(SourceMessenger.ReservedPayload.selector);
+        messenger.sendMessage(80002, payload);
+    }
+    
+    function testCannotSendToZeroTarget() public {
+        vm.prank(user);
+        vm.expectRevert(SourceMessenger.InvalidTarget.selector);
+        messenger.sendMessageToTarget(80002, address(0), 100000, "ping");
+    }
 }
\ No newline at end of file
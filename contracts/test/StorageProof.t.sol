@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.19;
+
+import "forge-std/Test.sol";
+import "../src/DestinationMessenger.sol";
+import "../src/StateRootOracle.sol";
+import "../src/libraries/MerklePatricia.sol";
+
+contract StorageProofTest is Test {
+    DestinationMessenger public messenger;
+    StateRootOracle public oracle;
+    address public relayer = address(0xBEEF);
+    address public sender = address(0x123);
+    
+    // Fixture from a go-ethereum simulated chain, regenerated by running
+    // `go test ./internal/proof -run TestBuild -update` in relayer/:
+    // at block 1 of chain 11155111, SOURCE has messageExists set for
+    // message (nonce 7, sender 0x123, "Hello", timestamp 1000) to chain 31337.
+    uint256 constant SOURCE_CHAIN = 11155111;
+    address constant SOURCE = 0x5000000000000000000000000000000000000005;
+    bytes32 constant BLOCK_HASH = 0x12ada48bdf18a263266845b17095cf7ee3d4c762a072912104f28a0d40445be5;
+    bytes constant PROOF = hex"0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000003200000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000027bf90278a0ef433054455fabb5d0db0da22927a77e4547ad06ea5f270c1048100cb68d53f4a01dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347940000000000000000000000000000000000000000a0836767220cda768806728929e6295151ad27216cf68d4c590f87f89133405df1a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421b90100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000008001840393870080846ad5880e99d883011007846765746888676f312e32372e31856c696e7578a0d08266ab95765978f453186459d84df674817e28269cecad1377e69123684caf88000000000000000084342770c0a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b4218080a00000000000000000000000000000000000000000000000000000000000000000a0e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000006cf86aa1201934a1ae67a6b101ce4d7a96cc158c7e681236b5fb1543237f821bbe5ff7027cb846f8448080a049472f6cfe79a76792ffefd68b4641a515ae3fa922527bc237884455496de23ba0bc36789e7a1e281436464229828f817d6612f7b477d66591ff96a9e064bcc98a00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000053f851a0c66ef924374f921381a0a5361689d744e28fc624407c3c793bf45348004e94c88080808080808080808080808080a0dd2e5e719ca8c17e3d41ca9f9b530d7278e6dd841603d934356da4202b80190880000000000000000000000000000000000000000000000000000000000000000000000000000000000000000023e2a03d0dd84f38e5db1070f9cd08bead7a14d2108baefce07d5c961b8ced36731ba3010000000000000000000000000000000000000000000000000000000000";
+    
+    function setUp() public {
+        messenger = new DestinationMessenger(relayer);
+        oracle = new StateRootOracle();
+        
+        messenger.setStateRootOracle(address(oracle));
+        messenger.setSourceMessenger(SOURCE_CHAIN, SOURCE);
+        oracle.postBlockHash(SOURCE_CHAIN, 1, BLOCK_HASH);
+    }
+    
+    function testReceiveProven() public {
+        // Anyone may deliver a proven message
+        vm.prank(address(0xCAFE));
+        bytes32 hash = messenger.receiveProven(7, SOURCE_CHAIN, sender, "Hello", 1000, PROOF);
+        
+        assertTrue(messenger.isProcessed(hash));
+        assertEq(messenger.receivedCount(sender), 1);
+    }
+    
+    function testProvenReplay() public {
+        messenger.receiveProven(7, SOURCE_CHAIN, sender, "Hello", 1000, PROOF);
+        
+        vm.expectRevert(DestinationMessenger.AlreadyProcessed.selector);
+        messenger.receiveProven(7, SOURCE_CHAIN, sender, "Hello", 1000, PROOF);
+    }
+    
+    function testProvenWrongMessage() public {
+        // A different message maps to a slot the proof does not cover
+        vm.expectRevert(MerklePatricia.InvalidProof.selector);
+        messenger.receiveProven(7, SOURCE_CHAIN, sender, "Hello!", 1000, PROOF);
+    }
+    
+    function testProvenUnknownBlock() public {
+        oracle.postBlockHash(SOURCE_CHAIN, 1, keccak256("other"));
+        
+        vm.expectRevert(DestinationMessenger.UnknownBlock.selector);
+        messenger.receiveProven(7, SOURCE_CHAIN, sender, "Hello", 1000, PROOF);
+    }
+    
+    function testProvenWrongSourceMessenger() public {
+        messenger.setSourceMessenger(SOURCE_CHAIN, address(0x6));
+        
+        vm.expectRevert(MerklePatricia.InvalidProof.selector);
+        messenger.receiveProven(7, SOURCE_CHAIN, sender, "Hello", 1000, PROOF);
+    }
+    
+    function testProvenUnknownSourceChain() public {
+        vm.expectRevert(DestinationMessenger.UnknownSourceMessenger.selector);
+        messenger.receiveProven(7, 80001, sender, "Hello", 1000, PROOF);
+    }
+    
+    function testDirectDeliveryDisabledWithOracle() public {
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.DirectDeliveryDisabled.selector);
+        messenger.receiveMessage(7, SOURCE_CHAIN, sender, "Hello", 1000);
+    }
+    
+    function testProposeDisabledWithOracle() public {
+        // Without a challenge period the relayer could finalize in one block
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        messenger.proposeMessage(7, SOURCE_CHAIN, sender, "Hello", 1000);
+        
+        messenger.setChallengePeriod(1 hours);
+        vm.prank(relayer);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        messenger.proposeMessage(7, SOURCE_CHAIN, sender, "Hello", 1000);
+    }
+    
+    function testFinalizeDisabledOnceOracleSet() public {
+        DestinationMessenger optimistic = new DestinationMessenger(relayer);
+        optimistic.setChallengePeriod(1 hours);
+        vm.prank(relayer);
+        optimistic.proposeMessage(7, SOURCE_CHAIN, sender, "Hello", 1000);
+        
+        optimistic.setStateRootOracle(address(oracle));
+        vm.warp(block.timestamp + 1 hours);
+        vm.expectRevert(DestinationMessenger.OptimisticDeliveryDisabled.selector);
+        optimistic.finalizeMessage(7, SOURCE_CHAIN, sender, "Hello", 1000);
+    }
+    
+    function testOnlyPosterPostsBlockHash() public {
+        vm.prank(address(0xCAFE));
+        vm.expectRevert(StateRootOracle.OnlyPoster.selector);
+        oracle.postBlockHash(SOURCE_CHAIN, 2, BLOCK_HASH);
+    }
+    
+    function testLatestBlock() public {
+        oracle.postBlockHash(SOURCE_CHAIN, 5, keccak256("five"));
+        oracle.postBlockHash(SOURCE_CHAIN, 3, keccak256("three"));
+        
+        assertEq(oracle.latestBlock(SOURCE_CHAIN), 5);
+        assertEq(oracle.blockHash(SOURCE_CHAIN, 3), keccak256("three"));
+    }
+}
\ No newline at end of file
@@ -5,28 +5,45 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"relayer/internal/admin"
+	"relayer/internal/attestation"
 	"relayer/internal/config"
 	"relayer/internal/executor"
-	"relayer/internal/listener"
+	"relayer/internal/fees"
+	"relayer/internal/finality"
+	"relayer/internal/metrics"
+	"relayer/internal/policy"
+	"relayer/internal/proof"
+	"relayer/internal/ratelimit"
+	"relayer/internal/registry"
 	"relayer/internal/signer"
+	"relayer/internal/store"
 	"syscall"
-
-	"github.com/ethereum/go-ethereum/ethclient"
+	"time"
 
 	customTypes "relayer/internal/types"
 )
 
+const configPath = "config.yaml"
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		os.Exit(validateConfig(os.Args[2:]))
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Load config
-	cfg, err := config.LoadConfig("config.yaml")
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
 	// Initialize signer
+	if cfg.Relayer.PrivateKey == "" {
+		log.Fatalf("Failed to load config: %s", missingRelayerKey)
+	}
 	sign, err := signer.NewSigner(cfg.Relayer.PrivateKey)
 	if err != nil {
 		log.Fatalf("Failed to create signer: %v", err)
@@ -34,48 +51,96 @@ func main() {
 
 	log.Printf(" Relayer address: %s", sign.GetAddress().Hex())
 
-	// Initialize clients and chains
-	clients := make(map[int64]*ethclient.Client)
-	chains := make(map[int64]*config.ChainConfig)
+	// Open message store
+	db, err := store.NewStore(cfg.Relayer.DBPath)
+	if err != nil {
+		log.Fatalf("Failed to open store: %v", err)
+	}
 
-	for _, chain := range cfg.Chains {
-		client, err := ethclient.Dial(chain.RpcURL)
-		if err != nil {
-			log.Fatalf("Failed to connect to %s: %v", chain.Name, err)
-		}
-		clients[chain.ChainID] = client
-		chains[chain.ChainID] = &chain
-		log.Printf(" Connected to %s (Chain ID: %d)", chain.Name, chain.ChainID)
+	// Load relay policy
+	pol, err := policy.NewEngine(&cfg.Policy)
+	if err != nil {
+		log.Fatalf("Failed to load policy: %v", err)
+	}
+
+	limiter := ratelimit.NewLimiter(&cfg.RateLimits)
+
+	feeChecker, err := fees.NewChecker(&cfg.Fees, cfg.Chains)
+	if err != nil {
+		log.Fatalf("Failed to load fee config: %v", err)
 	}
 
 	// Message channel
 	messageChan := make(chan *customTypes.CrossChainMessage, 100)
 
-	// Start listeners
+	// Initialize clients and chains
+	chains := registry.NewRegistry()
+	sup := newSupervisor(ctx, configPath, cfg, chains, db, messageChan)
 	for _, chain := range cfg.Chains {
-		chainListener, err := listener.NewListener(
-			clients[chain.ChainID],
-			&chain,
-			messageChan,
-		)
+		if err := sup.connect(chain); err != nil {
+			log.Fatalf("Failed to set up RPC for %s: %v", chain.Name, err)
+		}
+	}
+
+	// Load per-route finality rules
+	finalityChecker, err := finality.NewChecker(cfg.FinalityRules, chains)
+	if err != nil {
+		log.Fatalf("Failed to load finality rules: %v", err)
+	}
+
+	// Set up M-of-N attestation
+	var collector *attestation.Collector
+	if cfg.Attestation.Enabled {
+		attester := attestation.NewAttester(sign, chains)
+		collector, err = attestation.NewCollector(&cfg.Attestation, attester)
 		if err != nil {
-			log.Fatalf("Failed to create listener for %s: %v", chain.Name, err)
+			log.Fatalf("Failed to set up attestation: %v", err)
 		}
 
-		go func(l *listener.Listener) {
-			if err := l.Start(ctx); err != nil {
-				log.Printf("Listener error: %v", err)
+		peerServer := attestation.NewServer(attester, db, finalityChecker)
+		go func() {
+			if err := peerServer.Serve(ctx, cfg.Attestation.ListenAddr); err != nil {
+				log.Printf("Attestation peer API error: %v", err)
 			}
-		}(chainListener)
+		}()
+	}
+
+	// Set up storage-proof delivery
+	var prover *proof.Prover
+	if cfg.Proofs.Enabled {
+		if cfg.Attestation.Enabled {
+			log.Fatalf("attestation and proofs cannot both be enabled")
+		}
+		prover, err = proof.NewProver(&cfg.Proofs, chains)
+		if err != nil {
+			log.Fatalf("Failed to set up proofs: %v", err)
+		}
+	}
+
+	if cfg.Optimistic.Enabled && (cfg.Attestation.Enabled || cfg.Proofs.Enabled) {
+		log.Fatalf("optimistic mode cannot be combined with attestation or proofs")
+	}
+
+	// Start listeners
+	if err := sup.listen(); err != nil {
+		log.Fatalf("%v", err)
 	}
 
 	// Start executor
 	exec := executor.NewExecutor(
-		clients,
 		chains,
 		sign,
+		db,
+		pol,
+		limiter,
+		feeChecker,
+		finalityChecker,
+		collector,
+		prover,
+		cfg.Optimistic.Enabled,
 		cfg.Relayer.MaxRetries,
 		cfg.Relayer.GasLimit,
+		cfg.Relayer.GetMaxExecGas(),
 		messageChan,
 	)
 
@@ -85,13 +150,78 @@ func main() {
 		}
 	}()
 
+	sup.executor = exec
+	sup.policy = pol
+	sup.limiter = limiter
+	sup.fees = feeChecker
+	sup.finality = finalityChecker
+
+	// Start admin API
+	if cfg.Admin.Enabled {
+		adminServer := admin.NewServer(cfg, configPath, chains, sup.listeners(), exec, pol, limiter, sign)
+		sup.admin = adminServer
+		go func() {
+			if err := adminServer.Serve(ctx); err != nil {
+				log.Printf("Admin API error: %v", err)
+			}
+		}()
+	}
+
+	// Start metrics
+	if cfg.Relayer.MetricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(ctx, cfg.Relayer.MetricsAddr); err != nil {
+				log.Printf("Metrics error: %v", err)
+			}
+		}()
+	}
+
+	// Drop old delivered messages from the store
+	go pruneStore(ctx, db, cfg.Relayer.GetRetention())
+
+	// Reload the config when the file changes
+	go func() {
+		if err := sup.watch(ctx); err != nil {
+			log.Printf("Config watch error: %v", err)
+		}
+	}()
+
 	log.Println(" Relayer started successfully!")
 
-	// Wait for interrupt
+	// Wait for interrupt; SIGHUP reloads the config
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if err := sup.reload(); err != nil {
+			log.Printf("Failed to reload config: %v", err)
+		}
+	}
 
 	log.Println("Shutting down...")
 	cancel()
 }
+
+// pruneStore removes delivered messages older than retention from the store
+// every hour, so its snapshot does not grow without bound.
+func pruneStore(ctx context.Context, db *store.Store, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := db.Prune(time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Failed to prune store: %v", err)
+		} else if pruned > 0 {
+			log.Printf(" Pruned %d delivered message(s) from the store", pruned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"relayer/internal/admin"
+	"relayer/internal/config"
+	"relayer/internal/executor"
+	"relayer/internal/fees"
+	"relayer/internal/finality"
+	"relayer/internal/listener"
+	"relayer/internal/policy"
+	"relayer/internal/ratelimit"
+	"relayer/internal/registry"
+	"relayer/internal/rpcpool"
+	"relayer/internal/store"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	customTypes "relayer/internal/types"
+)
+
+const (
+	// Editors often write a file in several steps; wait for them to finish
+	reloadDebounce = 500 * time.Millisecond
+	// Old clients stay open this long so in-flight calls can complete
+	clientDrainDelay = time.Minute
+)
+
+// chainRuntime is the RPC pool and listener running for one chain.
+type chainRuntime struct {
+	config     *config.ChainConfig
+	pool       *rpcpool.Pool
+	stopPool   context.CancelFunc
+	listener   *listener.Listener
+	stopListen context.CancelFunc
+	done       chan struct{}
+}
+
+// supervisor runs a pool and listener per chain and applies config reloads
+// to them and to the other running components. Messages in the executor's
+// queue and store are never dropped by a reload.
+type supervisor struct {
+	ctx         context.Context
+	path        string
+	registry    *registry.Registry
+	db          *store.Store
+	messageChan chan *customTypes.CrossChainMessage
+
+	// Reloadable components, set by main once they exist
+	executor *executor.Executor
+	policy   *policy.Engine
+	limiter  *ratelimit.Limiter
+	fees     *fees.Checker
+	finality *finality.Checker
+	admin    *admin.Server
+
+	mu     sync.Mutex
+	cfg    *config.Config
+	chains map[int64]*chainRuntime
+}
+
+func newSupervisor(
+	ctx context.Context,
+	path string,
+	cfg *config.Config,
+	registry *registry.Registry,
+	db *store.Store,
+	messageChan chan *customTypes.CrossChainMessage,
+) *supervisor {
+	return &supervisor{
+		ctx:         ctx,
+		path:        path,
+		cfg:         cfg,
+		registry:    registry,
+		db:          db,
+		messageChan: messageChan,
+		chains:      make(map[int64]*chainRuntime),
+	}
+}
+
+// connect sets up the RPC pool for a chain and registers it. Listeners are
+// started separately by listen, once the executor side is ready.
+func (s *supervisor) connect(chain config.ChainConfig) error {
+	pool, err := rpcpool.NewPool(s.ctx, &chain, &s.cfg.RPC)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chains[chain.ChainID] = s.startPool(chain, pool)
+	return nil
+}
+
+func (s *supervisor) startPool(chain config.ChainConfig, pool *rpcpool.Pool) *chainRuntime {
+	ctx, cancel := context.WithCancel(s.ctx)
+	go pool.Start(ctx)
+
+	rt := &chainRuntime{config: &chain, pool: pool, stopPool: cancel}
+	s.registry.Set(rt.config, pool.Client())
+	log.Printf(" Connected to %s (Chain ID: %d)", chain.Name, chain.ChainID)
+	return rt
+}
+
+// listen starts the listener of every connected chain.
+func (s *supervisor) listen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rt := range s.chains {
+		if err := s.startListener(rt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startListener starts rt's listener. It resumes from the checkpoint in the
+// store, so a restarted listener picks up where the old one stopped.
+func (s *supervisor) startListener(rt *chainRuntime) error {
+	l, err := listener.NewListener(rt.pool.Client(), rt.config, s.db, s.messageChan, s.cfg.Relayer.GetPollInterval())
+	if err != nil {
+		return fmt.Errorf("failed to create listener for %s: %w", rt.config.Name, err)
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	rt.listener = l
+	rt.stopListen = cancel
+	rt.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		if err := l.Start(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Listener error: %v", err)
+		}
+	}(rt.done)
+	return nil
+}
+
+// stopListener stops rt's listener and waits for it to exit, so two
+// listeners never move the same checkpoint.
+func (s *supervisor) stopListener(rt *chainRuntime) {
+	if rt.listener == nil {
+		return
+	}
+	rt.stopListen()
+	<-rt.done
+	rt.listener = nil
+}
+
+// stopPool stops rt's health checks and closes its client once in-flight
+// calls have had time to finish.
+func (s *supervisor) stopPool(rt *chainRuntime) {
+	rt.stopPool()
+	client := rt.pool.Client()
+	time.AfterFunc(clientDrainDelay, client.Close)
+}
+
+// listeners returns the running listeners by chain ID.
+func (s *supervisor) listeners() map[int64]*listener.Listener {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runningListeners()
+}
+
+// runningListeners is listeners for callers holding s.mu.
+func (s *supervisor) runningListeners() map[int64]*listener.Listener {
+	out := make(map[int64]*listener.Listener, len(s.chains))
+	for id, rt := range s.chains {
+		if rt.listener != nil {
+			out[id] = rt.listener
+		}
+	}
+	return out
+}
+
+// watch reloads the config whenever its file changes, until ctx is
+// cancelled. The directory is watched since editors often replace the file
+// rather than write to it.
+func (s *supervisor) watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config: %w", err)
+	}
+	defer watcher.Close()
+
+	path, err := filepath.Abs(s.path)
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", filepath.Dir(path), err)
+	}
+
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events:
+			if filepath.Clean(event.Name) != path || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			timer = time.After(reloadDebounce)
+		case err := <-watcher.Errors:
+			log.Printf(" Config watch error: %v", err)
+		case <-timer:
+			timer = nil
+			if err := s.reload(); err != nil {
+				log.Printf(" Config reload failed, keeping the running config: %v", err)
+			}
+		}
+	}
+}
+
+// reload re-reads the config file and applies what changed. Nothing is
+// applied unless the whole new config is valid. Sections that cannot
+// change at runtime keep their running values.
+func (s *supervisor) reload() error {
+	if err := s.apply(); err != nil {
+		return err
+	}
+	// Held messages may be relayable under the new config
+	return s.executor.RequeuePending(s.ctx)
+}
+
+func (s *supervisor) apply() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg, err := config.LoadConfig(s.path)
+	if err != nil {
+		return err
+	}
+	s.keepRestartOnly(cfg)
+
+	// Validate everything before changing anything
+	if _, err := fees.NewChecker(&cfg.Fees, cfg.Chains); err != nil {
+		return fmt.Errorf("invalid fees: %w", err)
+	}
+	if _, err := finality.NewChecker(cfg.FinalityRules, s.registry); err != nil {
+		return fmt.Errorf("invalid finality rules: %w", err)
+	}
+
+	// LoadConfig has already rejected duplicate chain IDs
+	next := make(map[int64]config.ChainConfig, len(cfg.Chains))
+	for _, chain := range cfg.Chains {
+		next[chain.ChainID] = chain
+	}
+
+	// New pools for added chains and chains whose endpoints changed
+	pools := make(map[int64]*rpcpool.Pool)
+	for id, chain := range next {
+		if rt, ok := s.chains[id]; ok && sameEndpoints(rt.config, &chain) {
+			continue
+		}
+		pool, err := rpcpool.NewPool(s.ctx, &chain, &cfg.RPC)
+		if err != nil {
+			for _, p := range pools {
+				p.Client().Close()
+			}
+			return err
+		}
+		pools[id] = pool
+	}
+
+	if err := s.policy.Reload(&cfg.Policy); err != nil {
+		for _, p := range pools {
+			p.Client().Close()
+		}
+		return fmt.Errorf("invalid policy: %w", err)
+	}
+	s.limiter.Reload(&cfg.RateLimits)
+	s.fees.Reload(&cfg.Fees, cfg.Chains)
+	s.finality.Reload(cfg.FinalityRules)
+	s.executor.SetLimits(cfg.Relayer.MaxRetries, cfg.Relayer.GasLimit, cfg.Relayer.GetMaxExecGas())
+
+	var added, removed, changed int
+	for id, rt := range s.chains {
+		if _, ok := next[id]; ok {
+			continue
+		}
+		s.stopListener(rt)
+		s.stopPool(rt)
+		s.registry.Remove(id)
+		delete(s.chains, id)
+		log.Printf(" Removed chain %s (Chain ID: %d)", rt.config.Name, id)
+		removed++
+	}
+	for _, chain := range cfg.Chains {
+		rt, ok := s.chains[chain.ChainID]
+		if ok && reflect.DeepEqual(*rt.config, chain) {
+			continue
+		}
+
+		if ok {
+			s.stopListener(rt)
+			if pool, replaced := pools[chain.ChainID]; replaced {
+				s.stopPool(rt)
+				rt = s.startPool(chain, pool)
+			} else {
+				// A fresh copy, since other components may hold the old one
+				updated := chain
+				rt.config = &updated
+				s.registry.Set(rt.config, rt.pool.Client())
+			}
+			changed++
+		} else {
+			rt = s.startPool(chain, pools[chain.ChainID])
+			added++
+		}
+		s.chains[chain.ChainID] = rt
+
+		if err := s.startListener(rt); err != nil {
+			// The config was validated, so this only fails on the RPC side
+			log.Printf(" Failed to restart listener for %s: %v", chain.Name, err)
+		}
+	}
+
+	s.cfg = cfg
+	if s.admin != nil {
+		s.admin.Update(cfg, s.runningListeners())
+	}
+
+	log.Printf(" Config reloaded: %d chain(s) added, %d removed, %d changed", added, removed, changed)
+	return nil
+}
+
+// keepRestartOnly copies the sections that need a restart to take effect
+// from the running config into cfg, warning about any that changed.
+func (s *supervisor) keepRestartOnly(cfg *config.Config) {
+	warn := func(name string, running, next any) {
+		if !reflect.DeepEqual(running, next) {
+			log.Printf(" Config section %s changed; restart relayerd to apply it", name)
+		}
+	}
+
+	warn("relayer.private_key", s.cfg.Relayer.PrivateKey, cfg.Relayer.PrivateKey)
+	warn("relayer.db_path", s.cfg.Relayer.DBPath, cfg.Relayer.DBPath)
+	warn("relayer.retention", s.cfg.Relayer.Retention, cfg.Relayer.Retention)
+	warn("relayer.metrics_addr", s.cfg.Relayer.MetricsAddr, cfg.Relayer.MetricsAddr)
+	warn("admin", s.cfg.Admin, cfg.Admin)
+	warn("attestation", s.cfg.Attestation, cfg.Attestation)
+	warn("proofs", s.cfg.Proofs, cfg.Proofs)
+	warn("optimistic", s.cfg.Optimistic, cfg.Optimistic)
+	warn("rpc", s.cfg.RPC, cfg.RPC)
+
+	cfg.Relayer.PrivateKey = s.cfg.Relayer.PrivateKey
+	cfg.Relayer.DBPath = s.cfg.Relayer.DBPath
+	cfg.Relayer.Retention = s.cfg.Relayer.Retention
+	cfg.Relayer.MetricsAddr = s.cfg.Relayer.MetricsAddr
+	cfg.Admin = s.cfg.Admin
+	cfg.Attestation = s.cfg.Attestation
+	cfg.Proofs = s.cfg.Proofs
+	cfg.Optimistic = s.cfg.Optimistic
+	cfg.RPC = s.cfg.RPC
+}
+
+func sameEndpoints(a, b *config.ChainConfig) bool {
+	return reflect.DeepEqual(a.GetRpcURLs(), b.GetRpcURLs()) && a.RpcQuorum == b.RpcQuorum
+}
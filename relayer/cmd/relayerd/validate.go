@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"relayer/internal/config"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// chainIDTimeout bounds each eth_chainId call made by validate-config.
+const chainIDTimeout = 10 * time.Second
+
+// missingRelayerKey is reported when relayer.private_key is empty, which
+// usually means RELAYER_PRIVATE_KEY is not set.
+const missingRelayerKey = "relayer.private_key is required (is RELAYER_PRIVATE_KEY set?)"
+
+// validateConfig implements `relayerd validate-config`. It prints every
+// problem with the config, including RPC endpoints serving another chain,
+// and returns the exit code.
+func validateConfig(args []string) int {
+	flags := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	path := flags.String("config", configPath, "config file to validate")
+	offline := flags.Bool("offline", false, "skip the eth_chainId check against each RPC endpoint")
+	flags.Parse(args)
+
+	cfg, problems, err := config.Check(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *path, err)
+		return 1
+	}
+	if cfg.Relayer.PrivateKey == "" {
+		problems = append(problems, missingRelayerKey)
+	}
+	if !*offline {
+		problems = append(problems, checkChainIDs(cfg)...)
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("%s: %d problem(s)\n", *path, len(problems))
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		return 1
+	}
+	fmt.Printf("%s: OK (%d chain(s))\n", *path, len(cfg.Chains))
+	return 0
+}
+
+// checkChainIDs asks every RPC endpoint for its chain ID and reports those
+// that are unreachable or serve a different chain than configured.
+func checkChainIDs(cfg *config.Config) config.Problems {
+	var problems config.Problems
+	for _, chain := range cfg.Chains {
+		if chain.ChainID <= 0 {
+			continue
+		}
+		for _, url := range chain.GetRpcURLs() {
+			if !strings.Contains(url, "://") || !strings.HasPrefix(url, "http") && !strings.HasPrefix(url, "ws") {
+				continue // already reported by Validate
+			}
+			label := config.RedactURL(url)
+			id, err := rpcChainID(url)
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("chains[%s]: %s is unreachable: %v", chain.Name, label, err))
+			case id != chain.ChainID:
+				problems = append(problems, fmt.Sprintf("chains[%s]: %s serves chain %d, not %d", chain.Name, label, id, chain.ChainID))
+			}
+		}
+	}
+	return problems
+}
+
+func rpcChainID(url string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), chainIDTimeout)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return id.Int64(), nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"relayer/internal/rpcpool"
+	"relayer/internal/signer"
+	"relayer/internal/watcher"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"relayer/pkg/contracts"
+)
+
+const configPath = "config.yaml"
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Load config
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Without a key the watcher only reports
+	var sign *signer.Signer
+	if cfg.Watcher.PrivateKey != "" {
+		sign, err = signer.NewSigner(cfg.Watcher.PrivateKey)
+		if err != nil {
+			log.Fatalf("Failed to create signer: %v", err)
+		}
+		log.Printf(" Challenger address: %s", sign.GetAddress().Hex())
+	} else {
+		log.Println(" No watcher key configured, running read-only")
+	}
+
+	// Initialize clients and source contracts
+	clients := make(map[int64]*ethclient.Client)
+	sources := make(map[int64]*contracts.SourceMessenger)
+
+	for _, chain := range cfg.Chains {
+		pool, err := rpcpool.NewPool(ctx, &chain, &cfg.RPC)
+		if err != nil {
+			log.Fatalf("Failed to set up RPC for %s: %v", chain.Name, err)
+		}
+		go pool.Start(ctx)
+		client := pool.Client()
+		clients[chain.ChainID] = client
+
+		source, err := contracts.NewSourceMessenger(chain.GetSourceContract(), client)
+		if err != nil {
+			log.Fatalf("Failed to instantiate source contract on %s: %v", chain.Name, err)
+		}
+		sources[chain.ChainID] = source
+		log.Printf(" Connected to %s (Chain ID: %d)", chain.Name, chain.ChainID)
+	}
+
+	tracker := watcher.NewTracker()
+	alerter := watcher.NewAlerter(cfg.Watcher.WebhookURL)
+
+	// Watch every chain
+	dests := make(map[int64]*contracts.DestinationMessenger)
+	for _, chain := range cfg.Chains {
+		chainWatcher, err := watcher.NewWatcher(
+			clients[chain.ChainID],
+			&chain,
+			sources,
+			tracker,
+			alerter,
+			sign,
+			cfg.Watcher.Lookback,
+		)
+		if err != nil {
+			log.Fatalf("Failed to create watcher for %s: %v", chain.Name, err)
+		}
+		dests[chain.ChainID] = chainWatcher.DestContract()
+
+		go func(w *watcher.Watcher) {
+			if err := w.Start(ctx); err != nil {
+				log.Printf("Watcher error: %v", err)
+			}
+		}(chainWatcher)
+	}
+
+	// Flag messages sent but never delivered
+	retention := watcher.DefaultRetention
+	if cfg.Watcher.DeliverySLA != "" {
+		sla, err := time.ParseDuration(cfg.Watcher.DeliverySLA)
+		if err != nil {
+			log.Fatalf("Invalid watcher delivery_sla: %v", err)
+		}
+		// Sends must outlive the SLA to be flagged
+		if 2*sla > retention {
+			retention = 2 * sla
+		}
+		checker := watcher.NewSLAChecker(tracker, alerter, dests, sla)
+		go func() {
+			if err := checker.Start(ctx); err != nil {
+				log.Printf("SLA checker error: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		if err := tracker.Start(ctx, retention); err != nil && ctx.Err() == nil {
+			log.Printf("Tracker error: %v", err)
+		}
+	}()
+
+	// Start metrics
+	if cfg.Watcher.MetricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(ctx, cfg.Watcher.MetricsAddr); err != nil {
+				log.Printf("Metrics error: %v", err)
+			}
+		}()
+	}
+
+	log.Println(" Watcher started successfully!")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	log.Println("Shutting down...")
+	cancel()
+}
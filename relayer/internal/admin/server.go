@@ -0,0 +1,383 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+
+	"relayer/internal/config"
+	"relayer/internal/executor"
+	"relayer/internal/listener"
+	"relayer/internal/policy"
+	"relayer/internal/ratelimit"
+	"relayer/internal/registry"
+	"relayer/internal/signer"
+	"relayer/internal/store"
+	"relayer/pkg/adminpb"
+)
+
+// Server implements adminpb.AdminServiceServer on top of the running
+// executor, listeners and signer.
+type Server struct {
+	adminpb.UnimplementedAdminServiceServer
+
+	configPath string
+	registry   *registry.Registry
+	executor   *executor.Executor
+	policy     *policy.Engine
+	limiter    *ratelimit.Limiter
+	signer     *signer.Signer
+
+	mu        sync.RWMutex
+	cfg       *config.Config
+	listeners map[int64]*listener.Listener
+}
+
+func NewServer(
+	cfg *config.Config,
+	configPath string,
+	registry *registry.Registry,
+	listeners map[int64]*listener.Listener,
+	exec *executor.Executor,
+	policy *policy.Engine,
+	limiter *ratelimit.Limiter,
+	signer *signer.Signer,
+) *Server {
+	return &Server{
+		cfg:        cfg,
+		configPath: configPath,
+		registry:   registry,
+		listeners:  listeners,
+		executor:   exec,
+		policy:     policy,
+		limiter:    limiter,
+		signer:     signer,
+	}
+}
+
+// Update points the server at the config and listeners in use after a
+// config reload.
+func (s *Server) Update(cfg *config.Config, listeners map[int64]*listener.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+	s.listeners = listeners
+}
+
+func (s *Server) currentConfig() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Serve listens on cfg.Admin.ListenAddr until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context) error {
+	cfg := s.currentConfig()
+	opts, err := serverOptions(&cfg.Admin)
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", cfg.Admin.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Admin.ListenAddr, err)
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	adminpb.RegisterAdminServiceServer(grpcServer, s)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	log.Printf(" Admin API listening on %s", lis.Addr())
+	return grpcServer.Serve(lis)
+}
+
+func (s *Server) Pause(ctx context.Context, req *adminpb.PauseRequest) (*adminpb.PauseResponse, error) {
+	switch target := req.GetTarget().GetTarget().(type) {
+	case *adminpb.PauseTarget_All:
+		if !target.All {
+			return nil, status.Error(codes.InvalidArgument, "all must be true")
+		}
+		s.policy.PauseAll()
+	case *adminpb.PauseTarget_ChainId:
+		s.policy.PauseChain(target.ChainId)
+	case *adminpb.PauseTarget_Route:
+		s.policy.PauseRoute(toRoute(target.Route))
+	default:
+		return nil, status.Error(codes.InvalidArgument, "target is required")
+	}
+	return &adminpb.PauseResponse{Paused: s.paused()}, nil
+}
+
+func (s *Server) Resume(ctx context.Context, req *adminpb.ResumeRequest) (*adminpb.ResumeResponse, error) {
+	switch target := req.GetTarget().GetTarget().(type) {
+	case *adminpb.PauseTarget_All:
+		if !target.All {
+			return nil, status.Error(codes.InvalidArgument, "all must be true")
+		}
+		s.policy.ResumeAll()
+	case *adminpb.PauseTarget_ChainId:
+		s.policy.ResumeChain(target.ChainId)
+	case *adminpb.PauseTarget_Route:
+		s.policy.ResumeRoute(toRoute(target.Route))
+	default:
+		return nil, status.Error(codes.InvalidArgument, "target is required")
+	}
+	if err := s.executor.RequeuePending(ctx); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to requeue held messages: %v", err)
+	}
+	return &adminpb.ResumeResponse{Paused: s.paused()}, nil
+}
+
+func (s *Server) ListPaused(ctx context.Context, req *adminpb.ListPausedRequest) (*adminpb.ListPausedResponse, error) {
+	return &adminpb.ListPausedResponse{Paused: s.paused()}, nil
+}
+
+func (s *Server) ReloadPolicy(ctx context.Context, req *adminpb.ReloadPolicyRequest) (*adminpb.ReloadPolicyResponse, error) {
+	if err := s.policy.ReloadFrom(s.configPath); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to reload policy: %v", err)
+	}
+	if err := s.executor.RequeuePending(ctx); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to requeue held messages: %v", err)
+	}
+	return &adminpb.ReloadPolicyResponse{Paused: s.paused()}, nil
+}
+
+func (s *Server) RequeueMessage(ctx context.Context, req *adminpb.RequeueMessageRequest) (*adminpb.RequeueMessageResponse, error) {
+	if !isHash(req.GetMessageHash()) {
+		return nil, status.Error(codes.InvalidArgument, "message_hash must be a 32-byte hex string")
+	}
+
+	msg, err := s.executor.Requeue(ctx, common.HexToHash(req.GetMessageHash()))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to requeue: %v", err)
+	}
+
+	return &adminpb.RequeueMessageResponse{
+		MessageHash:   msg.MessageHash.Hex(),
+		SourceChainId: msg.SourceChainID.Int64(),
+		DestChainId:   msg.DestChainID.Int64(),
+		RetryCount:    int32(msg.RetryCount),
+	}, nil
+}
+
+func (s *Server) RewindCheckpoint(ctx context.Context, req *adminpb.RewindCheckpointRequest) (*adminpb.RewindCheckpointResponse, error) {
+	s.mu.RLock()
+	l, ok := s.listeners[req.GetChainId()]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no listener for chain %d", req.GetChainId())
+	}
+
+	previous := l.Checkpoint()
+	if req.GetBlock() > previous {
+		return nil, status.Errorf(codes.InvalidArgument, "block %d is ahead of checkpoint %d", req.GetBlock(), previous)
+	}
+
+	if err := l.Rewind(req.GetBlock()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rewind: %v", err)
+	}
+
+	return &adminpb.RewindCheckpointResponse{
+		ChainId:       req.GetChainId(),
+		PreviousBlock: previous,
+		Block:         req.GetBlock(),
+	}, nil
+}
+
+func (s *Server) GetSignerStatus(ctx context.Context, req *adminpb.GetSignerStatusRequest) (*adminpb.GetSignerStatusResponse, error) {
+	address := s.signer.GetAddress()
+	resp := &adminpb.GetSignerStatusResponse{Address: address.Hex()}
+
+	for _, chain := range s.currentConfig().Chains {
+		balance := &adminpb.ChainBalance{ChainId: chain.ChainID, Name: chain.Name}
+		resp.Balances = append(resp.Balances, balance)
+
+		client, ok := s.registry.Client(chain.ChainID)
+		if !ok {
+			balance.Error = "no client"
+			continue
+		}
+
+		wei, err := client.BalanceAt(ctx, address, nil)
+		if err != nil {
+			balance.Error = err.Error()
+			continue
+		}
+		balance.BalanceWei = wei.String()
+
+		nonce, err := client.PendingNonceAt(ctx, address)
+		if err != nil {
+			balance.Error = err.Error()
+			continue
+		}
+		balance.PendingNonce = nonce
+	}
+
+	return resp, nil
+}
+
+func (s *Server) GetRateLimits(ctx context.Context, req *adminpb.GetRateLimitsRequest) (*adminpb.GetRateLimitsResponse, error) {
+	resp := &adminpb.GetRateLimitsResponse{}
+	for _, b := range s.limiter.Buckets() {
+		resp.Buckets = append(resp.Buckets, &adminpb.RateLimitBucket{
+			Kind:      b.Kind,
+			Key:       b.Key,
+			Tokens:    b.Tokens,
+			Burst:     int32(b.Burst),
+			PerMinute: float64(b.Rate) * 60,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) GetConfig(ctx context.Context, req *adminpb.GetConfigRequest) (*adminpb.GetConfigResponse, error) {
+	out, err := yaml.Marshal(s.currentConfig().Redacted())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode config: %v", err)
+	}
+	return &adminpb.GetConfigResponse{Yaml: string(out)}, nil
+}
+
+func (s *Server) paused() []*adminpb.PauseTarget {
+	all, chains, routes := s.policy.Paused()
+
+	out := make([]*adminpb.PauseTarget, 0, len(chains)+len(routes)+1)
+	if all {
+		out = append(out, &adminpb.PauseTarget{
+			Target: &adminpb.PauseTarget_All{All: true},
+		})
+	}
+	for _, chainID := range chains {
+		out = append(out, &adminpb.PauseTarget{
+			Target: &adminpb.PauseTarget_ChainId{ChainId: chainID},
+		})
+	}
+	for _, route := range routes {
+		out = append(out, &adminpb.PauseTarget{
+			Target: &adminpb.PauseTarget_Route{Route: &adminpb.Route{
+				SourceChainId: route.SourceChainID,
+				DestChainId:   route.DestChainID,
+			}},
+		})
+	}
+	return out
+}
+
+func toRoute(r *adminpb.Route) policy.Route {
+	return policy.Route{
+		SourceChainID: r.GetSourceChainId(),
+		DestChainID:   r.GetDestChainId(),
+	}
+}
+
+func isHash(s string) bool {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	return err == nil && len(b) == common.HashLength
+}
+
+// serverOptions builds TLS and auth options. A token is checked on every
+// call via the "authorization: Bearer <token>" metadata; a client CA turns
+// on mandatory mTLS.
+func serverOptions(cfg *config.AdminConfig) ([]grpc.ServerOption, error) {
+	if cfg.Token == "" && cfg.ClientCA == "" {
+		return nil, fmt.Errorf("admin API requires a token or a client CA")
+	}
+
+	var opts []grpc.ServerOption
+
+	if cfg.TLSCert != "" || cfg.ClientCA != "" {
+		tlsConfig, err := tlsConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	if cfg.Token != "" {
+		auth := tokenAuth(cfg.Token)
+		opts = append(opts, grpc.UnaryInterceptor(func(
+			ctx context.Context,
+			req interface{},
+			info *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler,
+		) (interface{}, error) {
+			if err := auth(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}))
+	}
+
+	return opts, nil
+}
+
+func tlsConfig(cfg *config.AdminConfig) (*tls.Config, error) {
+	if cfg.TLSCert == "" || cfg.TLSKey == "" {
+		return nil, fmt.Errorf("admin TLS requires tls_cert and tls_key")
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin TLS key pair: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCA != "" {
+		pem, err := os.ReadFile(cfg.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA %s", cfg.ClientCA)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+func tokenAuth(token string) func(ctx context.Context) error {
+	expected := []byte("Bearer " + token)
+	return func(ctx context.Context) error {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return status.Error(codes.Unauthenticated, "missing metadata")
+		}
+		for _, value := range md.Get("authorization") {
+			if subtle.ConstantTimeCompare([]byte(value), expected) == 1 {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "invalid admin token")
+	}
+}
@@ -0,0 +1,89 @@
+package attestation
+
+import (
+	"fmt"
+	"relayer/internal/registry"
+	"relayer/internal/signer"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	customTypes "relayer/internal/types"
+)
+
+// Attestation is one validator's signature over a message.
+type Attestation struct {
+	MessageHash common.Hash
+	Signer      common.Address
+	Signature   []byte
+}
+
+// Digest mirrors DestinationMessenger.attestationDigest: an EIP-191 personal
+// message over the destination contract address and the message hash, so a
+// signature cannot be replayed against another deployment.
+func Digest(destContract common.Address, messageHash common.Hash) common.Hash {
+	inner := crypto.Keccak256(destContract.Bytes(), messageHash.Bytes())
+	return common.BytesToHash(accounts.TextHash(inner))
+}
+
+// Recover returns the address that produced signature over digest.
+func Recover(digest common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Attester signs messages with the relayer key for the destination
+// contract configured for their chain.
+type Attester struct {
+	signer   *signer.Signer
+	registry *registry.Registry
+}
+
+func NewAttester(signer *signer.Signer, registry *registry.Registry) *Attester {
+	return &Attester{signer: signer, registry: registry}
+}
+
+func (a *Attester) Address() common.Address {
+	return a.signer.GetAddress()
+}
+
+// Digest returns the digest to sign for msg on its destination chain.
+func (a *Attester) Digest(msg *customTypes.CrossChainMessage) (common.Hash, error) {
+	chain, ok := a.registry.Chain(msg.DestChainID.Int64())
+	if !ok {
+		return common.Hash{}, fmt.Errorf("no config for chain %s", msg.DestChainID)
+	}
+	return Digest(chain.GetDestContract(), msg.MessageHash), nil
+}
+
+func (a *Attester) Attest(msg *customTypes.CrossChainMessage) (*Attestation, error) {
+	digest, err := a.Digest(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := a.signer.SignHash(digest.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{
+		MessageHash: msg.MessageHash,
+		Signer:      a.signer.GetAddress(),
+		Signature:   sig,
+	}, nil
+}
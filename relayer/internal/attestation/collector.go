@@ -0,0 +1,176 @@
+package attestation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"relayer/internal/config"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	customTypes "relayer/internal/types"
+)
+
+var ErrInsufficientSignatures = errors.New("not enough validator signatures")
+
+// Collector gathers attestations from this relayer and its peers until the
+// validator threshold is met. Only the submitter relayer collects; the
+// others just serve their attestations to it.
+type Collector struct {
+	attester      *Attester
+	peers         []string
+	validators    map[common.Address]bool
+	threshold     int
+	submitter     bool
+	retryInterval time.Duration
+	client        *http.Client
+}
+
+func NewCollector(cfg *config.AttestationConfig, attester *Attester) (*Collector, error) {
+	if cfg.Threshold < 1 || cfg.Threshold > len(cfg.Validators) {
+		return nil, fmt.Errorf("attestation threshold must be between 1 and the number of validators")
+	}
+
+	validators := make(map[common.Address]bool, len(cfg.Validators))
+	for _, v := range cfg.Validators {
+		if !common.IsHexAddress(v) {
+			return nil, fmt.Errorf("invalid validator address %q", v)
+		}
+		validators[common.HexToAddress(v)] = true
+	}
+
+	retryInterval := 15 * time.Second
+	if cfg.RetryInterval != "" {
+		d, err := time.ParseDuration(cfg.RetryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attestation retry_interval: %w", err)
+		}
+		retryInterval = d
+	}
+
+	return &Collector{
+		attester:      attester,
+		peers:         cfg.Peers,
+		validators:    validators,
+		threshold:     cfg.Threshold,
+		submitter:     cfg.Submitter,
+		retryInterval: retryInterval,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Submitter reports whether this relayer submits aggregated proofs.
+func (c *Collector) Submitter() bool {
+	return c.submitter
+}
+
+// RetryInterval is how long to wait before collecting again after
+// ErrInsufficientSignatures.
+func (c *Collector) RetryInterval() time.Duration {
+	return c.retryInterval
+}
+
+// Collect returns exactly threshold signatures from distinct validators,
+// sorted by signer address as DestinationMessenger requires.
+func (c *Collector) Collect(ctx context.Context, msg *customTypes.CrossChainMessage) ([][]byte, error) {
+	digest, err := c.attester.Digest(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		byAddr = make(map[common.Address][]byte)
+	)
+	accept := func(att *Attestation, from string) {
+		signer, err := Recover(digest, att.Signature)
+		if err != nil || signer != att.Signer || !c.validators[signer] {
+			log.Printf(" Ignoring invalid attestation from %s for %s", from, msg.MessageHash.Hex())
+			return
+		}
+		mu.Lock()
+		byAddr[signer] = att.Signature
+		mu.Unlock()
+	}
+
+	if c.validators[c.attester.Address()] {
+		own, err := c.attester.Attest(msg)
+		if err != nil {
+			return nil, err
+		}
+		accept(own, "self")
+	}
+
+	for _, peer := range c.peers {
+		wg.Add(1)
+		go func(peer string) {
+			defer wg.Done()
+			att, err := c.fetch(ctx, peer, msg.MessageHash)
+			if err != nil {
+				log.Printf(" Peer %s: %v", peer, err)
+				return
+			}
+			accept(att, peer)
+		}(peer)
+	}
+	wg.Wait()
+
+	if len(byAddr) < c.threshold {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrInsufficientSignatures, len(byAddr), c.threshold)
+	}
+
+	signers := make([]common.Address, 0, len(byAddr))
+	for addr := range byAddr {
+		signers = append(signers, addr)
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i].Bytes(), signers[j].Bytes()) < 0
+	})
+
+	signatures := make([][]byte, 0, c.threshold)
+	for _, addr := range signers[:c.threshold] {
+		signatures = append(signatures, byAddr[addr])
+	}
+	return signatures, nil
+}
+
+func (c *Collector) fetch(ctx context.Context, peer string, hash common.Hash) (*Attestation, error) {
+	url := strings.TrimRight(peer, "/") + "/v1/attestations/" + hash.Hex()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var body attestationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode attestation: %w", err)
+	}
+	if body.MessageHash != hash {
+		return nil, fmt.Errorf("attestation for wrong message %s", body.MessageHash.Hex())
+	}
+
+	return &Attestation{
+		MessageHash: body.MessageHash,
+		Signer:      body.Signer,
+		Signature:   body.Signature,
+	}, nil
+}
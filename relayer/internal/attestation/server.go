@@ -0,0 +1,119 @@
+package attestation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"relayer/internal/finality"
+	"relayer/internal/store"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	customTypes "relayer/internal/types"
+)
+
+// Server is the peer API other relayers call to collect this relayer's
+// attestation. It only signs messages its own listener has seen on the
+// source chain, that passed its policy, execution gas and fee checks, and
+// whose source block is final under the route's finality rules.
+type Server struct {
+	attester *Attester
+	store    *store.Store
+	finality *finality.Checker
+}
+
+func NewServer(attester *Attester, store *store.Store, finality *finality.Checker) *Server {
+	return &Server{attester: attester, store: store, finality: finality}
+}
+
+// attestationResponse is the wire format of GET /v1/attestations/{hash}.
+type attestationResponse struct {
+	MessageHash common.Hash    `json:"message_hash"`
+	Signer      common.Address `json:"signer"`
+	Signature   hexutil.Bytes  `json:"signature"`
+}
+
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/attestations/{hash}", s.handleGet)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf(" Attestation peer API listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	raw, err := hexutil.Decode(r.PathValue("hash"))
+	if err != nil || len(raw) != common.HashLength {
+		http.Error(w, "invalid message hash", http.StatusBadRequest)
+		return
+	}
+
+	msg, err := s.store.GetMessage(common.BytesToHash(raw))
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "message not seen", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch msg.Status {
+	case customTypes.StatusOrphaned:
+		http.Error(w, "message dropped by a source chain reorg", http.StatusNotFound)
+		return
+	case customTypes.StatusRejected:
+		http.Error(w, "message rejected by relay policy", http.StatusConflict)
+		return
+	case customTypes.StatusUnderfunded:
+		http.Error(w, "message underfunded", http.StatusConflict)
+		return
+	case customTypes.StatusPending:
+		// Held, deferred or not yet through the executor's checks
+		http.Error(w, "message not checked yet", http.StatusConflict)
+		return
+	}
+
+	final, err := s.finality.Final(r.Context(), msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !final {
+		http.Error(w, "message not final yet", http.StatusConflict)
+		return
+	}
+
+	att, err := s.attester.Attest(msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attestationResponse{
+		MessageHash: att.MessageHash,
+		Signer:      att.Signer,
+		Signature:   att.Signature,
+	})
+}
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/joho/godotenv"
@@ -11,18 +15,36 @@ import (
 )
 
 type Config struct {
-	Chains  []ChainConfig `yaml:"chains"`
-	Relayer RelayerConfig `yaml:"relayer"`
+	Chains        []ChainConfig     `yaml:"chains"`
+	Relayer       RelayerConfig     `yaml:"relayer"`
+	Admin         AdminConfig       `yaml:"admin"`
+	Policy        PolicyConfig      `yaml:"policy"`
+	RateLimits    RateLimitConfig   `yaml:"rate_limits"`
+	Fees          FeeConfig         `yaml:"fees"`
+	Attestation   AttestationConfig `yaml:"attestation"`
+	Proofs        ProofConfig       `yaml:"proofs"`
+	Optimistic    OptimisticConfig  `yaml:"optimistic"`
+	Watcher       WatcherConfig     `yaml:"watcher"`
+	FinalityRules []FinalityRule    `yaml:"finality_rules"`
+	RPC           RPCConfig         `yaml:"rpc"`
 }
 
+// ChainConfig describes one chain. RpcURLs lists fallback endpoints tried
+// after RpcURL; with RpcQuorum above one, logs and receipts must match on
+// that many endpoints before they are acted on.
 type ChainConfig struct {
-	Name           string `yaml:"name"`
-	ChainID        int64  `yaml:"chain_id"`
-	RpcURL         string `yaml:"rpc_url"`
-	SourceContract string `yaml:"source_contract"`
-	DestContract   string `yaml:"dest_contract"`
-	StartBlock     uint64 `yaml:"start_block"`
-	Confirmations  uint64 `yaml:"confirmations"`
+	Name            string   `yaml:"name"`
+	ChainID         int64    `yaml:"chain_id"`
+	RpcURL          string   `yaml:"rpc_url"`
+	RpcURLs         []string `yaml:"rpc_urls"`
+	RpcQuorum       int      `yaml:"rpc_quorum"`
+	SourceContract  string   `yaml:"source_contract"`
+	DestContract    string   `yaml:"dest_contract"`
+	StateRootOracle string   `yaml:"state_root_oracle"`
+	StartBlock      uint64   `yaml:"start_block"`
+	Finality        string   `yaml:"finality"`
+	Confirmations   uint64   `yaml:"confirmations"`
+	ReorgWindow     uint64   `yaml:"reorg_window"`
 }
 
 type RelayerConfig struct {
@@ -30,33 +52,231 @@ type RelayerConfig struct {
 	PollInterval string `yaml:"poll_interval"`
 	MaxRetries   int    `yaml:"max_retries"`
 	GasLimit     uint64 `yaml:"gas_limit"`
+	MaxExecGas   uint64 `yaml:"max_exec_gas"`
 	DBPath       string `yaml:"db_path"`
+	Retention    string `yaml:"retention"`
+	MetricsAddr  string `yaml:"metrics_addr"`
 }
 
+// AdminConfig controls the gRPC admin API. At least one of Token or
+// ClientCA must be set; with ClientCA the server requires mTLS.
+type AdminConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ListenAddr string `yaml:"listen_addr"`
+	Token      string `yaml:"token"`
+	TLSCert    string `yaml:"tls_cert"`
+	TLSKey     string `yaml:"tls_key"`
+	ClientCA   string `yaml:"client_ca"`
+}
+
+// PolicyConfig is evaluated before every relay. When Routes is empty all
+// routes are enabled; otherwise only the listed routes are.
+type PolicyConfig struct {
+	Paused         bool          `yaml:"paused"`
+	MaxPayloadSize int           `yaml:"max_payload_size"`
+	Routes         []RoutePolicy `yaml:"routes"`
+}
+
+type RoutePolicy struct {
+	SourceChainID  int64    `yaml:"source_chain_id"`
+	DestChainID    int64    `yaml:"dest_chain_id"`
+	Paused         bool     `yaml:"paused"`
+	Allowlist      []string `yaml:"allowlist"`
+	Denylist       []string `yaml:"denylist"`
+	MaxPayloadSize int      `yaml:"max_payload_size"`
+}
+
+// RateLimitConfig sets token buckets per sender address and per route.
+// Messages over the limit are deferred, not dropped.
+type RateLimitConfig struct {
+	Sender RateLimit `yaml:"sender"`
+	Route  RateLimit `yaml:"route"`
+}
+
+// RateLimit refills PerMinute tokens a minute up to Burst. A zero
+// PerMinute disables the limit.
+type RateLimit struct {
+	PerMinute float64 `yaml:"per_minute"`
+	Burst     int     `yaml:"burst"`
+}
+
+// FeeConfig holds underpaid messages back. NativePrices maps a chain ID to
+// the price of its native token in any common unit (e.g. USD), and Margin
+// multiplies the estimated cost.
+type FeeConfig struct {
+	Enabled      bool              `yaml:"enabled"`
+	Margin       float64           `yaml:"margin"`
+	NativePrices map[int64]float64 `yaml:"native_prices"`
+}
+
+// AttestationConfig turns on M-of-N delivery. Every relayer serves its
+// signature to peers on ListenAddr; the one with Submitter set collects
+// Threshold signatures from Validators and submits them.
+type AttestationConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	Submitter     bool     `yaml:"submitter"`
+	ListenAddr    string   `yaml:"listen_addr"`
+	Peers         []string `yaml:"peers"`
+	Validators    []string `yaml:"validators"`
+	Threshold     int      `yaml:"threshold"`
+	RetryInterval string   `yaml:"retry_interval"`
+}
+
+// ProofConfig turns on storage-proof delivery. Messages are delivered with
+// an eth_getProof proof of SourceMessenger.messageExists once the
+// destination chain's state_root_oracle has a source block at or after the
+// message's block.
+type ProofConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	RetryInterval string `yaml:"retry_interval"`
+}
+
+// FinalityRule requires stronger finality for messages on a route whose
+// fee paid (in wei) is at least MinValue. Finality is blocks (with
+// Confirmations), safe or finalized.
+type FinalityRule struct {
+	SourceChainID int64  `yaml:"source_chain_id"`
+	DestChainID   int64  `yaml:"dest_chain_id"`
+	MinValue      string `yaml:"min_value"`
+	Finality      string `yaml:"finality"`
+	Confirmations uint64 `yaml:"confirmations"`
+}
+
+// OptimisticConfig turns on optimistic delivery: the relayer proposes each
+// message and finalizes it once the destination's challenge period has
+// passed without a challenge.
+type OptimisticConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// WatcherConfig configures watcherd. Without a PrivateKey it only reports
+// messages missing on the source chain; with one it also challenges them.
+// Alerts go to the log, metrics and, if set, WebhookURL. Messages not
+// delivered within DeliverySLA are flagged too.
+type WatcherConfig struct {
+	PrivateKey  string `yaml:"private_key"`
+	Lookback    uint64 `yaml:"lookback"`
+	MetricsAddr string `yaml:"metrics_addr"`
+	WebhookURL  string `yaml:"webhook_url"`
+	DeliverySLA string `yaml:"delivery_sla"`
+}
+
+// RPCConfig throttles and batches JSON-RPC traffic. RateLimit is requests
+// per second allowed on each endpoint, refilling up to Burst; zero
+// disables it. Concurrent eth_call and receipt lookups arriving within
+// BatchWindow are sent as one batch request. CacheSize bounds the cache of
+// results that cannot change, such as calls pinned to a block hash.
+type RPCConfig struct {
+	RateLimit   float64 `yaml:"rate_limit"`
+	Burst       int     `yaml:"burst"`
+	BatchWindow string  `yaml:"batch_window"`
+	CacheSize   int     `yaml:"cache_size"`
+}
+
+// LoadConfig reads and validates the config at path. If anything is wrong
+// the error is a Problems listing all of it.
 func LoadConfig(path string) (*Config, error) {
+	cfg, problems, err := Check(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return cfg, nil
+}
+
+// Check reads the config at path and returns it with every problem found,
+// including keys that match no field. The config is returned even when it
+// has problems; err is only set if the file cannot be read or parsed.
+func Check(path string) (*Config, Problems, error) {
 	// Load .env file if it exists (ignore error if not found)
 	_ = godotenv.Load()
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
 	// Expand environment variables like ${VAR} or $VAR
 	expanded := os.ExpandEnv(string(data))
 
 	var cfg Config
-	if err := yaml.Unmarshal([]byte(expanded), &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+	var problems Problems
+	dec := yaml.NewDecoder(strings.NewReader(expanded))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		// Decoding carries on past type errors and unknown keys
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+		problems = append(problems, typeErr.Errors...)
+	}
+
+	return &cfg, append(problems, cfg.Validate()...), nil
+}
+
+// Redacted returns a copy of the config with secrets masked, safe to expose
+// over the admin API.
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.Chains = append([]ChainConfig(nil), c.Chains...)
+	for i := range out.Chains {
+		out.Chains[i].RpcURL = RedactURL(out.Chains[i].RpcURL)
+		urls := make([]string, len(out.Chains[i].RpcURLs))
+		for j, u := range out.Chains[i].RpcURLs {
+			urls[j] = RedactURL(u)
+		}
+		out.Chains[i].RpcURLs = urls
+	}
+	if out.Relayer.PrivateKey != "" {
+		out.Relayer.PrivateKey = "<redacted>"
 	}
+	if out.Watcher.PrivateKey != "" {
+		out.Watcher.PrivateKey = "<redacted>"
+	}
+	if out.Watcher.WebhookURL != "" {
+		out.Watcher.WebhookURL = RedactURL(out.Watcher.WebhookURL)
+	}
+	if out.Admin.Token != "" {
+		out.Admin.Token = "<redacted>"
+	}
+	return &out
+}
 
-	return &cfg, nil
+// RedactURL keeps only scheme and host, since provider API keys usually
+// live in the path or query string.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "<redacted>"
+	}
+	if u.Path == "" && u.RawQuery == "" && u.User == nil {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host + "/<redacted>"
 }
 
 func (c *ChainConfig) GetChainID() *big.Int {
 	return big.NewInt(c.ChainID)
 }
 
+// GetRpcURLs returns RpcURL followed by RpcURLs, skipping empty entries
+// and duplicates.
+func (c *ChainConfig) GetRpcURLs() []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range append([]string{c.RpcURL}, c.RpcURLs...) {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls
+}
+
 func (c *ChainConfig) GetSourceContract() common.Address {
 	return common.HexToAddress(c.SourceContract)
 }
@@ -64,3 +284,7 @@ func (c *ChainConfig) GetSourceContract() common.Address {
 func (c *ChainConfig) GetDestContract() common.Address {
 	return common.HexToAddress(c.DestContract)
 }
+
+func (c *ChainConfig) GetStateRootOracle() common.Address {
+	return common.HexToAddress(c.StateRootOracle)
+}
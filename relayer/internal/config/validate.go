@@ -0,0 +1,296 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultPollInterval is how often heads are polled on endpoints without
+// subscription support when relayer.poll_interval is not set.
+const DefaultPollInterval = 5 * time.Second
+
+// DefaultMaxExecGas is the most gas a targeted message may ask its receiver
+// call to be given when relayer.max_exec_gas is not set.
+const DefaultMaxExecGas = 1_000_000
+
+// DefaultRetention is how long delivered messages stay in the store.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// Problems lists everything wrong with a config, so it can be fixed in one
+// pass rather than one restart per mistake.
+type Problems []string
+
+func (p Problems) Error() string {
+	if len(p) == 1 {
+		return "invalid config: " + p[0]
+	}
+	return fmt.Sprintf("invalid config (%d problems):\n  - %s", len(p), strings.Join(p, "\n  - "))
+}
+
+func (p *Problems) add(format string, args ...any) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the config without touching the network and returns
+// every problem found. The relayer key is only checked if set, since
+// watcherd runs from the same file without it.
+func (c *Config) Validate() Problems {
+	var p Problems
+
+	if len(c.Chains) == 0 {
+		p.add("chains: at least one chain is required")
+	}
+	ids := make(map[int64]string, len(c.Chains))
+	for i := range c.Chains {
+		chain := &c.Chains[i]
+		field := fmt.Sprintf("chains[%d]", i)
+		if chain.Name != "" {
+			field = fmt.Sprintf("chains[%s]", chain.Name)
+		} else {
+			p.add("%s.name is required", field)
+		}
+
+		if chain.ChainID <= 0 {
+			p.add("%s.chain_id must be positive", field)
+		} else if other, dup := ids[chain.ChainID]; dup {
+			p.add("%s.chain_id %d is already used by %s", field, chain.ChainID, other)
+		} else {
+			ids[chain.ChainID] = field
+		}
+
+		urls := chain.GetRpcURLs()
+		if len(urls) == 0 {
+			p.add("%s.rpc_url is required", field)
+		}
+		for _, u := range urls {
+			checkRPCURL(&p, field, u, len(urls) > 1)
+		}
+		if len(urls) > 0 && (chain.RpcQuorum < 0 || chain.RpcQuorum > len(urls)) {
+			p.add("%s.rpc_quorum must be between 1 and the number of endpoints (%d)", field, len(urls))
+		}
+
+		checkAddress(&p, field+".source_contract", chain.SourceContract, true)
+		checkAddress(&p, field+".dest_contract", chain.DestContract, true)
+		checkAddress(&p, field+".state_root_oracle", chain.StateRootOracle, c.Proofs.Enabled)
+		checkFinality(&p, field+".finality", chain.Finality)
+	}
+
+	checkKey(&p, "relayer.private_key", c.Relayer.PrivateKey)
+	checkDuration(&p, "relayer.poll_interval", c.Relayer.PollInterval)
+	if c.Relayer.MaxRetries < 0 {
+		p.add("relayer.max_retries must not be negative")
+	}
+	if c.Relayer.GasLimit == 0 {
+		p.add("relayer.gas_limit is required")
+	}
+	if c.Relayer.DBPath == "" {
+		p.add("relayer.db_path is required")
+	}
+	checkDuration(&p, "relayer.retention", c.Relayer.Retention)
+
+	if c.Admin.Enabled {
+		if c.Admin.ListenAddr == "" {
+			p.add("admin.listen_addr is required when the admin API is enabled")
+		}
+		if c.Admin.Token == "" && c.Admin.ClientCA == "" {
+			p.add("admin needs a token or client_ca")
+		}
+		if (c.Admin.TLSCert == "") != (c.Admin.TLSKey == "") {
+			p.add("admin.tls_cert and admin.tls_key must be set together")
+		}
+		if c.Admin.ClientCA != "" && c.Admin.TLSCert == "" {
+			p.add("admin.client_ca requires tls_cert and tls_key")
+		}
+	}
+
+	routes := make(map[[2]int64]bool, len(c.Policy.Routes))
+	for i, r := range c.Policy.Routes {
+		field := fmt.Sprintf("policy.routes[%d]", i)
+		route := [2]int64{r.SourceChainID, r.DestChainID}
+		if routes[route] {
+			p.add("%s: duplicate policy for route %d->%d", field, r.SourceChainID, r.DestChainID)
+		}
+		routes[route] = true
+		for j, a := range r.Allowlist {
+			checkAddress(&p, fmt.Sprintf("%s.allowlist[%d]", field, j), a, true)
+		}
+		for j, a := range r.Denylist {
+			checkAddress(&p, fmt.Sprintf("%s.denylist[%d]", field, j), a, true)
+		}
+	}
+
+	for name, limit := range map[string]RateLimit{"sender": c.RateLimits.Sender, "route": c.RateLimits.Route} {
+		if limit.PerMinute < 0 || limit.Burst < 0 {
+			p.add("rate_limits.%s must not be negative", name)
+		}
+	}
+
+	if c.Fees.Margin < 0 {
+		p.add("fees.margin must not be negative")
+	}
+	for chainID, price := range c.Fees.NativePrices {
+		if price <= 0 {
+			p.add("fees.native_prices[%d] must be positive", chainID)
+		}
+	}
+	if c.Fees.Enabled {
+		// Without a price, messages on the chain's routes are never checked
+		for _, chain := range c.Chains {
+			if _, ok := c.Fees.NativePrices[chain.ChainID]; !ok && chain.ChainID > 0 {
+				p.add("fees.native_prices has no price for chain %d (%s)", chain.ChainID, chain.Name)
+			}
+		}
+	}
+
+	if c.Attestation.Enabled {
+		if c.Attestation.ListenAddr == "" {
+			p.add("attestation.listen_addr is required when attestation is enabled")
+		}
+		if c.Attestation.Threshold < 1 || c.Attestation.Threshold > len(c.Attestation.Validators) {
+			p.add("attestation.threshold must be between 1 and the number of validators (%d)", len(c.Attestation.Validators))
+		}
+		for i, v := range c.Attestation.Validators {
+			checkAddress(&p, fmt.Sprintf("attestation.validators[%d]", i), v, true)
+		}
+	}
+	checkDuration(&p, "attestation.retry_interval", c.Attestation.RetryInterval)
+	checkDuration(&p, "proofs.retry_interval", c.Proofs.RetryInterval)
+	if c.Optimistic.Enabled && (c.Attestation.Enabled || c.Proofs.Enabled) {
+		p.add("optimistic delivery cannot be combined with attestation or proofs")
+	} else if c.Attestation.Enabled && c.Proofs.Enabled {
+		p.add("attestation and proofs cannot both be enabled")
+	}
+
+	checkKey(&p, "watcher.private_key", c.Watcher.PrivateKey)
+	checkDuration(&p, "watcher.delivery_sla", c.Watcher.DeliverySLA)
+	if c.Watcher.WebhookURL != "" {
+		if u, err := url.Parse(c.Watcher.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			p.add("watcher.webhook_url must be an http(s) URL")
+		}
+	}
+
+	for i, r := range c.FinalityRules {
+		field := fmt.Sprintf("finality_rules[%d]", i)
+		for _, id := range []int64{r.SourceChainID, r.DestChainID} {
+			if _, ok := ids[id]; !ok && id != 0 {
+				p.add("%s: chain %d is not configured", field, id)
+			}
+		}
+		if r.MinValue != "" {
+			if v, ok := new(big.Int).SetString(r.MinValue, 10); !ok || v.Sign() < 0 {
+				p.add("%s.min_value %q is not a wei amount", field, r.MinValue)
+			}
+		}
+		checkFinality(&p, field+".finality", r.Finality)
+	}
+
+	if c.RPC.RateLimit < 0 || c.RPC.Burst < 0 || c.RPC.CacheSize < 0 {
+		p.add("rpc.rate_limit, burst and cache_size must not be negative")
+	}
+	checkDuration(&p, "rpc.batch_window", c.RPC.BatchWindow)
+
+	return p
+}
+
+// checkAddress rejects values that common.HexToAddress would quietly turn
+// into the wrong address: non-hex placeholders, the zero address and
+// mixed-case addresses with a bad EIP-55 checksum.
+func checkAddress(p *Problems, field, value string, required bool) {
+	if value == "" {
+		if required {
+			p.add("%s is required", field)
+		}
+		return
+	}
+	if !common.IsHexAddress(value) {
+		p.add("%s %q is not an address", field, value)
+		return
+	}
+	addr := common.HexToAddress(value)
+	if addr == (common.Address{}) {
+		p.add("%s is the zero address", field)
+		return
+	}
+	digits := value[len(value)-40:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr.Hex()[2:] != digits {
+		p.add("%s %s has an invalid checksum (want %s)", field, value, addr.Hex())
+	}
+}
+
+func checkKey(p *Problems, field, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := crypto.HexToECDSA(value); err != nil {
+		p.add("%s is not a valid private key", field)
+	}
+}
+
+func checkDuration(p *Problems, field, value string) {
+	if value == "" {
+		return
+	}
+	if d, err := time.ParseDuration(value); err != nil {
+		p.add("%s %q is not a duration (e.g. 5s, 1m)", field, value)
+	} else if d <= 0 {
+		p.add("%s must be positive", field)
+	}
+}
+
+func checkFinality(p *Problems, field, value string) {
+	switch value {
+	case "", "blocks", "safe", "finalized":
+	default:
+		p.add("%s %q is unknown (want blocks, safe or finalized)", field, value)
+	}
+}
+
+// checkRPCURL checks an endpoint URL. Only http(s) endpoints can be pooled,
+// so ws(s) is only accepted as a chain's single endpoint.
+func checkRPCURL(p *Problems, field, value string, pooled bool) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		p.add("%s has an invalid RPC URL %s", field, RedactURL(value))
+		return
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "ws", "wss":
+		if pooled {
+			p.add("%s RPC URL %s must be http(s) when a chain has several endpoints", field, RedactURL(value))
+		}
+	default:
+		p.add("%s RPC URL %s must be http(s) or ws(s)", field, RedactURL(value))
+	}
+}
+
+// GetPollInterval returns how often the listener polls for new heads on
+// endpoints without subscriptions.
+func (c *RelayerConfig) GetPollInterval() time.Duration {
+	if d, err := time.ParseDuration(c.PollInterval); err == nil && d > 0 {
+		return d
+	}
+	return DefaultPollInterval
+}
+
+// GetMaxExecGas returns the most gas the relayer forwards to a target.
+func (c *RelayerConfig) GetMaxExecGas() uint64 {
+	if c.MaxExecGas > 0 {
+		return c.MaxExecGas
+	}
+	return DefaultMaxExecGas
+}
+
+// GetRetention returns how long delivered messages are kept in the store.
+func (c *RelayerConfig) GetRetention() time.Duration {
+	if d, err := time.ParseDuration(c.Retention); err == nil && d > 0 {
+		return d
+	}
+	return DefaultRetention
+}
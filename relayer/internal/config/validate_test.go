@@ -0,0 +1,184 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testKey     = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcaf784d7bf4f2ff80"
+	testAddress = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+)
+
+// validConfig returns a config with no problems, for tests to break one
+// field at a time.
+func validConfig() *Config {
+	return &Config{
+		Chains: []ChainConfig{
+			{Name: "sepolia", ChainID: 11155111, RpcURL: "https://sepolia.example", SourceContract: testAddress, DestContract: testAddress},
+			{Name: "amoy", ChainID: 80002, RpcURL: "wss://amoy.example", SourceContract: testAddress, DestContract: testAddress},
+		},
+		Relayer: RelayerConfig{PrivateKey: testKey, GasLimit: 500000, DBPath: "relayer.db"},
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	if p := validConfig().Validate(); len(p) != 0 {
+		t.Fatalf("Validate = %v, want no problems", p)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"no chains", func(c *Config) { c.Chains = nil }, "at least one chain is required"},
+		{"no name", func(c *Config) { c.Chains[0].Name = "" }, "chains[0].name is required"},
+		{"bad chain id", func(c *Config) { c.Chains[0].ChainID = 0 }, "chains[sepolia].chain_id must be positive"},
+		{"duplicate chain id", func(c *Config) { c.Chains[1].ChainID = 11155111 }, "chain_id 11155111 is already used by chains[sepolia]"},
+		{"no rpc url", func(c *Config) { c.Chains[0].RpcURL = "" }, "chains[sepolia].rpc_url is required"},
+		{"invalid rpc url", func(c *Config) { c.Chains[0].RpcURL = "sepolia" }, "has an invalid RPC URL"},
+		{"bad rpc scheme", func(c *Config) { c.Chains[0].RpcURL = "ftp://sepolia.example" }, "must be http(s) or ws(s)"},
+		{"pooled ws", func(c *Config) { c.Chains[1].RpcURLs = []string{"https://amoy.example"} }, "must be http(s) when a chain has several endpoints"},
+		{"quorum over endpoints", func(c *Config) { c.Chains[0].RpcQuorum = 2 }, "rpc_quorum must be between 1 and the number of endpoints (1)"},
+		{"negative quorum", func(c *Config) { c.Chains[0].RpcQuorum = -1 }, "rpc_quorum must be between"},
+		{"no source contract", func(c *Config) { c.Chains[0].SourceContract = "" }, "chains[sepolia].source_contract is required"},
+		{"placeholder dest contract", func(c *Config) { c.Chains[0].DestContract = "0xYourContract" }, "is not an address"},
+		{"zero address", func(c *Config) { c.Chains[0].DestContract = "0x0000000000000000000000000000000000000000" }, "is the zero address"},
+		{"bad checksum", func(c *Config) { c.Chains[0].SourceContract = "0x5fbDB2315678afecb367f032d93F642f64180aa3" }, "has an invalid checksum (want " + testAddress + ")"},
+		{"oracle required with proofs", func(c *Config) {
+			c.Proofs.Enabled = true
+			c.Chains[1].StateRootOracle = testAddress
+		}, "chains[sepolia].state_root_oracle is required"},
+		{"unknown finality", func(c *Config) { c.Chains[0].Finality = "latest" }, `chains[sepolia].finality "latest" is unknown`},
+		{"bad relayer key", func(c *Config) { c.Relayer.PrivateKey = "0x1234" }, "relayer.private_key is not a valid private key"},
+		{"bad poll interval", func(c *Config) { c.Relayer.PollInterval = "5" }, `relayer.poll_interval "5" is not a duration`},
+		{"negative poll interval", func(c *Config) { c.Relayer.PollInterval = "-5s" }, "relayer.poll_interval must be positive"},
+		{"negative retries", func(c *Config) { c.Relayer.MaxRetries = -1 }, "relayer.max_retries must not be negative"},
+		{"no gas limit", func(c *Config) { c.Relayer.GasLimit = 0 }, "relayer.gas_limit is required"},
+		{"no db path", func(c *Config) { c.Relayer.DBPath = "" }, "relayer.db_path is required"},
+		{"bad retention", func(c *Config) { c.Relayer.Retention = "7d" }, `relayer.retention "7d" is not a duration`},
+		{"admin without addr", func(c *Config) { c.Admin = AdminConfig{Enabled: true, Token: "t"} }, "admin.listen_addr is required"},
+		{"admin without auth", func(c *Config) { c.Admin = AdminConfig{Enabled: true, ListenAddr: ":9090"} }, "admin needs a token or client_ca"},
+		{"admin cert without key", func(c *Config) {
+			c.Admin = AdminConfig{Enabled: true, ListenAddr: ":9090", Token: "t", TLSCert: "cert.pem"}
+		}, "admin.tls_cert and admin.tls_key must be set together"},
+		{"admin client ca without tls", func(c *Config) {
+			c.Admin = AdminConfig{Enabled: true, ListenAddr: ":9090", ClientCA: "ca.pem"}
+		}, "admin.client_ca requires tls_cert and tls_key"},
+		{"duplicate policy route", func(c *Config) {
+			c.Policy.Routes = []RoutePolicy{{SourceChainID: 1, DestChainID: 2}, {SourceChainID: 1, DestChainID: 2}}
+		}, "policy.routes[1]: duplicate policy for route 1->2"},
+		{"bad allowlist", func(c *Config) {
+			c.Policy.Routes = []RoutePolicy{{SourceChainID: 1, DestChainID: 2, Allowlist: []string{"alice"}}}
+		}, `policy.routes[0].allowlist[0] "alice" is not an address`},
+		{"bad denylist", func(c *Config) {
+			c.Policy.Routes = []RoutePolicy{{SourceChainID: 1, DestChainID: 2, Denylist: []string{"bob"}}}
+		}, `policy.routes[0].denylist[0] "bob" is not an address`},
+		{"negative rate limit", func(c *Config) { c.RateLimits.Sender.Burst = -1 }, "rate_limits.sender must not be negative"},
+		{"negative margin", func(c *Config) { c.Fees.Margin = -0.1 }, "fees.margin must not be negative"},
+		{"missing native price", func(c *Config) {
+			c.Fees = FeeConfig{Enabled: true, NativePrices: map[int64]float64{11155111: 2500}}
+		}, "fees.native_prices has no price for chain 80002 (amoy)"},
+		{"zero native price", func(c *Config) { c.Fees.NativePrices = map[int64]float64{1: 0} }, "fees.native_prices[1] must be positive"},
+		{"attestation without addr", func(c *Config) {
+			c.Attestation = AttestationConfig{Enabled: true, Threshold: 1, Validators: []string{testAddress}}
+		}, "attestation.listen_addr is required"},
+		{"attestation threshold", func(c *Config) {
+			c.Attestation = AttestationConfig{Enabled: true, ListenAddr: ":9292", Threshold: 2, Validators: []string{testAddress}}
+		}, "attestation.threshold must be between 1 and the number of validators (1)"},
+		{"bad validator", func(c *Config) {
+			c.Attestation = AttestationConfig{Enabled: true, ListenAddr: ":9292", Threshold: 1, Validators: []string{"validator"}}
+		}, `attestation.validators[0] "validator" is not an address`},
+		{"bad attestation retry", func(c *Config) { c.Attestation.RetryInterval = "soon" }, `attestation.retry_interval "soon" is not a duration`},
+		{"bad proofs retry", func(c *Config) { c.Proofs.RetryInterval = "0s" }, "proofs.retry_interval must be positive"},
+		{"optimistic with proofs", func(c *Config) {
+			c.Optimistic.Enabled = true
+			c.Proofs.Enabled = true
+			for i := range c.Chains {
+				c.Chains[i].StateRootOracle = testAddress
+			}
+		}, "optimistic delivery cannot be combined with attestation or proofs"},
+		{"attestation with proofs", func(c *Config) {
+			c.Attestation = AttestationConfig{Enabled: true, ListenAddr: ":9292", Threshold: 1, Validators: []string{testAddress}}
+			c.Proofs.Enabled = true
+			for i := range c.Chains {
+				c.Chains[i].StateRootOracle = testAddress
+			}
+		}, "attestation and proofs cannot both be enabled"},
+		{"bad watcher key", func(c *Config) { c.Watcher.PrivateKey = "watcher" }, "watcher.private_key is not a valid private key"},
+		{"bad delivery sla", func(c *Config) { c.Watcher.DeliverySLA = "1 hour" }, `watcher.delivery_sla "1 hour" is not a duration`},
+		{"bad webhook", func(c *Config) { c.Watcher.WebhookURL = "slack://alerts" }, "watcher.webhook_url must be an http(s) URL"},
+		{"finality rule unknown chain", func(c *Config) {
+			c.FinalityRules = []FinalityRule{{SourceChainID: 11155111, DestChainID: 1}}
+		}, "finality_rules[0]: chain 1 is not configured"},
+		{"finality rule min value", func(c *Config) {
+			c.FinalityRules = []FinalityRule{{SourceChainID: 11155111, DestChainID: 80002, MinValue: "1 ether"}}
+		}, `finality_rules[0].min_value "1 ether" is not a wei amount`},
+		{"finality rule mode", func(c *Config) {
+			c.FinalityRules = []FinalityRule{{SourceChainID: 11155111, DestChainID: 80002, Finality: "final"}}
+		}, `finality_rules[0].finality "final" is unknown`},
+		{"negative rpc budget", func(c *Config) { c.RPC.RateLimit = -1 }, "rpc.rate_limit, burst and cache_size must not be negative"},
+		{"bad batch window", func(c *Config) { c.RPC.BatchWindow = "10" }, `rpc.batch_window "10" is not a duration`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(c)
+
+			p := c.Validate()
+			if len(p) != 1 {
+				t.Fatalf("Validate = %v, want one problem containing %q", p, tt.want)
+			}
+			if !strings.Contains(p[0], tt.want) {
+				t.Errorf("Validate = %q, want it to contain %q", p[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	c := validConfig()
+	c.Relayer.GasLimit = 0
+	c.Relayer.DBPath = ""
+	c.Chains[1].Name = ""
+
+	p := c.Validate()
+	if len(p) != 3 {
+		t.Fatalf("Validate = %v, want 3 problems", p)
+	}
+	if !strings.HasPrefix(p.Error(), "invalid config (3 problems):") {
+		t.Errorf("Error() = %q", p.Error())
+	}
+}
+
+func TestCheckReportsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := `chains:
+  - name: sepolia
+    chain_id: 11155111
+    rpc_url: https://sepolia.example
+    source_contract: ` + testAddress + `
+    dest_contract: ` + testAddress + `
+relayer:
+  gas_limit: 500000
+  db_path: relayer.db
+  gas_limt: 1
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, p, err := Check(path)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(p) != 1 || !strings.Contains(p[0], "gas_limt") {
+		t.Fatalf("Check = %v, want the unknown key gas_limt", p)
+	}
+}
@@ -2,13 +2,27 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"relayer/internal/config"
+	"relayer/internal/attestation"
+	"relayer/internal/fees"
+	"relayer/internal/finality"
+	"relayer/internal/metrics"
+	"relayer/internal/policy"
+	"relayer/internal/proof"
+	"relayer/internal/ratelimit"
+	"relayer/internal/registry"
 	"relayer/internal/signer"
+	"relayer/internal/store"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 
@@ -16,58 +30,324 @@ import (
 	"relayer/pkg/contracts"
 )
 
+// ErrChallengeWindowOpen is returned for a proposed message whose challenge
+// window has not passed yet.
+var ErrChallengeWindowOpen = errors.New("challenge window still open")
+
+// finalityRetryInterval is how often a message waiting for stronger
+// finality is checked again.
+const finalityRetryInterval = 30 * time.Second
+
+// feeRetryInterval is how often a message whose fee could not be checked,
+// e.g. because gas price lookups failed, is checked again.
+const feeRetryInterval = 30 * time.Second
+
+// finalizeMargin covers clock skew between us and the destination chain.
+const finalizeMargin = 15 * time.Second
+
 type Executor struct {
-	clients     map[int64]*ethclient.Client
-	chains      map[int64]*config.ChainConfig
+	registry    *registry.Registry
 	signer      *signer.Signer
-	maxRetries  int
-	gasLimit    uint64
+	maxRetries  atomic.Int64
+	gasLimit    atomic.Uint64
+	maxExecGas  atomic.Uint64
 	messageChan chan *customTypes.CrossChainMessage
+	store       *store.Store
+	policy      *policy.Engine
+	limiter     *ratelimit.Limiter
+	fees        *fees.Checker
+	finality    *finality.Checker
+	attestation *attestation.Collector
+	proofs      *proof.Prover
+	optimistic  bool
+
+	// Messages waiting on a deferMessage timer, which RequeuePending skips
+	// so they are not queued twice
+	deferredMu sync.Mutex
+	deferred   map[common.Hash]bool
 }
 
 func NewExecutor(
-	clients map[int64]*ethclient.Client,
-	chains map[int64]*config.ChainConfig,
+	registry *registry.Registry,
 	signer *signer.Signer,
+	store *store.Store,
+	policy *policy.Engine,
+	limiter *ratelimit.Limiter,
+	fees *fees.Checker,
+	finality *finality.Checker,
+	attestation *attestation.Collector,
+	proofs *proof.Prover,
+	optimistic bool,
 	maxRetries int,
 	gasLimit uint64,
+	maxExecGas uint64,
 	messageChan chan *customTypes.CrossChainMessage,
 ) *Executor {
-	return &Executor{
-		clients:     clients,
-		chains:      chains,
+	e := &Executor{
+		registry:    registry,
 		signer:      signer,
-		maxRetries:  maxRetries,
-		gasLimit:    gasLimit,
+		store:       store,
+		policy:      policy,
+		limiter:     limiter,
+		fees:        fees,
+		finality:    finality,
+		attestation: attestation,
+		proofs:      proofs,
+		optimistic:  optimistic,
 		messageChan: messageChan,
+		deferred:    make(map[common.Hash]bool),
 	}
+	e.SetLimits(maxRetries, gasLimit, maxExecGas)
+	return e
+}
+
+// SetLimits updates the retry and gas limits, e.g. after a config reload.
+func (e *Executor) SetLimits(maxRetries int, gasLimit, maxExecGas uint64) {
+	e.maxRetries.Store(int64(maxRetries))
+	e.gasLimit.Store(gasLimit)
+	e.maxExecGas.Store(maxExecGas)
 }
 
 func (e *Executor) Start(ctx context.Context) error {
 	log.Println("Starting executor...")
 
+	// Pick up messages left pending by a previous run
+	go func() {
+		if err := e.RequeuePending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf(" Failed to requeue pending messages: %v", err)
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case msg := <-e.messageChan:
-			if err := e.processMessage(ctx, msg); err != nil {
+			if e.orphaned(msg) {
+				continue
+			}
+			if !e.routeConfigured(msg) {
+				// Kept pending until the chain is configured again
+				log.Printf(" Chain of message %s not configured, holding it", msg.MessageHash.Hex())
+				if msg.Status != customTypes.StatusProposed {
+					msg.Status = customTypes.StatusPending
+				}
+				e.save(msg)
+				continue
+			}
+			switch e.policy.Evaluate(msg).Action {
+			case policy.Hold:
+				// Keep it pending; RequeuePending picks it up later
+				msg.Status = customTypes.StatusPending
+				e.save(msg)
+				continue
+			case policy.Reject:
+				msg.Status = customTypes.StatusRejected
+				e.save(msg)
+				continue
+			}
+			if msg.ExecGasLimit > e.maxExecGas.Load() {
+				// The sender picks the gas; we would pay for it
+				log.Printf(" Message %s asks for %d execution gas, over the %d limit, rejecting it",
+					msg.MessageHash.Hex(), msg.ExecGasLimit, e.maxExecGas.Load())
+				msg.Status = customTypes.StatusRejected
+				e.save(msg)
+				continue
+			}
+			if final, err := e.finality.Final(ctx, msg); err != nil {
+				log.Printf(" Failed to check finality: %v", err)
+				e.deferMessage(ctx, msg, finalityRetryInterval, "finality unknown")
+				continue
+			} else if !final {
+				e.deferMessage(ctx, msg, finalityRetryInterval, "awaiting finality")
+				continue
+			}
+			if funded, err := e.checkFee(ctx, msg); err != nil {
+				log.Printf(" Failed to check fee: %v", err)
+				e.deferMessage(ctx, msg, feeRetryInterval, "fee unknown")
+				continue
+			} else if !funded {
+				msg.Status = customTypes.StatusUnderfunded
+				e.save(msg)
+				continue
+			}
+			if e.attestation != nil && !e.attestation.Submitter() {
+				// Peers collect our signature through the attestation API,
+				// which only signs messages that got this far
+				msg.Status = customTypes.StatusAttested
+				e.save(msg)
+				continue
+			}
+			// Proposed messages were charged when they were proposed
+			if msg.Status != customTypes.StatusProposed {
+				if delay := e.limiter.Reserve(msg); delay > 0 {
+					e.deferMessage(ctx, msg, delay, "rate limited")
+					continue
+				}
+			}
+			if err := e.processMessage(ctx, msg); errors.Is(err, attestation.ErrInsufficientSignatures) {
+				log.Printf(" %v", err)
+				e.deferMessage(ctx, msg, e.attestation.RetryInterval(), "awaiting attestations")
+				continue
+			} else if errors.Is(err, proof.ErrBlockNotAvailable) {
+				log.Printf(" %v", err)
+				e.deferMessage(ctx, msg, e.proofs.RetryInterval(), "awaiting state root")
+				continue
+			} else if errors.Is(err, ErrChallengeWindowOpen) {
+				e.deferMessage(ctx, msg, time.Until(*msg.ExecutableAt)+finalizeMargin, "challenge window open")
+				continue
+			} else if err != nil {
 				log.Printf(" Failed to process message: %v", err)
+				msg.Status = customTypes.StatusFailed
 				// Implement retry logic here
 			}
+			e.save(msg)
 		}
 	}
 }
 
+// Requeue sends a stored message back to the executor regardless of its
+// current status. Delivery is still skipped if the destination has it.
+func (e *Executor) Requeue(ctx context.Context, hash common.Hash) (*customTypes.CrossChainMessage, error) {
+	msg, err := e.store.GetMessage(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Status = customTypes.StatusPending
+	msg.RetryCount++
+	now := time.Now()
+	msg.LastRetryAt = &now
+
+	if err := e.enqueue(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RequeuePending re-enqueues held, underfunded and proposed messages. Call
+// it after a pause is lifted or the policy or fee config is reloaded;
+// messages still held are evaluated again. Deferred messages are left to
+// their timers.
+func (e *Executor) RequeuePending(ctx context.Context) error {
+	statuses := []customTypes.MessageStatus{customTypes.StatusPending, customTypes.StatusUnderfunded, customTypes.StatusProposed}
+	for _, status := range statuses {
+		for _, msg := range e.store.ListMessages(status) {
+			if e.isDeferred(msg.MessageHash) {
+				continue
+			}
+			if err := e.enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkFee reports whether the message paid enough to cover delivery.
+// Always true when fee checks are disabled.
+func (e *Executor) checkFee(ctx context.Context, msg *customTypes.CrossChainMessage) (bool, error) {
+	if !e.fees.Enabled() {
+		return true, nil
+	}
+
+	client, ok := e.registry.Client(msg.DestChainID.Int64())
+	if !ok {
+		return false, fmt.Errorf("no client for chain %s", msg.DestChainID)
+	}
+
+	quote, err := e.fees.Check(ctx, client, msg, e.gasLimit.Load()+msg.ExecGasLimit)
+	if err != nil {
+		return false, err
+	}
+
+	if !quote.Sufficient() {
+		log.Printf(" Message %s underfunded: paid %s, required %s",
+			msg.MessageHash.Hex(), quote.Paid, quote.Required)
+		metrics.UnderfundedMessages.WithLabelValues(msg.SourceChainID.String(), msg.DestChainID.String()).Inc()
+		return false, nil
+	}
+	return true, nil
+}
+
+// deferMessage keeps a message pending (or proposed) and re-enqueues it
+// after delay, e.g. once its rate limit buckets have refilled.
+func (e *Executor) deferMessage(ctx context.Context, msg *customTypes.CrossChainMessage, delay time.Duration, reason string) {
+	log.Printf(" %s, deferring message %s by %s", reason, msg.MessageHash.Hex(), delay.Round(time.Second))
+	if msg.Status != customTypes.StatusProposed {
+		msg.Status = customTypes.StatusPending
+	}
+	e.save(msg)
+
+	e.setDeferred(msg.MessageHash, true)
+	time.AfterFunc(delay, func() {
+		e.setDeferred(msg.MessageHash, false)
+		if err := e.enqueue(ctx, msg); err != nil && ctx.Err() == nil {
+			log.Printf(" Failed to requeue deferred message %s: %v", msg.MessageHash.Hex(), err)
+		}
+	})
+}
+
+func (e *Executor) setDeferred(hash common.Hash, deferred bool) {
+	e.deferredMu.Lock()
+	defer e.deferredMu.Unlock()
+	if deferred {
+		e.deferred[hash] = true
+	} else {
+		delete(e.deferred, hash)
+	}
+}
+
+func (e *Executor) isDeferred(hash common.Hash) bool {
+	e.deferredMu.Lock()
+	defer e.deferredMu.Unlock()
+	return e.deferred[hash]
+}
+
+// routeConfigured reports whether both chains of the message are in the
+// running config. A reload may have removed one while the message was
+// queued.
+func (e *Executor) routeConfigured(msg *customTypes.CrossChainMessage) bool {
+	_, source := e.registry.Chain(msg.SourceChainID.Int64())
+	_, dest := e.registry.Chain(msg.DestChainID.Int64())
+	return source && dest
+}
+
+// orphaned reports whether the listener has dropped the message after a
+// source chain reorg since it was queued.
+func (e *Executor) orphaned(msg *customTypes.CrossChainMessage) bool {
+	stored, err := e.store.GetMessage(msg.MessageHash)
+	if err != nil || stored.Status != customTypes.StatusOrphaned {
+		return false
+	}
+	log.Printf(" Skipping orphaned message %s", msg.MessageHash.Hex())
+	return true
+}
+
+func (e *Executor) enqueue(ctx context.Context, msg *customTypes.CrossChainMessage) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case e.messageChan <- msg:
+		return nil
+	}
+}
+
+func (e *Executor) save(msg *customTypes.CrossChainMessage) {
+	if err := e.store.SaveMessage(msg); err != nil {
+		log.Printf(" Failed to save message %s: %v", msg.MessageHash.Hex(), err)
+	}
+}
+
 func (e *Executor) processMessage(ctx context.Context, msg *customTypes.CrossChainMessage) error {
 	destChainID := msg.DestChainID.Int64()
 
-	client, ok := e.clients[destChainID]
+	client, ok := e.registry.Client(destChainID)
 	if !ok {
 		return fmt.Errorf("no client for chain %d", destChainID)
 	}
 
-	chainConfig, ok := e.chains[destChainID]
+	chainConfig, ok := e.registry.Chain(destChainID)
 	if !ok {
 		return fmt.Errorf("no config for chain %d", destChainID)
 	}
@@ -88,28 +368,49 @@ func (e *Executor) processMessage(ctx context.Context, msg *customTypes.CrossCha
 
 	if processed {
 		log.Printf(" Message already processed: %s", msg.MessageHash.Hex())
+		msg.Status = customTypes.StatusCompleted
 		return nil
 	}
 
+	// In optimistic mode the message is proposed first and finalized by the
+	// submission below once its challenge window has passed
+	if e.optimistic {
+		ready, err := e.propose(ctx, client, destContract, msg)
+		if err != nil || !ready {
+			return err
+		}
+	}
+
+	// In attestation mode the validator signatures authorise delivery
+	var signatures [][]byte
+	if e.attestation != nil {
+		signatures, err = e.attestation.Collect(ctx, msg)
+		if err != nil {
+			return err
+		}
+	}
+
+	// In proof mode a storage proof of the source message authorises delivery
+	var proofData []byte
+	if e.proofs != nil {
+		proofData, err = e.proofs.Prove(ctx, msg)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get transactor
 	auth, err := e.signer.GetTransactor(msg.DestChainID)
 	if err != nil {
 		return fmt.Errorf("failed to get transactor: %w", err)
 	}
 
-	auth.GasLimit = e.gasLimit
+	auth.GasLimit = e.gasLimit.Load()
 
 	log.Printf(" Relaying message to chain %d...", destChainID)
+	msg.Status = customTypes.StatusRelaying
 
-	// Send transaction
-	tx, err := destContract.ReceiveMessage(
-		auth,
-		msg.Nonce,
-		msg.SourceChainID,
-		msg.Sender,
-		msg.Payload,
-		msg.Timestamp,
-	)
+	tx, err := e.submit(auth, destContract, msg, signatures, proofData)
 	if err != nil {
 		return fmt.Errorf("failed to send transaction: %w", err)
 	}
@@ -128,6 +429,7 @@ func (e *Executor) processMessage(ctx context.Context, msg *customTypes.CrossCha
 		msg.DestTxHash = tx.Hash()
 		now := time.Now()
 		msg.ProcessedAt = &now
+		e.checkExecution(destContract, receipt, msg)
 	} else {
 		return fmt.Errorf("transaction reverted")
 	}
@@ -135,6 +437,137 @@ func (e *Executor) processMessage(ctx context.Context, msg *customTypes.CrossCha
 	return nil
 }
 
+// submit picks the delivery method: targeted messages call the receiver and
+// need gas for it, and attested messages carry validator signatures.
+func (e *Executor) submit(
+	auth *bind.TransactOpts,
+	destContract *contracts.DestinationMessenger,
+	msg *customTypes.CrossChainMessage,
+	signatures [][]byte,
+	proofData []byte,
+) (*types.Transaction, error) {
+	targeted := msg.Target != (common.Address{})
+	if targeted {
+		auth.GasLimit = e.gasLimit.Load() + msg.ExecGasLimit
+	}
+
+	switch {
+	case targeted && e.optimistic:
+		return destContract.FinalizeAndExecute(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp)
+	case e.optimistic:
+		return destContract.FinalizeMessage(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp)
+	case targeted && proofData != nil:
+		return destContract.ReceiveAndExecuteProven(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp, proofData)
+	case proofData != nil:
+		return destContract.ReceiveProven(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp, proofData)
+	case targeted && signatures != nil:
+		return destContract.ReceiveAndExecuteAttested(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp, signatures)
+	case signatures != nil:
+		return destContract.ReceiveAttested(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp, signatures)
+	case targeted:
+		return destContract.ReceiveAndExecute(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp)
+	default:
+		return destContract.ReceiveMessage(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp)
+	}
+}
+
+// propose makes sure the message is proposed on the destination and reports
+// whether it can be finalized. It returns ErrChallengeWindowOpen while the
+// window is open and marks challenged messages.
+func (e *Executor) propose(
+	ctx context.Context,
+	client *ethclient.Client,
+	destContract *contracts.DestinationMessenger,
+	msg *customTypes.CrossChainMessage,
+) (bool, error) {
+	executableAt, err := destContract.ExecutableAt(nil, msg.MessageHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to check proposal: %w", err)
+	}
+
+	if executableAt.Sign() == 0 {
+		auth, err := e.signer.GetTransactor(msg.DestChainID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get transactor: %w", err)
+		}
+		auth.GasLimit = e.gasLimit.Load()
+
+		log.Printf(" Proposing message to chain %s...", msg.DestChainID)
+		tx, err := destContract.ProposeMessage(auth, msg.Nonce, msg.SourceChainID, msg.Sender, msg.Payload, msg.Timestamp)
+		if err != nil {
+			return false, fmt.Errorf("failed to send proposal: %w", err)
+		}
+
+		receipt, err := waitForConfirmation(ctx, client, tx.Hash())
+		if err != nil {
+			return false, fmt.Errorf("proposal failed: %w", err)
+		}
+		if receipt.Status != 1 {
+			return false, fmt.Errorf("proposal reverted")
+		}
+
+		executableAt, err = destContract.ExecutableAt(nil, msg.MessageHash)
+		if err != nil {
+			return false, fmt.Errorf("failed to check proposal: %w", err)
+		}
+		log.Printf(" Message proposed! Tx: %s", tx.Hash().Hex())
+	}
+
+	msg.Status = customTypes.StatusProposed
+	readyAt := time.Unix(executableAt.Int64(), 0)
+	msg.ExecutableAt = &readyAt
+
+	challenged, err := destContract.Challenged(nil, msg.MessageHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to check challenge: %w", err)
+	}
+	if challenged {
+		log.Printf(" Message %s was challenged on chain %s", msg.MessageHash.Hex(), msg.DestChainID)
+		msg.Status = customTypes.StatusChallenged
+		return false, nil
+	}
+
+	if time.Now().Before(readyAt) {
+		return false, ErrChallengeWindowOpen
+	}
+	return true, nil
+}
+
+// checkExecution reports a failed receiver call from the MessageExecuted
+// event. The message itself is delivered either way.
+func (e *Executor) checkExecution(destContract *contracts.DestinationMessenger, receipt *types.Receipt, msg *customTypes.CrossChainMessage) {
+	if msg.Target == (common.Address{}) {
+		return
+	}
+
+	for _, vLog := range receipt.Logs {
+		event, err := destContract.ParseMessageExecuted(*vLog)
+		if err != nil || event.MessageHash != msg.MessageHash {
+			continue
+		}
+		if event.Success {
+			log.Printf(" Message executed on target %s", event.Target.Hex())
+			return
+		}
+
+		msg.Status = customTypes.StatusExecutionFailed
+		msg.ExecError = revertReason(event.ReturnData)
+		metrics.ExecutionFailures.WithLabelValues(msg.DestChainID.String()).Inc()
+		log.Printf(" Execution failed on target %s: %s", event.Target.Hex(), msg.ExecError)
+		return
+	}
+}
+
+func revertReason(data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) == 0 {
+		return "reverted without reason"
+	}
+	return hexutil.Encode(data)
+}
+
 func waitForConfirmation(ctx context.Context, client *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
 	for {
 		receipt, err := client.TransactionReceipt(ctx, txHash)
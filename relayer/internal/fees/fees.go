@@ -0,0 +1,125 @@
+package fees
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"relayer/internal/config"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	customTypes "relayer/internal/types"
+)
+
+// Quote compares what a message paid on its source chain with what relaying
+// it costs on the destination, both in source-chain wei.
+type Quote struct {
+	Paid     *big.Int
+	Required *big.Int
+}
+
+func (q *Quote) Sufficient() bool {
+	return q.Paid.Cmp(q.Required) >= 0
+}
+
+// Checker estimates delivery cost as destination gas price × gas limit and
+// converts it to the source chain's native token using the configured
+// price table. All native tokens are assumed to have 18 decimals.
+type Checker struct {
+	mu      sync.RWMutex
+	enabled bool
+	margin  *big.Float
+	prices  map[int64]*big.Float
+}
+
+// NewChecker builds the checker for cfg. With fees enabled every chain in
+// chains needs a native price, since messages from or to a chain without one
+// could never be checked.
+func NewChecker(cfg *config.FeeConfig, chains []config.ChainConfig) (*Checker, error) {
+	c := &Checker{
+		enabled: cfg.Enabled,
+		margin:  big.NewFloat(1),
+		prices:  make(map[int64]*big.Float, len(cfg.NativePrices)),
+	}
+	if !cfg.Enabled {
+		return c, nil
+	}
+
+	if cfg.Margin != 0 {
+		if cfg.Margin < 0 {
+			return nil, fmt.Errorf("fee margin must be positive")
+		}
+		c.margin = big.NewFloat(cfg.Margin)
+	}
+	for chainID, price := range cfg.NativePrices {
+		if price <= 0 {
+			return nil, fmt.Errorf("native price for chain %d must be positive", chainID)
+		}
+		c.prices[chainID] = big.NewFloat(price)
+	}
+	for _, chain := range chains {
+		if _, ok := c.prices[chain.ChainID]; !ok {
+			return nil, fmt.Errorf("no native price for chain %s (%d)", chain.Name, chain.ChainID)
+		}
+	}
+
+	return c, nil
+}
+
+// Reload replaces the fee settings. On error the current ones are kept.
+func (c *Checker) Reload(cfg *config.FeeConfig, chains []config.ChainConfig) error {
+	next, err := NewChecker(cfg, chains)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = next.enabled
+	c.margin = next.margin
+	c.prices = next.prices
+	return nil
+}
+
+func (c *Checker) Enabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.enabled
+}
+
+// Check quotes the cost of relaying msg through client with gasLimit.
+func (c *Checker) Check(ctx context.Context, client *ethclient.Client, msg *customTypes.CrossChainMessage, gasLimit uint64) (*Quote, error) {
+	c.mu.RLock()
+	margin := c.margin
+	srcPrice, srcOK := c.prices[msg.SourceChainID.Int64()]
+	destPrice, destOK := c.prices[msg.DestChainID.Int64()]
+	c.mu.RUnlock()
+
+	if !srcOK {
+		return nil, fmt.Errorf("no native price for source chain %s", msg.SourceChainID)
+	}
+	if !destOK {
+		return nil, fmt.Errorf("no native price for destination chain %s", msg.DestChainID)
+	}
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	// cost in destination wei, converted to source wei with the margin applied
+	cost := new(big.Float).SetInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit)))
+	cost.Mul(cost, destPrice)
+	cost.Quo(cost, srcPrice)
+	cost.Mul(cost, margin)
+
+	required, _ := cost.Int(nil)
+
+	paid := msg.FeePaid
+	if paid == nil {
+		paid = new(big.Int)
+	}
+
+	return &Quote{Paid: paid, Required: required}, nil
+}
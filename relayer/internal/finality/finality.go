@@ -0,0 +1,184 @@
+package finality
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"relayer/internal/config"
+	"relayer/internal/registry"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	customTypes "relayer/internal/types"
+)
+
+// Finality modes
+const (
+	ModeBlocks    = "blocks"
+	ModeSafe      = "safe"
+	ModeFinalized = "finalized"
+)
+
+// Policy says when a source block counts as final: Confirmations blocks
+// behind the head, or at or below the chain's safe or finalized block.
+type Policy struct {
+	Mode          string
+	Confirmations uint64
+}
+
+func NewPolicy(mode string, confirmations uint64) (Policy, error) {
+	switch mode {
+	case "":
+		mode = ModeBlocks
+	case ModeBlocks, ModeSafe, ModeFinalized:
+	default:
+		return Policy{}, fmt.Errorf("unknown finality %q (want blocks, safe or finalized)", mode)
+	}
+	return Policy{Mode: mode, Confirmations: confirmations}, nil
+}
+
+// ChainPolicy is the policy the listener of a chain scans with.
+func ChainPolicy(chain *config.ChainConfig) (Policy, error) {
+	return NewPolicy(chain.Finality, chain.Confirmations)
+}
+
+func (p Policy) String() string {
+	if p.Mode == ModeBlocks {
+		return fmt.Sprintf("%d blocks", p.Confirmations)
+	}
+	return p.Mode
+}
+
+// ConfirmedBlock returns the highest final block given the latest head, and
+// false if no block is final yet.
+func ConfirmedBlock(ctx context.Context, client *ethclient.Client, p Policy, head uint64) (uint64, bool, error) {
+	var tag rpc.BlockNumber
+	switch p.Mode {
+	case ModeSafe:
+		tag = rpc.SafeBlockNumber
+	case ModeFinalized:
+		tag = rpc.FinalizedBlockNumber
+	default:
+		if head < p.Confirmations {
+			return 0, false, nil
+		}
+		return head - p.Confirmations, true, nil
+	}
+
+	header, err := client.HeaderByNumber(ctx, big.NewInt(int64(tag)))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get %s block: %w", p.Mode, err)
+	}
+	return header.Number.Uint64(), true, nil
+}
+
+type rule struct {
+	sourceChainID int64
+	destChainID   int64
+	minValue      *big.Int
+	policy        Policy
+}
+
+// Checker holds back messages that need stronger finality than their source
+// chain's listener waits for, e.g. high-value messages on a route.
+type Checker struct {
+	registry *registry.Registry
+
+	mu    sync.RWMutex
+	rules []rule
+}
+
+func NewChecker(rules []config.FinalityRule, registry *registry.Registry) (*Checker, error) {
+	c := &Checker{registry: registry}
+	if err := c.Reload(rules); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload replaces the rules. On error the current rules are kept.
+func (c *Checker) Reload(rules []config.FinalityRule) error {
+	var parsed []rule
+	for _, r := range rules {
+		policy, err := NewPolicy(r.Finality, r.Confirmations)
+		if err != nil {
+			return err
+		}
+
+		minValue := new(big.Int)
+		if r.MinValue != "" {
+			if _, ok := minValue.SetString(r.MinValue, 10); !ok {
+				return fmt.Errorf("invalid finality rule min_value %q", r.MinValue)
+			}
+		}
+
+		parsed = append(parsed, rule{
+			sourceChainID: r.SourceChainID,
+			destChainID:   r.DestChainID,
+			minValue:      minValue,
+			policy:        policy,
+		})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = parsed
+	return nil
+}
+
+// Required returns the policy of the matching rule with the highest
+// min_value, and false if no rule matches.
+func (c *Checker) Required(msg *customTypes.CrossChainMessage) (Policy, bool) {
+	value := msg.FeePaid
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var best *rule
+	for i := range c.rules {
+		r := &c.rules[i]
+		if r.sourceChainID != msg.SourceChainID.Int64() || r.destChainID != msg.DestChainID.Int64() {
+			continue
+		}
+		if value.Cmp(r.minValue) < 0 {
+			continue
+		}
+		if best == nil || r.minValue.Cmp(best.minValue) > 0 {
+			best = r
+		}
+	}
+	if best == nil {
+		return Policy{}, false
+	}
+	return best.policy, true
+}
+
+// Final reports whether the message's source block is final under the
+// policy its route and value require.
+func (c *Checker) Final(ctx context.Context, msg *customTypes.CrossChainMessage) (bool, error) {
+	policy, ok := c.Required(msg)
+	if !ok {
+		return true, nil
+	}
+
+	client, ok := c.registry.Client(msg.SourceChainID.Int64())
+	if !ok {
+		return false, fmt.Errorf("no client for chain %s", msg.SourceChainID)
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	confirmed, ok, err := ConfirmedBlock(ctx, client, policy, head)
+	if err != nil || !ok {
+		return false, err
+	}
+	return msg.SourceBlock <= confirmed, nil
+}
@@ -2,12 +2,18 @@ package listener
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"relayer/internal/config"
+	"relayer/internal/finality"
+	"relayer/internal/metrics"
+	"relayer/internal/store"
 	customTypes "relayer/internal/types"
 	"relayer/pkg/contracts"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -15,19 +21,56 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
+var (
+	messageSentTopic = crypto.Keccak256Hash([]byte("MessageSent(uint256,uint256,address,bytes,uint256)"))
+	feePaidTopic     = crypto.Keccak256Hash([]byte("FeePaid(uint256,bytes32,uint256)"))
+	targetedTopic    = crypto.Keccak256Hash([]byte("MessageTargeted(uint256,address,uint256)"))
+)
+
+// sendKey ties FeePaid and MessageTargeted logs to the MessageSent log of
+// the same send.
+type sendKey struct {
+	txHash common.Hash
+	nonce  string
+}
+
+type sendExtras struct {
+	fee      *big.Int
+	targeted *contracts.SourceMessengerMessageTargeted
+}
+
 type Listener struct {
 	client         *ethclient.Client
 	chainConfig    *config.ChainConfig
 	sourceContract *contracts.SourceMessenger
 	messageChan    chan *customTypes.CrossChainMessage
+	store          *store.Store
+	finality       finality.Policy
+
+	mu        sync.Mutex
+	fromBlock uint64
+	// Hashes of the last reorgWindow scanned blocks, to detect reorgs
+	hashes      map[uint64]common.Hash
+	reorgWindow uint64
+	// How often heads are polled when the endpoint has no subscriptions
+	pollInterval time.Duration
+	// New messages found by a scan, sent to the executor once mu is released
+	found []*customTypes.CrossChainMessage
 }
 
+// defaultReorgWindow is how many recent block hashes are kept when the
+// chain config does not set reorg_window.
+const defaultReorgWindow = 64
+
 func NewListener(
 	client *ethclient.Client,
 	chainConfig *config.ChainConfig,
+	store *store.Store,
 	messageChan chan *customTypes.CrossChainMessage,
+	pollInterval time.Duration,
 ) (*Listener, error) {
 	sourceContract, err := contracts.NewSourceMessenger(
 		chainConfig.GetSourceContract(),
@@ -37,82 +80,370 @@ func NewListener(
 		return nil, fmt.Errorf("failed to instantiate contract: %w", err)
 	}
 
+	// Resume from the last persisted checkpoint if there is one
+	fromBlock := chainConfig.StartBlock
+	if checkpoint, ok := store.GetCheckpoint(chainConfig.ChainID); ok {
+		fromBlock = checkpoint
+	}
+
+	policy, err := finality.ChainPolicy(chainConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid finality for %s: %w", chainConfig.Name, err)
+	}
+
+	reorgWindow := chainConfig.ReorgWindow
+	if reorgWindow == 0 {
+		reorgWindow = defaultReorgWindow
+	}
+
 	return &Listener{
 		client:         client,
 		chainConfig:    chainConfig,
 		sourceContract: sourceContract,
 		messageChan:    messageChan,
+		store:          store,
+		finality:       policy,
+		fromBlock:      fromBlock,
+		hashes:         make(map[uint64]common.Hash),
+		reorgWindow:    reorgWindow,
+		pollInterval:   pollInterval,
 	}, nil
 }
 
+// ChainID returns the chain this listener scans.
+func (l *Listener) ChainID() int64 {
+	return l.chainConfig.ChainID
+}
+
+// Checkpoint returns the next block the listener will scan.
+func (l *Listener) Checkpoint() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fromBlock
+}
+
+// Rewind forces the listener to re-scan from the given block on the next head.
+// Messages already in the store are not enqueued again; use the executor's
+// Requeue for those.
+func (l *Listener) Rewind(block uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fromBlock = block
+	for n := range l.hashes {
+		if n >= block {
+			delete(l.hashes, n)
+		}
+	}
+	if err := l.store.SetCheckpoint(l.chainConfig.ChainID, block); err != nil {
+		return err
+	}
+
+	log.Printf(" Checkpoint for %s rewound to block %d", l.chainConfig.Name, block)
+	return nil
+}
+
 func (l *Listener) Start(ctx context.Context) error {
-	log.Printf("Starting listener for %s (Chain ID: %d)", l.chainConfig.Name, l.chainConfig.ChainID)
+	log.Printf("Starting listener for %s (Chain ID: %d, finality: %s)", l.chainConfig.Name, l.chainConfig.ChainID, l.finality)
 
 	// Subscribe to new blocks
 	headers := make(chan *types.Header)
-	sub, err := l.client.SubscribeNewHead(ctx, headers)
+	subErr, err := WatchHeads(ctx, l.client, l.chainConfig.Name, l.pollInterval, headers)
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to new heads: %w", err)
+		return err
 	}
-	defer sub.Unsubscribe()
-
-	fromBlock := l.chainConfig.StartBlock
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case err := <-sub.Err():
+		case err := <-subErr:
 			return fmt.Errorf("subscription error: %w", err)
 		case header := <-headers:
-			// Wait for confirmations
-			confirmedBlock := header.Number.Uint64()
-			if confirmedBlock < l.chainConfig.Confirmations {
+			// Only scan up to the chain's final block
+			confirmedBlock, ok, err := finality.ConfirmedBlock(ctx, l.client, l.finality, header.Number.Uint64())
+			if err != nil {
+				log.Printf("Error getting confirmed block: %v", err)
 				continue
 			}
-			confirmedBlock -= l.chainConfig.Confirmations
-
-			if fromBlock > confirmedBlock {
+			if !ok {
 				continue
 			}
 
-			// Query logs
-			if err := l.processBlocks(ctx, fromBlock, confirmedBlock); err != nil {
+			if err := l.advance(ctx, confirmedBlock); err != nil {
 				log.Printf("Error processing blocks: %v", err)
-				continue
 			}
+		}
+	}
+}
+
+// WatchHeads sends new heads to headers until ctx is cancelled. It
+// subscribes where the endpoint supports it and otherwise polls every
+// interval. The returned channel reports subscription failures and
+// is nil when polling.
+func WatchHeads(ctx context.Context, client *ethclient.Client, name string, interval time.Duration, headers chan<- *types.Header) (<-chan error, error) {
+	sub, err := client.SubscribeNewHead(ctx, headers)
+	switch {
+	case errors.Is(err, rpc.ErrNotificationsUnsupported):
+		log.Printf(" No subscriptions on %s endpoint, polling heads every %s", name, interval)
+		go pollHeads(ctx, client, name, interval, headers)
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		sub.Unsubscribe()
+	}()
+	return sub.Err(), nil
+}
+
+// pollHeads sends the latest header to headers whenever the head moves.
+func pollHeads(ctx context.Context, client *ethclient.Client, name string, interval time.Duration, headers chan<- *types.Header) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Error polling head for %s: %v", name, err)
+		} else if err == nil && header.Number.Uint64() > last {
+			last = header.Number.Uint64()
+			select {
+			case headers <- header:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
 
-			fromBlock = confirmedBlock + 1
+// advance scans from the checkpoint up to confirmedBlock, moves the
+// checkpoint forward and sends new messages to the executor. The scan holds
+// the lock so a concurrent Rewind is not lost; sending does not, so a full
+// executor queue never blocks Rewind or Checkpoint.
+func (l *Listener) advance(ctx context.Context, confirmedBlock uint64) error {
+	l.mu.Lock()
+	err := l.scan(ctx, confirmedBlock)
+	found := l.found
+	l.found = nil
+	l.mu.Unlock()
+
+	// Messages are already stored as pending, so any not sent before ctx is
+	// cancelled are requeued by the executor on the next start
+	for _, msg := range found {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l.messageChan <- msg:
 		}
 	}
+	return err
+}
+
+// scan is advance for callers holding l.mu.
+func (l *Listener) scan(ctx context.Context, confirmedBlock uint64) error {
+	if l.fromBlock > confirmedBlock {
+		return nil
+	}
+
+	if err := l.checkReorg(ctx); err != nil {
+		return err
+	}
+
+	// Query logs
+	if _, err := l.processBlocks(ctx, l.fromBlock, confirmedBlock); err != nil {
+		return err
+	}
+	if err := l.recordHashes(ctx, l.fromBlock, confirmedBlock); err != nil {
+		return err
+	}
+
+	l.fromBlock = confirmedBlock + 1
+	return l.store.SetCheckpoint(l.chainConfig.ChainID, l.fromBlock)
 }
 
-func (l *Listener) processBlocks(ctx context.Context, from, to uint64) error {
+// checkReorg compares the parent hash of the next block with the hash
+// recorded for the last scanned one. On a mismatch it walks back to the
+// common ancestor, re-scans the replaced blocks and marks detections that
+// did not survive as orphaned.
+func (l *Listener) checkReorg(ctx context.Context) error {
+	if l.fromBlock == 0 {
+		return nil
+	}
+	last := l.fromBlock - 1
+	recorded, ok := l.hashes[last]
+	if !ok {
+		return nil
+	}
+
+	next, err := l.client.HeaderByNumber(ctx, new(big.Int).SetUint64(l.fromBlock))
+	if err != nil {
+		return fmt.Errorf("failed to get header %d: %w", l.fromBlock, err)
+	}
+	if next.ParentHash == recorded {
+		return nil
+	}
+
+	// Find the newest recorded block still on the canonical chain
+	ancestor := last
+	for {
+		hash, ok := l.hashes[ancestor]
+		if !ok {
+			log.Printf(" Reorg on %s is deeper than the %d block window", l.chainConfig.Name, l.reorgWindow)
+			break
+		}
+		header, err := l.client.HeaderByNumber(ctx, new(big.Int).SetUint64(ancestor))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", ancestor, err)
+		}
+		if header.Hash() == hash || ancestor == 0 {
+			break
+		}
+		ancestor--
+	}
+
+	from := ancestor + 1
+	log.Printf(" Reorg detected on %s: re-scanning blocks %d-%d", l.chainConfig.Name, from, last)
+	metrics.Reorgs.WithLabelValues(l.chainConfig.GetChainID().String()).Inc()
+
+	for n := range l.hashes {
+		if n >= from {
+			delete(l.hashes, n)
+		}
+	}
+
+	detected, err := l.processBlocks(ctx, from, last)
+	if err != nil {
+		return err
+	}
+	if err := l.recordHashes(ctx, from, last); err != nil {
+		return err
+	}
+
+	for _, msg := range l.store.ListFromBlock(l.chainConfig.ChainID, from) {
+		if msg.SourceBlock > last || detected[msg.MessageHash] || msg.Status == customTypes.StatusOrphaned {
+			continue
+		}
+		l.orphan(msg)
+	}
+	return nil
+}
+
+// orphan marks a message whose MessageSent log was dropped by a reorg so
+// the executor never delivers it.
+func (l *Listener) orphan(msg *customTypes.CrossChainMessage) {
+	if msg.Status == customTypes.StatusCompleted {
+		log.Printf(" Delivered message %s was dropped from %s by a reorg", msg.MessageHash.Hex(), l.chainConfig.Name)
+	} else {
+		log.Printf(" Message %s was dropped from %s by a reorg, orphaning it", msg.MessageHash.Hex(), l.chainConfig.Name)
+	}
+
+	msg.Status = customTypes.StatusOrphaned
+	if err := l.store.SaveMessage(msg); err != nil {
+		log.Printf("Error saving orphaned message: %v", err)
+	}
+	metrics.OrphanedMessages.WithLabelValues(l.chainConfig.GetChainID().String()).Inc()
+}
+
+// recordHashes remembers the hashes of the scanned blocks that fall in the
+// reorg window ending at to.
+func (l *Listener) recordHashes(ctx context.Context, from, to uint64) error {
+	start := from
+	if to >= l.reorgWindow && to-l.reorgWindow+1 > start {
+		start = to - l.reorgWindow + 1
+	}
+
+	for n := start; n <= to; n++ {
+		header, err := l.client.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", n, err)
+		}
+		l.hashes[n] = header.Hash()
+	}
+
+	for n := range l.hashes {
+		if n+l.reorgWindow <= to {
+			delete(l.hashes, n)
+		}
+	}
+	return nil
+}
+
+// processBlocks scans [from, to] and returns the hashes of the messages
+// found there.
+func (l *Listener) processBlocks(ctx context.Context, from, to uint64) (map[common.Hash]bool, error) {
 	query := ethereum.FilterQuery{
 		FromBlock: big.NewInt(int64(from)),
 		ToBlock:   big.NewInt(int64(to)),
 		Addresses: []common.Address{l.chainConfig.GetSourceContract()},
+		Topics:    [][]common.Hash{{messageSentTopic, feePaidTopic, targetedTopic}},
 	}
 
 	logs, err := l.client.FilterLogs(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to filter logs: %w", err)
+		return nil, fmt.Errorf("failed to filter logs: %w", err)
 	}
 
+	// Collect fees and targets first so each message can be matched with them
+	extras := make(map[sendKey]*sendExtras)
+	extrasFor := func(key sendKey) *sendExtras {
+		if extras[key] == nil {
+			extras[key] = &sendExtras{}
+		}
+		return extras[key]
+	}
 	for _, vLog := range logs {
-		if err := l.handleLog(vLog); err != nil {
+		if vLog.Removed {
+			continue
+		}
+		switch vLog.Topics[0] {
+		case feePaidTopic:
+			event, err := l.sourceContract.ParseFeePaid(vLog)
+			if err != nil {
+				log.Printf("Error parsing fee: %v", err)
+				continue
+			}
+			extrasFor(sendKey{vLog.TxHash, event.Nonce.String()}).fee = event.Amount
+		case targetedTopic:
+			event, err := l.sourceContract.ParseMessageTargeted(vLog)
+			if err != nil {
+				log.Printf("Error parsing target: %v", err)
+				continue
+			}
+			extrasFor(sendKey{vLog.TxHash, event.Nonce.String()}).targeted = event
+		}
+	}
+
+	detected := make(map[common.Hash]bool)
+	for _, vLog := range logs {
+		if vLog.Removed || vLog.Topics[0] != messageSentTopic {
+			continue
+		}
+		hash, err := l.handleLog(vLog, extras)
+		if err != nil {
 			log.Printf("Error handling log: %v", err)
+			continue
 		}
+		detected[hash] = true
 	}
 
-	return nil
+	return detected, nil
 }
 
-func (l *Listener) handleLog(vLog types.Log) error {
+func (l *Listener) handleLog(vLog types.Log, extras map[sendKey]*sendExtras) (common.Hash, error) {
 	// Parse MessageSent event
 	event, err := l.sourceContract.ParseMessageSent(vLog)
 	if err != nil {
-		return fmt.Errorf("failed to parse event: %w", err)
+		return common.Hash{}, fmt.Errorf("failed to parse event: %w", err)
 	}
 
 	message := &customTypes.CrossChainMessage{
@@ -122,14 +453,16 @@ func (l *Listener) handleLog(vLog types.Log) error {
 		Sender:        event.Sender,
 		Payload:       event.Payload,
 		Timestamp:     event.Timestamp,
+		FeePaid:       new(big.Int),
 		SourceTxHash:  vLog.TxHash,
+		SourceBlock:   vLog.BlockNumber,
 		Status:        customTypes.StatusPending,
 		CreatedAt:     time.Now(),
 		RetryCount:    0,
 	}
 
 	// Compute message hash
-	messageHash := computeMessageHash(
+	messageHash := ComputeMessageHash(
 		event.Nonce,
 		l.chainConfig.GetChainID(),
 		event.DestinationChainId,
@@ -139,27 +472,60 @@ func (l *Listener) handleLog(vLog types.Log) error {
 	)
 	message.MessageHash = messageHash
 
+	// Seen before (re-scan after a reorg or rewind): just follow it to its
+	// new block. Orphaned messages that reappear are detected again.
+	if existing, err := l.store.GetMessage(messageHash); err == nil && existing.Status != customTypes.StatusOrphaned {
+		if existing.SourceBlock != vLog.BlockNumber {
+			existing.SourceBlock = vLog.BlockNumber
+			existing.SourceTxHash = vLog.TxHash
+			if err := l.store.SaveMessage(existing); err != nil {
+				return common.Hash{}, fmt.Errorf("failed to store message: %w", err)
+			}
+		}
+		return messageHash, nil
+	}
+
+	if extra, ok := extras[sendKey{vLog.TxHash, event.Nonce.String()}]; ok {
+		if extra.fee != nil {
+			message.FeePaid = extra.fee
+		}
+		if extra.targeted != nil {
+			message.Target = extra.targeted.Target
+			message.ExecGasLimit = extra.targeted.GasLimit.Uint64()
+			if !extra.targeted.GasLimit.IsUint64() {
+				// Over any max_exec_gas, so the executor rejects it
+				message.ExecGasLimit = math.MaxUint64
+			}
+		}
+	}
+
 	log.Printf(" New message detected: Nonce=%s, From=%s, To Chain=%s",
 		event.Nonce.String(), event.Sender.Hex(), event.DestinationChainId.String())
 
-	// Send to executor
-	l.messageChan <- message
+	if err := l.store.SaveMessage(message); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to store message: %w", err)
+	}
 
-	return nil
+	// Sent to the executor by advance, once l.mu is released
+	l.found = append(l.found, message)
+
+	return messageHash, nil
 }
 
-func computeMessageHash(
+// ComputeMessageHash mirrors the contracts' keccak256(abi.encodePacked(...)),
+// where every uint256 is packed as a full 32-byte word.
+func ComputeMessageHash(
 	nonce, sourceChainID, destChainID *big.Int,
 	sender common.Address,
 	payload []byte,
 	timestamp *big.Int,
 ) common.Hash {
 	return crypto.Keccak256Hash(
-		nonce.Bytes(),
-		sourceChainID.Bytes(),
-		destChainID.Bytes(),
+		common.BigToHash(nonce).Bytes(),
+		common.BigToHash(sourceChainID).Bytes(),
+		common.BigToHash(destChainID).Bytes(),
 		sender.Bytes(),
 		payload,
-		timestamp.Bytes(),
+		common.BigToHash(timestamp).Bytes(),
 	)
 }
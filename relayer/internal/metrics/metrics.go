@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	PolicyDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_policy_decisions_total",
+		Help: "Policy decisions by action and reason.",
+	}, []string{"action", "reason"})
+
+	RateLimitDeferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_rate_limit_deferred_total",
+		Help: "Messages deferred by a rate limit, by the bucket kind that was empty.",
+	}, []string{"kind"})
+
+	UnderfundedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_underfunded_messages_total",
+		Help: "Messages held because the fee paid was below the estimated delivery cost.",
+	}, []string{"source_chain_id", "dest_chain_id"})
+
+	ExecutionFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_execution_failures_total",
+		Help: "Delivered messages whose target onMessage call reverted.",
+	}, []string{"dest_chain_id"})
+
+	RateLimitTokens = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "relayer_rate_limit_tokens",
+		Help: "Remaining tokens per route rate limit bucket.",
+	}, []string{"route"})
+
+	Reorgs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_reorgs_total",
+		Help: "Source chain reorgs detected by the listener.",
+	}, []string{"chain_id"})
+
+	OrphanedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_orphaned_messages_total",
+		Help: "Detected messages dropped from the source chain by a reorg.",
+	}, []string{"chain_id"})
+
+	RPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_rpc_requests_total",
+		Help: "JSON-RPC requests per endpoint, by method and outcome (ok, error).",
+	}, []string{"chain_id", "endpoint", "method", "status"})
+
+	RPCLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "relayer_rpc_request_duration_seconds",
+		Help:    "JSON-RPC request latency per endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"chain_id", "endpoint"})
+
+	RPCEndpointUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "relayer_rpc_endpoint_up",
+		Help: "Whether an RPC endpoint passed its last health check (1) or not (0).",
+	}, []string{"chain_id", "endpoint"})
+
+	RPCRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_rpc_rate_limited_total",
+		Help: "Responses where an RPC endpoint signalled a rate limit and was backed off.",
+	}, []string{"chain_id", "endpoint"})
+
+	RPCCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_rpc_cache_hits_total",
+		Help: "JSON-RPC requests answered from the cache of immutable results.",
+	}, []string{"chain_id", "method"})
+
+	RPCQuorumFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "relayer_rpc_quorum_failures_total",
+		Help: "Requests where too few RPC endpoints agreed on the result.",
+	}, []string{"chain_id", "method"})
+
+	WatcherAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "watcher_alerts_total",
+		Help: "Deliveries flagged by watcherd, by kind (forged, mismatched, undelivered, challenge_failed).",
+	}, []string{"kind", "source_chain_id", "dest_chain_id"})
+
+	WatcherOutstanding = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "watcher_outstanding_messages",
+		Help: "Messages seen on a source chain and not yet delivered.",
+	})
+)
+
+// Serve exposes /metrics on addr until ctx is cancelled.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf(" Metrics listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
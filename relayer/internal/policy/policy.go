@@ -0,0 +1,243 @@
+package policy
+
+import (
+	"fmt"
+	"log"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	customTypes "relayer/internal/types"
+)
+
+// Action is what the executor should do with a message.
+type Action string
+
+const (
+	// Allow relays the message.
+	Allow Action = "allow"
+	// Hold keeps the message pending until the pause is lifted.
+	Hold Action = "hold"
+	// Reject never relays the message.
+	Reject Action = "reject"
+)
+
+type Decision struct {
+	Action Action
+	Reason string
+}
+
+// Route is a source→destination chain pair.
+type Route struct {
+	SourceChainID int64
+	DestChainID   int64
+}
+
+type routeRules struct {
+	paused         bool
+	allow          map[common.Address]bool
+	deny           map[common.Address]bool
+	maxPayloadSize int
+}
+
+// Engine evaluates every message before the executor relays it. Rules come
+// from the `policy` section of config.yaml and can be swapped with Reload;
+// pauses set at runtime through the admin API are kept across reloads.
+type Engine struct {
+	mu             sync.RWMutex
+	paused         bool
+	maxPayloadSize int
+	routes         map[Route]*routeRules
+
+	pausedAll    bool
+	pausedChains map[int64]bool
+	pausedRoutes map[Route]bool
+}
+
+func NewEngine(cfg *config.PolicyConfig) (*Engine, error) {
+	e := &Engine{
+		pausedChains: make(map[int64]bool),
+		pausedRoutes: make(map[Route]bool),
+	}
+	if err := e.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload replaces the configured rules.
+func (e *Engine) Reload(cfg *config.PolicyConfig) error {
+	routes := make(map[Route]*routeRules, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		route := Route{SourceChainID: r.SourceChainID, DestChainID: r.DestChainID}
+		if _, dup := routes[route]; dup {
+			return fmt.Errorf("duplicate policy for route %d->%d", r.SourceChainID, r.DestChainID)
+		}
+
+		rules := &routeRules{paused: r.Paused, maxPayloadSize: r.MaxPayloadSize}
+		var err error
+		if rules.allow, err = addressSet(r.Allowlist); err != nil {
+			return fmt.Errorf("route %d->%d allowlist: %w", r.SourceChainID, r.DestChainID, err)
+		}
+		if rules.deny, err = addressSet(r.Denylist); err != nil {
+			return fmt.Errorf("route %d->%d denylist: %w", r.SourceChainID, r.DestChainID, err)
+		}
+		routes[route] = rules
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.paused = cfg.Paused
+	e.maxPayloadSize = cfg.MaxPayloadSize
+	e.routes = routes
+
+	log.Printf(" Policy loaded: %d route(s), global pause %v", len(routes), cfg.Paused)
+	return nil
+}
+
+// ReloadFrom re-reads the policy section of a config file.
+func (e *Engine) ReloadFrom(path string) error {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return e.Reload(&cfg.Policy)
+}
+
+// Evaluate decides what to do with a message, then logs and counts the
+// decision.
+func (e *Engine) Evaluate(msg *customTypes.CrossChainMessage) Decision {
+	d := e.evaluate(msg)
+
+	metrics.PolicyDecisions.WithLabelValues(string(d.Action), d.Reason).Inc()
+	if d.Action != Allow {
+		log.Printf(" Policy %s message %s (%s->%s, sender %s): %s",
+			d.Action, msg.MessageHash.Hex(), msg.SourceChainID, msg.DestChainID, msg.Sender.Hex(), d.Reason)
+	}
+	return d
+}
+
+func (e *Engine) evaluate(msg *customTypes.CrossChainMessage) Decision {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	route := Route{
+		SourceChainID: msg.SourceChainID.Int64(),
+		DestChainID:   msg.DestChainID.Int64(),
+	}
+
+	if e.paused || e.pausedAll {
+		return Decision{Hold, "global_pause"}
+	}
+	if e.pausedChains[route.SourceChainID] || e.pausedChains[route.DestChainID] {
+		return Decision{Hold, "chain_paused"}
+	}
+	if e.pausedRoutes[route] {
+		return Decision{Hold, "route_paused"}
+	}
+	if e.maxPayloadSize > 0 && len(msg.Payload) > e.maxPayloadSize {
+		return Decision{Reject, "payload_too_large"}
+	}
+
+	// With no routes configured every route is enabled
+	if len(e.routes) == 0 {
+		return Decision{Allow, "ok"}
+	}
+
+	rules, ok := e.routes[route]
+	if !ok {
+		return Decision{Reject, "route_disabled"}
+	}
+	if rules.paused {
+		return Decision{Hold, "route_paused"}
+	}
+	if rules.maxPayloadSize > 0 && len(msg.Payload) > rules.maxPayloadSize {
+		return Decision{Reject, "payload_too_large"}
+	}
+	if rules.deny[msg.Sender] {
+		return Decision{Reject, "sender_denied"}
+	}
+	if len(rules.allow) > 0 && !rules.allow[msg.Sender] {
+		return Decision{Reject, "sender_not_allowed"}
+	}
+
+	return Decision{Allow, "ok"}
+}
+
+// PauseAll holds every message until ResumeAll.
+func (e *Engine) PauseAll() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pausedAll = true
+	log.Printf(" Global pause enabled")
+}
+
+func (e *Engine) ResumeAll() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pausedAll = false
+	log.Printf(" Global pause lifted")
+}
+
+// PauseChain holds every message from or to the chain.
+func (e *Engine) PauseChain(chainID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pausedChains[chainID] = true
+	log.Printf(" Chain %d paused", chainID)
+}
+
+func (e *Engine) ResumeChain(chainID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.pausedChains, chainID)
+	log.Printf(" Chain %d resumed", chainID)
+}
+
+// PauseRoute holds messages for a single source→destination pair.
+func (e *Engine) PauseRoute(route Route) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pausedRoutes[route] = true
+	log.Printf(" Route %d->%d paused", route.SourceChainID, route.DestChainID)
+}
+
+func (e *Engine) ResumeRoute(route Route) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.pausedRoutes, route)
+	log.Printf(" Route %d->%d resumed", route.SourceChainID, route.DestChainID)
+}
+
+// Paused reports the active pauses, both runtime and configured.
+func (e *Engine) Paused() (all bool, chains []int64, routes []Route) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for chainID := range e.pausedChains {
+		chains = append(chains, chainID)
+	}
+	for route := range e.pausedRoutes {
+		routes = append(routes, route)
+	}
+	for route, rules := range e.routes {
+		if rules.paused && !e.pausedRoutes[route] {
+			routes = append(routes, route)
+		}
+	}
+	return e.paused || e.pausedAll, chains, routes
+}
+
+func addressSet(addrs []string) (map[common.Address]bool, error) {
+	set := make(map[common.Address]bool, len(addrs))
+	for _, addr := range addrs {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid address %q", addr)
+		}
+		set[common.HexToAddress(addr)] = true
+	}
+	return set, nil
+}
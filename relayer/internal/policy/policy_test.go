@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"math/big"
+	"relayer/internal/config"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	customTypes "relayer/internal/types"
+)
+
+var (
+	alice = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob   = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+func message(source, dest int64, sender common.Address, payloadSize int) *customTypes.CrossChainMessage {
+	return &customTypes.CrossChainMessage{
+		SourceChainID: big.NewInt(source),
+		DestChainID:   big.NewInt(dest),
+		Sender:        sender,
+		Payload:       make([]byte, payloadSize),
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	routes := []config.RoutePolicy{
+		{SourceChainID: 1, DestChainID: 2, Allowlist: []string{alice.Hex()}},
+		{SourceChainID: 2, DestChainID: 1, Denylist: []string{bob.Hex()}, MaxPayloadSize: 8},
+		{SourceChainID: 1, DestChainID: 3, Paused: true},
+	}
+
+	tests := []struct {
+		name  string
+		cfg   config.PolicyConfig
+		pause func(*Engine)
+		msg   *customTypes.CrossChainMessage
+		want  Decision
+	}{
+		{"no routes allows all", config.PolicyConfig{}, nil, message(5, 6, bob, 100), Decision{Allow, "ok"}},
+		{"global cap", config.PolicyConfig{MaxPayloadSize: 4}, nil, message(5, 6, bob, 5), Decision{Reject, "payload_too_large"}},
+		{"at global cap", config.PolicyConfig{MaxPayloadSize: 4}, nil, message(5, 6, bob, 4), Decision{Allow, "ok"}},
+		{"configured pause", config.PolicyConfig{Paused: true}, nil, message(1, 2, alice, 0), Decision{Hold, "global_pause"}},
+		{"runtime pause", config.PolicyConfig{}, (*Engine).PauseAll, message(1, 2, alice, 0), Decision{Hold, "global_pause"}},
+		{"source chain paused", config.PolicyConfig{}, func(e *Engine) { e.PauseChain(1) }, message(1, 2, alice, 0), Decision{Hold, "chain_paused"}},
+		{"dest chain paused", config.PolicyConfig{}, func(e *Engine) { e.PauseChain(2) }, message(1, 2, alice, 0), Decision{Hold, "chain_paused"}},
+		{"route paused", config.PolicyConfig{}, func(e *Engine) { e.PauseRoute(Route{1, 2}) }, message(1, 2, alice, 0), Decision{Hold, "route_paused"}},
+		{"other route not paused", config.PolicyConfig{}, func(e *Engine) { e.PauseRoute(Route{2, 1}) }, message(1, 2, alice, 0), Decision{Allow, "ok"}},
+		{"pause holds before cap", config.PolicyConfig{MaxPayloadSize: 4}, (*Engine).PauseAll, message(1, 2, alice, 5), Decision{Hold, "global_pause"}},
+		{"unlisted route", config.PolicyConfig{Routes: routes}, nil, message(3, 1, alice, 0), Decision{Reject, "route_disabled"}},
+		{"route paused in config", config.PolicyConfig{Routes: routes}, nil, message(1, 3, alice, 0), Decision{Hold, "route_paused"}},
+		{"allowlisted sender", config.PolicyConfig{Routes: routes}, nil, message(1, 2, alice, 0), Decision{Allow, "ok"}},
+		{"sender not allowlisted", config.PolicyConfig{Routes: routes}, nil, message(1, 2, bob, 0), Decision{Reject, "sender_not_allowed"}},
+		{"denylisted sender", config.PolicyConfig{Routes: routes}, nil, message(2, 1, bob, 0), Decision{Reject, "sender_denied"}},
+		{"sender not denylisted", config.PolicyConfig{Routes: routes}, nil, message(2, 1, alice, 0), Decision{Allow, "ok"}},
+		{"route cap", config.PolicyConfig{Routes: routes}, nil, message(2, 1, alice, 9), Decision{Reject, "payload_too_large"}},
+		{"global cap before route", config.PolicyConfig{Routes: routes, MaxPayloadSize: 2}, nil, message(2, 1, alice, 3), Decision{Reject, "payload_too_large"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEngine(&tt.cfg)
+			if err != nil {
+				t.Fatalf("NewEngine: %v", err)
+			}
+			if tt.pause != nil {
+				tt.pause(e)
+			}
+			if got := e.evaluate(tt.msg); got != tt.want {
+				t.Errorf("evaluate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	e, err := NewEngine(&config.PolicyConfig{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	msg := message(1, 2, alice, 0)
+
+	e.PauseAll()
+	e.PauseChain(1)
+	e.PauseRoute(Route{1, 2})
+	e.ResumeAll()
+	e.ResumeChain(1)
+	e.ResumeRoute(Route{1, 2})
+	if got := e.evaluate(msg); got.Action != Allow {
+		t.Errorf("evaluate after resume = %v, want allow", got)
+	}
+}
+
+func TestReloadKeepsRuntimePauses(t *testing.T) {
+	e, err := NewEngine(&config.PolicyConfig{})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	e.PauseChain(1)
+
+	if err := e.Reload(&config.PolicyConfig{MaxPayloadSize: 10}); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := e.evaluate(message(1, 2, alice, 0)); got.Reason != "chain_paused" {
+		t.Errorf("evaluate after reload = %v, want chain_paused", got)
+	}
+}
+
+func TestReloadRejectsBadRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []config.RoutePolicy
+	}{
+		{"duplicate route", []config.RoutePolicy{{SourceChainID: 1, DestChainID: 2}, {SourceChainID: 1, DestChainID: 2}}},
+		{"bad allowlist", []config.RoutePolicy{{SourceChainID: 1, DestChainID: 2, Allowlist: []string{"alice"}}}},
+		{"bad denylist", []config.RoutePolicy{{SourceChainID: 1, DestChainID: 2, Denylist: []string{"0x12"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEngine(&config.PolicyConfig{MaxPayloadSize: 4})
+			if err != nil {
+				t.Fatalf("NewEngine: %v", err)
+			}
+			if err := e.Reload(&config.PolicyConfig{Routes: tt.routes}); err == nil {
+				t.Fatalf("Reload accepted %s", tt.name)
+			}
+			// The running rules are kept
+			if got := e.evaluate(message(1, 2, alice, 5)); got.Reason != "payload_too_large" {
+				t.Errorf("evaluate after failed reload = %v, want payload_too_large", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// MessageExistsSlot is the storage slot of SourceMessenger.messageExists.
+const MessageExistsSlot = 1
+
+var ErrNotProven = errors.New("message not found in source storage")
+
+var proofArgs abi.Arguments
+
+func init() {
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	bytesArrayType, _ := abi.NewType("bytes[]", "", nil)
+	proofArgs = abi.Arguments{
+		{Type: uint256Type},
+		{Type: bytesType},
+		{Type: bytesArrayType},
+		{Type: bytesArrayType},
+	}
+}
+
+// Proof shows that SourceMessenger.messageExists[hash] is set in the state
+// of a source chain block.
+type Proof struct {
+	BlockNumber  *big.Int
+	Header       []byte
+	AccountProof [][]byte
+	StorageProof [][]byte
+}
+
+// Encode packs the proof as DestinationMessenger.receiveProven expects:
+// abi.encode(blockNumber, rlpHeader, accountProof, storageProof).
+func (p *Proof) Encode() ([]byte, error) {
+	return proofArgs.Pack(p.BlockNumber, p.Header, p.AccountProof, p.StorageProof)
+}
+
+// Decode unpacks a proof produced by Encode.
+func Decode(data []byte) (*Proof, error) {
+	values, err := proofArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode proof: %w", err)
+	}
+	return &Proof{
+		BlockNumber:  values[0].(*big.Int),
+		Header:       values[1].([]byte),
+		AccountProof: values[2].([][]byte),
+		StorageProof: values[3].([][]byte),
+	}, nil
+}
+
+// SlotKey is the storage key of messageExists[messageHash]:
+// keccak256(abi.encode(messageHash, MessageExistsSlot)).
+func SlotKey(messageHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(messageHash.Bytes(), common.BigToHash(big.NewInt(MessageExistsSlot)).Bytes())
+}
+
+// Build fetches the header of blockNumber and an eth_getProof proof of the
+// message's slot in the source contract, and checks the proof locally
+// before it is submitted.
+func Build(
+	ctx context.Context,
+	client *ethclient.Client,
+	sourceContract common.Address,
+	messageHash common.Hash,
+	blockNumber *big.Int,
+) (*Proof, error) {
+	header, err := client.HeaderByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header %s: %w", blockNumber, err)
+	}
+
+	encodedHeader, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode header: %w", err)
+	}
+
+	slot := SlotKey(messageHash)
+	result, err := gethclient.New(client.Client()).GetProof(ctx, sourceContract, []string{slot.Hex()}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proof: %w", err)
+	}
+	if len(result.StorageProof) != 1 {
+		return nil, fmt.Errorf("expected 1 storage proof, got %d", len(result.StorageProof))
+	}
+
+	p := &Proof{
+		BlockNumber: new(big.Int).Set(header.Number),
+		Header:      encodedHeader,
+	}
+	if p.AccountProof, err = decodeNodes(result.AccountProof); err != nil {
+		return nil, fmt.Errorf("invalid account proof: %w", err)
+	}
+	if p.StorageProof, err = decodeNodes(result.StorageProof[0].Proof); err != nil {
+		return nil, fmt.Errorf("invalid storage proof: %w", err)
+	}
+
+	if err := Verify(header.Root, sourceContract, messageHash, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Verify checks the proof against stateRoot the same way the destination
+// contract does.
+func Verify(stateRoot common.Hash, sourceContract common.Address, messageHash common.Hash, p *Proof) error {
+	var header types.Header
+	if err := rlp.DecodeBytes(p.Header, &header); err != nil {
+		return fmt.Errorf("failed to decode header: %w", err)
+	}
+	if header.Root != stateRoot {
+		return fmt.Errorf("header state root %s does not match %s", header.Root.Hex(), stateRoot.Hex())
+	}
+
+	encodedAccount, err := trie.VerifyProof(stateRoot, crypto.Keccak256(sourceContract.Bytes()), proofDB(p.AccountProof))
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %w", err)
+	}
+	if encodedAccount == nil {
+		return fmt.Errorf("source contract %s not found in state", sourceContract.Hex())
+	}
+
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(encodedAccount, &account); err != nil {
+		return fmt.Errorf("failed to decode account: %w", err)
+	}
+
+	slot := SlotKey(messageHash)
+	encodedValue, err := trie.VerifyProof(account.Root, crypto.Keccak256(slot.Bytes()), proofDB(p.StorageProof))
+	if err != nil {
+		return fmt.Errorf("invalid storage proof: %w", err)
+	}
+	if encodedValue == nil {
+		return ErrNotProven
+	}
+
+	var value []byte
+	if err := rlp.DecodeBytes(encodedValue, &value); err != nil {
+		return fmt.Errorf("failed to decode storage value: %w", err)
+	}
+	if !bytes.Equal(value, []byte{1}) {
+		return ErrNotProven
+	}
+	return nil
+}
+
+func decodeNodes(nodes []string) ([][]byte, error) {
+	out := make([][]byte, len(nodes))
+	for i, node := range nodes {
+		b, err := hexutil.Decode(node)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = b
+	}
+	return out, nil
+}
+
+// proofDB indexes proof nodes by hash for trie.VerifyProof.
+func proofDB(nodes [][]byte) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range nodes {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
@@ -0,0 +1,168 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+
+	"relayer/internal/listener"
+)
+
+var update = flag.Bool("update", false, "rewrite the fixture in contracts/test/StorageProof.t.sol")
+
+// The message contracts/test/StorageProof.t.sol delivers: nonce 7 from
+// 0x123 on chain 11155111 to forge's default chain 31337.
+var (
+	sourceContract = common.HexToAddress("0x5000000000000000000000000000000000000005")
+	sourceChainID  = big.NewInt(11155111)
+	destChainID    = big.NewInt(31337)
+	fixtureHash    = listener.ComputeMessageHash(
+		big.NewInt(7), sourceChainID, destChainID,
+		common.HexToAddress("0x123"), []byte("Hello"), big.NewInt(1000),
+	)
+	fixtureFile = filepath.Join("..", "..", "..", "contracts", "test", "StorageProof.t.sol")
+)
+
+// newChain starts a simulated chain whose source contract has
+// messageExists[hash] set for each hash, and mines block 1 on it.
+func newChain(t *testing.T, hashes ...common.Hash) *ethclient.Client {
+	t.Helper()
+
+	storage := make(map[common.Hash]common.Hash)
+	for _, hash := range hashes {
+		storage[SlotKey(hash)] = common.BigToHash(big.NewInt(1))
+	}
+	alloc := types.GenesisAlloc{
+		sourceContract: {Code: []byte{0x00}, Balance: new(big.Int), Storage: storage},
+	}
+
+	// The simulated client hides its *ethclient.Client, which Build needs
+	// for eth_getProof, so dial the node over IPC instead
+	ipc := filepath.Join(t.TempDir(), "sim.ipc")
+	sim := simulated.NewBackend(alloc, func(nodeConf *node.Config, _ *ethconfig.Config) {
+		nodeConf.IPCPath = ipc
+	})
+	t.Cleanup(func() { sim.Close() })
+	sim.Commit()
+
+	client, err := ethclient.Dial(ipc)
+	if err != nil {
+		t.Fatalf("failed to dial simulated chain: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	other := crypto.Keccak256Hash([]byte("other message"))
+	client := newChain(t, fixtureHash, other)
+
+	p, err := Build(ctx, client, sourceContract, fixtureHash, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	header, err := client.HeaderByNumber(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+	if !bytes.Equal(crypto.Keccak256(p.Header), header.Hash().Bytes()) {
+		t.Fatalf("proof header hashes to %x, want block hash %s", crypto.Keccak256(p.Header), header.Hash().Hex())
+	}
+	if err := Verify(header.Root, sourceContract, fixtureHash, p); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	// The contract decodes what Encode packs
+	encoded, err := p.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if decoded.BlockNumber.Cmp(p.BlockNumber) != 0 || !bytes.Equal(decoded.Header, p.Header) ||
+		len(decoded.AccountProof) != len(p.AccountProof) || len(decoded.StorageProof) != len(p.StorageProof) {
+		t.Fatalf("Decode(Encode(p)) differs from p")
+	}
+
+	if *update {
+		if err := writeFixture(header.Hash(), encoded); err != nil {
+			t.Fatalf("failed to update fixture: %v", err)
+		}
+	}
+}
+
+func TestVerifyRejectsOtherMessage(t *testing.T) {
+	ctx := context.Background()
+	client := newChain(t, fixtureHash)
+
+	p, err := Build(ctx, client, sourceContract, fixtureHash, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	header, err := client.HeaderByNumber(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	unsent := crypto.Keccak256Hash([]byte("never sent"))
+	if err := Verify(header.Root, sourceContract, unsent, p); err == nil {
+		t.Fatalf("Verify accepted a proof for a different message")
+	}
+	if err := Verify(header.Root, common.HexToAddress("0x6"), fixtureHash, p); err == nil {
+		t.Fatalf("Verify accepted a proof for a different contract")
+	}
+	if err := Verify(common.Hash{1}, sourceContract, fixtureHash, p); err == nil {
+		t.Fatalf("Verify accepted a proof against a different state root")
+	}
+}
+
+func TestBuildUnsentMessage(t *testing.T) {
+	client := newChain(t, fixtureHash)
+
+	unsent := crypto.Keccak256Hash([]byte("never sent"))
+	if _, err := Build(context.Background(), client, sourceContract, unsent, big.NewInt(1)); err == nil {
+		t.Fatalf("Build proved a message that was never sent")
+	}
+}
+
+// writeFixture replaces BLOCK_HASH and PROOF in the Solidity test with a
+// proof from this run.
+func writeFixture(blockHash common.Hash, encoded []byte) error {
+	src, err := os.ReadFile(fixtureFile)
+	if err != nil {
+		return err
+	}
+
+	replacements := []struct {
+		pattern *regexp.Regexp
+		value   string
+	}{
+		{regexp.MustCompile(`bytes32 constant BLOCK_HASH = 0x[0-9a-f]*;`), fmt.Sprintf("bytes32 constant BLOCK_HASH = %s;", blockHash.Hex())},
+		{regexp.MustCompile(`bytes constant PROOF = hex"[0-9a-f]*";`), fmt.Sprintf(`bytes constant PROOF = hex"%s";`, hexutil.Encode(encoded)[2:])},
+	}
+	for _, r := range replacements {
+		if !r.pattern.Match(src) {
+			return fmt.Errorf("%s not found in %s", r.pattern, fixtureFile)
+		}
+		src = r.pattern.ReplaceAll(src, []byte(r.value))
+	}
+	return os.WriteFile(fixtureFile, src, 0o644)
+}
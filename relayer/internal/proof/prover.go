@@ -0,0 +1,106 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"relayer/internal/config"
+	"relayer/internal/registry"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	customTypes "relayer/internal/types"
+	"relayer/pkg/contracts"
+)
+
+var ErrBlockNotAvailable = errors.New("state root oracle has no block covering the message yet")
+
+// Prover builds storage proofs for delivery. Proofs are built at the latest
+// source block the destination's oracle knows about, since messageExists is
+// never cleared once set.
+type Prover struct {
+	registry      *registry.Registry
+	retryInterval time.Duration
+}
+
+func NewProver(cfg *config.ProofConfig, registry *registry.Registry) (*Prover, error) {
+	retryInterval := 30 * time.Second
+	if cfg.RetryInterval != "" {
+		d, err := time.ParseDuration(cfg.RetryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proofs retry_interval: %w", err)
+		}
+		retryInterval = d
+	}
+
+	return &Prover{
+		registry:      registry,
+		retryInterval: retryInterval,
+	}, nil
+}
+
+// RetryInterval is how long to wait before proving again after
+// ErrBlockNotAvailable.
+func (p *Prover) RetryInterval() time.Duration {
+	return p.retryInterval
+}
+
+// Prove returns the encoded proof for msg, or ErrBlockNotAvailable if the
+// destination oracle has not caught up with the message's source block.
+func (p *Prover) Prove(ctx context.Context, msg *customTypes.CrossChainMessage) ([]byte, error) {
+	sourceChainID := msg.SourceChainID.Int64()
+	destChainID := msg.DestChainID.Int64()
+
+	sourceClient, ok := p.registry.Client(sourceChainID)
+	if !ok {
+		return nil, fmt.Errorf("no client for chain %d", sourceChainID)
+	}
+	sourceChain, ok := p.registry.Chain(sourceChainID)
+	if !ok {
+		return nil, fmt.Errorf("no config for chain %d", sourceChainID)
+	}
+	destClient, ok := p.registry.Client(destChainID)
+	if !ok {
+		return nil, fmt.Errorf("no client for chain %d", destChainID)
+	}
+	destChain, ok := p.registry.Chain(destChainID)
+	if !ok {
+		return nil, fmt.Errorf("no config for chain %d", destChainID)
+	}
+	if destChain.StateRootOracle == "" {
+		return nil, fmt.Errorf("chain %d has no state_root_oracle", destChainID)
+	}
+
+	oracle, err := contracts.NewStateRootOracle(destChain.GetStateRootOracle(), destClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate state root oracle: %w", err)
+	}
+
+	latest, err := oracle.LatestBlock(nil, msg.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest oracle block: %w", err)
+	}
+	if latest.Sign() == 0 || latest.Uint64() < msg.SourceBlock {
+		return nil, fmt.Errorf("%w (oracle at %s, message at %d)", ErrBlockNotAvailable, latest, msg.SourceBlock)
+	}
+
+	proof, err := Build(ctx, sourceClient, sourceChain.GetSourceContract(), msg.MessageHash, latest)
+	if err != nil {
+		return nil, err
+	}
+
+	// A mismatch means the oracle and our RPC disagree on the block,
+	// e.g. after a reorg; the contract would reject the proof anyway
+	posted, err := oracle.BlockHash(nil, msg.SourceChainID, new(big.Int).Set(latest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oracle block hash: %w", err)
+	}
+	if !bytes.Equal(posted[:], crypto.Keccak256(proof.Header)) {
+		return nil, fmt.Errorf("oracle block hash for block %s does not match source header", latest)
+	}
+
+	return proof.Encode()
+}
@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"fmt"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	customTypes "relayer/internal/types"
+)
+
+const (
+	KindSender = "sender"
+	KindRoute  = "route"
+)
+
+// evictInterval is how often buckets that have refilled are dropped. A full
+// bucket behaves like a new one, so dropping it loses nothing, and keeps
+// senders seen once from piling up.
+const evictInterval = time.Minute
+
+// Bucket is a snapshot of one token bucket.
+type Bucket struct {
+	Kind   string
+	Key    string
+	Tokens float64
+	Burst  int
+	Rate   rate.Limit
+}
+
+// Limiter applies token buckets per sender and per route. A message must
+// get a token from both; if either is empty neither is consumed.
+type Limiter struct {
+	mu      sync.Mutex
+	sender  config.RateLimit
+	route   config.RateLimit
+	senders map[string]*rate.Limiter
+	routes  map[string]*rate.Limiter
+	evicted time.Time
+}
+
+func NewLimiter(cfg *config.RateLimitConfig) *Limiter {
+	return &Limiter{
+		sender:  cfg.Sender,
+		route:   cfg.Route,
+		senders: make(map[string]*rate.Limiter),
+		routes:  make(map[string]*rate.Limiter),
+	}
+}
+
+// Reserve takes a token for the message's sender and route. It returns
+// zero if the message may be relayed now, or how long to defer it.
+func (l *Limiter) Reserve(msg *customTypes.CrossChainMessage) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	senderKey := msg.Sender.Hex()
+	routeKey := fmt.Sprintf("%s->%s", msg.SourceChainID, msg.DestChainID)
+
+	var reservations []*rate.Reservation
+	var delay time.Duration
+	var limitedBy string
+
+	if b := bucket(l.senders, senderKey, l.sender); b != nil {
+		r := b.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > delay {
+			delay, limitedBy = d, KindSender
+		}
+	}
+	if b := bucket(l.routes, routeKey, l.route); b != nil {
+		r := b.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > delay {
+			delay, limitedBy = d, KindRoute
+		}
+	}
+
+	if delay > 0 {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+		metrics.RateLimitDeferred.WithLabelValues(limitedBy).Inc()
+	}
+
+	l.report(routeKey, l.routes[routeKey], now)
+	if now.Sub(l.evicted) >= evictInterval {
+		l.evict(now)
+	}
+
+	return delay
+}
+
+// evict drops the buckets that are full again. Route buckets are bounded
+// by the configured chains but are dropped too, along with their gauges.
+func (l *Limiter) evict(now time.Time) {
+	l.evicted = now
+	for key, b := range l.senders {
+		if full(b, now) {
+			delete(l.senders, key)
+		}
+	}
+	for key, b := range l.routes {
+		if full(b, now) {
+			delete(l.routes, key)
+			metrics.RateLimitTokens.DeleteLabelValues(key)
+		}
+	}
+}
+
+func full(b *rate.Limiter, now time.Time) bool {
+	return b.TokensAt(now) >= float64(b.Burst())
+}
+
+// Reload applies new limits. Existing buckets keep their tokens; buckets
+// whose limit is now disabled are dropped.
+func (l *Limiter) Reload(cfg *config.RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sender = cfg.Sender
+	l.route = cfg.Route
+	retune(l.senders, cfg.Sender)
+	for key := range retune(l.routes, cfg.Route) {
+		metrics.RateLimitTokens.DeleteLabelValues(key)
+	}
+}
+
+// Buckets returns the remaining budget of every bucket in use. Buckets that
+// have refilled are dropped, so a sender missing here has a full budget.
+func (l *Limiter) Buckets() []Bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var out []Bucket
+	for key, b := range l.senders {
+		out = append(out, Bucket{KindSender, key, b.TokensAt(now), b.Burst(), b.Limit()})
+	}
+	for key, b := range l.routes {
+		out = append(out, Bucket{KindRoute, key, b.TokensAt(now), b.Burst(), b.Limit()})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// report exports a route bucket's tokens. Sender buckets are not exported,
+// since one series per sender address would grow without bound.
+func (l *Limiter) report(routeKey string, b *rate.Limiter, now time.Time) {
+	if b == nil {
+		return
+	}
+	metrics.RateLimitTokens.WithLabelValues(routeKey).Set(b.TokensAt(now))
+}
+
+// retune applies cfg to existing buckets and returns the keys of those
+// dropped because the limit is now disabled.
+func retune(buckets map[string]*rate.Limiter, cfg config.RateLimit) map[string]bool {
+	dropped := make(map[string]bool)
+	for key, b := range buckets {
+		if cfg.PerMinute <= 0 {
+			delete(buckets, key)
+			dropped[key] = true
+			continue
+		}
+		burst := cfg.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		b.SetLimit(rate.Limit(cfg.PerMinute / 60))
+		b.SetBurst(burst)
+	}
+	return dropped
+}
+
+// bucket returns the limiter for key, creating it on first use. A zero
+// rate means unlimited and returns nil.
+func bucket(buckets map[string]*rate.Limiter, key string, cfg config.RateLimit) *rate.Limiter {
+	if cfg.PerMinute <= 0 {
+		return nil
+	}
+	b, ok := buckets[key]
+	if !ok {
+		burst := cfg.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		b = rate.NewLimiter(rate.Limit(cfg.PerMinute/60), burst)
+		buckets[key] = b
+	}
+	return b
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"math/big"
+	"relayer/internal/config"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	customTypes "relayer/internal/types"
+)
+
+func message(sender common.Address) *customTypes.CrossChainMessage {
+	return &customTypes.CrossChainMessage{
+		SourceChainID: big.NewInt(1),
+		DestChainID:   big.NewInt(2),
+		Sender:        sender,
+	}
+}
+
+func TestReserve(t *testing.T) {
+	l := NewLimiter(&config.RateLimitConfig{
+		Sender: config.RateLimit{PerMinute: 1, Burst: 1},
+		Route:  config.RateLimit{PerMinute: 60, Burst: 2},
+	})
+	alice := common.HexToAddress("0xa11ce")
+	bob := common.HexToAddress("0xb0b")
+
+	if d := l.Reserve(message(alice)); d != 0 {
+		t.Fatalf("first message deferred by %s", d)
+	}
+	if d := l.Reserve(message(alice)); d <= 0 {
+		t.Fatalf("second message from the same sender not deferred")
+	}
+	// The deferred message took no route token
+	if d := l.Reserve(message(bob)); d != 0 {
+		t.Fatalf("message from another sender deferred by %s", d)
+	}
+	if d := l.Reserve(message(common.HexToAddress("0xca201"))); d <= 0 {
+		t.Fatalf("message over the route burst not deferred")
+	}
+}
+
+func TestEvictDropsFullBuckets(t *testing.T) {
+	l := NewLimiter(&config.RateLimitConfig{
+		Sender: config.RateLimit{PerMinute: 60, Burst: 1},
+		Route:  config.RateLimit{PerMinute: 1, Burst: 5},
+	})
+	for i := 0; i < 3; i++ {
+		l.Reserve(message(common.BigToAddress(big.NewInt(int64(i + 1)))))
+	}
+	if len(l.senders) != 3 {
+		t.Fatalf("got %d sender buckets, want 3", len(l.senders))
+	}
+
+	// Sender buckets refill within a second; the route bucket takes minutes
+	l.mu.Lock()
+	l.evict(time.Now().Add(2 * time.Second))
+	l.mu.Unlock()
+
+	if len(l.senders) != 0 {
+		t.Errorf("got %d sender buckets after eviction, want 0", len(l.senders))
+	}
+	if len(l.routes) != 1 {
+		t.Errorf("got %d route buckets after eviction, want the partly used one", len(l.routes))
+	}
+}
+
+func TestReloadDisablesLimit(t *testing.T) {
+	l := NewLimiter(&config.RateLimitConfig{Sender: config.RateLimit{PerMinute: 1, Burst: 1}})
+	alice := common.HexToAddress("0xa11ce")
+	l.Reserve(message(alice))
+
+	l.Reload(&config.RateLimitConfig{})
+	if d := l.Reserve(message(alice)); d != 0 {
+		t.Fatalf("message deferred by %s after the limit was disabled", d)
+	}
+	if len(l.Buckets()) != 0 {
+		t.Errorf("Buckets = %v, want none", l.Buckets())
+	}
+}
@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"relayer/internal/config"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Registry holds the config and RPC client of every configured chain. It is
+// shared by the executor and its helpers so a config reload can add, remove
+// or swap chains while they run.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[int64]*ethclient.Client
+	chains  map[int64]*config.ChainConfig
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		clients: make(map[int64]*ethclient.Client),
+		chains:  make(map[int64]*config.ChainConfig),
+	}
+}
+
+// Set adds a chain or replaces its config and client.
+func (r *Registry) Set(chain *config.ChainConfig, client *ethclient.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chains[chain.ChainID] = chain
+	r.clients[chain.ChainID] = client
+}
+
+func (r *Registry) Remove(chainID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.chains, chainID)
+	delete(r.clients, chainID)
+}
+
+func (r *Registry) Client(chainID int64) (*ethclient.Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, ok := r.clients[chainID]
+	return client, ok
+}
+
+func (r *Registry) Chain(chainID int64) (*config.ChainConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	chain, ok := r.chains[chainID]
+	return chain, ok
+}
+
+// ChainIDs returns the configured chain IDs in ascending order.
+func (r *Registry) ChainIDs() []int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]int64, 0, len(r.chains))
+	for id := range r.chains {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
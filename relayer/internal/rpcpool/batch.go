@@ -0,0 +1,147 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	maxBatchSize = 50
+	flushTimeout = time.Minute
+)
+
+// batchMethods are coalesced into batch requests: the contract reads and
+// receipt polls the executor and watcher issue for every message.
+var batchMethods = map[string]bool{
+	"eth_call":                  true,
+	"eth_getTransactionReceipt": true,
+}
+
+type batchResult struct {
+	resp []byte
+	err  error
+}
+
+type batchCall struct {
+	call rpcCall
+	body []byte
+	done chan batchResult
+}
+
+// batcher collects calls for window after the first one arrives, or until
+// maxBatchSize are queued, and sends them as a single batch request.
+type batcher struct {
+	pool   *Pool
+	window time.Duration
+
+	mu    sync.Mutex
+	queue []*batchCall
+	timer *time.Timer
+}
+
+func (b *batcher) call(ctx context.Context, call rpcCall, body []byte) ([]byte, error) {
+	c := &batchCall{call: call, body: body, done: make(chan batchResult, 1)}
+
+	b.mu.Lock()
+	b.queue = append(b.queue, c)
+	if len(b.queue) >= maxBatchSize {
+		calls := b.take()
+		b.mu.Unlock()
+		go b.flush(calls)
+	} else {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, b.flushQueued)
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case r := <-c.done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take empties the queue. The caller holds b.mu.
+func (b *batcher) take() []*batchCall {
+	calls := b.queue
+	b.queue = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return calls
+}
+
+func (b *batcher) flushQueued() {
+	b.mu.Lock()
+	calls := b.take()
+	b.mu.Unlock()
+	b.flush(calls)
+}
+
+func (b *batcher) flush(calls []*batchCall) {
+	if len(calls) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if len(calls) == 1 {
+		resp, err := b.pool.failoverCall(ctx, nil, calls[0].body, calls[0].call.Method, 1)
+		calls[0].done <- batchResult{resp: resp, err: err}
+		return
+	}
+
+	// Number the calls so responses can be matched up in any order
+	reqs := make([]rpcCall, len(calls))
+	for i, c := range calls {
+		reqs[i] = c.call
+		reqs[i].ID = json.RawMessage(strconv.Itoa(i))
+	}
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		b.fail(calls, err)
+		return
+	}
+
+	resp, err := b.pool.failoverCall(ctx, nil, body, "batch", len(calls))
+	if err != nil {
+		b.fail(calls, err)
+		return
+	}
+	var replies []map[string]json.RawMessage
+	if err := json.Unmarshal(resp, &replies); err != nil {
+		b.fail(calls, fmt.Errorf("invalid batch response: %w", err))
+		return
+	}
+
+	answered := make([]bool, len(calls))
+	for _, reply := range replies {
+		id, err := strconv.Atoi(string(reply["id"]))
+		if err != nil || id < 0 || id >= len(calls) || answered[id] {
+			continue
+		}
+		reply["id"] = calls[id].call.ID
+		out, err := json.Marshal(reply)
+		calls[id].done <- batchResult{resp: out, err: err}
+		answered[id] = true
+	}
+	for i, c := range calls {
+		if !answered[i] {
+			c.done <- batchResult{err: fmt.Errorf("no response to %s in batch", c.call.Method)}
+		}
+	}
+}
+
+func (b *batcher) fail(calls []*batchCall, err error) {
+	for _, c := range calls {
+		c.done <- batchResult{err: err}
+	}
+}
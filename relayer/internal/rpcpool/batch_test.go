@@ -0,0 +1,136 @@
+package rpcpool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"relayer/internal/config"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// batchServer answers batches in reverse order, echoing each call's id as
+// its result, skipping calls whose id is in drop. It records the size of
+// every request.
+func batchServer(t *testing.T, drop ...string) (*httptest.Server, func() []int) {
+	var (
+		mu    sync.Mutex
+		sizes []int
+	)
+	srv, _ := server(t, func(w http.ResponseWriter, body string) {
+		var calls []rpcCall
+		if err := json.Unmarshal([]byte(body), &calls); err != nil {
+			t.Errorf("request is not a batch: %s", body)
+			return
+		}
+		mu.Lock()
+		sizes = append(sizes, len(calls))
+		mu.Unlock()
+
+		var out []json.RawMessage
+		for i := len(calls) - 1; i >= 0; i-- {
+			id := string(calls[i].ID)
+			if !contains(drop, id) {
+				out = append(out, json.RawMessage(result(id, id)))
+			}
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+	return srv, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), sizes...)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// callBatcher makes n concurrent eth_calls through p's batcher and returns
+// their responses and errors by caller.
+func callBatcher(t *testing.T, p *Pool, n int) ([]string, []error) {
+	resps := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			call := rpcCall{Version: "2.0", ID: json.RawMessage(fmt.Sprintf(`"caller-%d"`, i)), Method: "eth_call", Params: json.RawMessage(`[]`)}
+			body, _ := json.Marshal(call)
+			resp, err := p.batcher.call(t.Context(), call, body)
+			resps[i], errs[i] = string(resp), err
+		}(i)
+	}
+	wg.Wait()
+	return resps, errs
+}
+
+func TestBatchFlushesAtMaxSize(t *testing.T) {
+	srv, sizes := batchServer(t)
+	// The window is too long to flush before the test times out
+	p := newTestPool(t, 1, config.RPCConfig{BatchWindow: "1h"}, srv)
+
+	resps, errs := callBatcher(t, p, maxBatchSize)
+	for i := range resps {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		// Responses are matched back to their caller's id
+		if want := fmt.Sprintf(`"id":"caller-%d"`, i); !strings.Contains(resps[i], want) {
+			t.Errorf("caller %d got %s", i, resps[i])
+		}
+	}
+	if got := sizes(); len(got) != 1 || got[0] != maxBatchSize {
+		t.Errorf("batch sizes = %v, want [%d]", got, maxBatchSize)
+	}
+}
+
+func TestBatchMissingReply(t *testing.T) {
+	srv, sizes := batchServer(t, "0")
+	p := newTestPool(t, 1, config.RPCConfig{BatchWindow: "1h"}, srv)
+
+	_, errs := callBatcher(t, p, maxBatchSize)
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+			if !strings.Contains(err.Error(), "no response to eth_call in batch") {
+				t.Errorf("unexpected error %v", err)
+			}
+		}
+	}
+	if failed != 1 {
+		t.Errorf("%d calls failed, want the one left unanswered", failed)
+	}
+	if got := sizes(); len(got) != 1 {
+		t.Errorf("batch sizes = %v, want one batch", got)
+	}
+}
+
+func TestLoneCallNotBatched(t *testing.T) {
+	srv, hits := server(t, func(w http.ResponseWriter, body string) {
+		if strings.HasPrefix(body, "[") {
+			t.Errorf("lone call sent as a batch: %s", body)
+		}
+		io.WriteString(w, result(`"caller-0"`, `"0x"`))
+	})
+	p := newTestPool(t, 1, config.RPCConfig{BatchWindow: "1ms"}, srv)
+
+	resps, errs := callBatcher(t, p, 1)
+	if errs[0] != nil {
+		t.Fatalf("call: %v", errs[0])
+	}
+	if !strings.Contains(resps[0], `"caller-0"`) || hits.Load() != 1 {
+		t.Errorf("call = %s after %d requests", resps[0], hits.Load())
+	}
+}
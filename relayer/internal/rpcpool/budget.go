@@ -0,0 +1,142 @@
+package rpcpool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+	// Times a call waits for a backed-off endpoint before giving up
+	maxBackoffRounds = 5
+	// JSON-RPC error code providers such as Infura use for exceeded limits
+	codeLimitExceeded = -32005
+)
+
+// errRateLimited is returned by send when an endpoint answers with HTTP 429
+// or a JSON-RPC rate limit error.
+var errRateLimited = errors.New("rate limited")
+
+func newLimiter(cfg *config.RPCConfig) *rate.Limiter {
+	if cfg.RateLimit <= 0 {
+		return nil
+	}
+	burst := cfg.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(cfg.RateLimit), burst)
+}
+
+// take waits until the endpoint's budget allows cost more requests. Costs
+// above the burst are capped so large batches are not rejected outright.
+func (e *endpoint) take(ctx context.Context, cost int) error {
+	if e.limiter == nil {
+		return nil
+	}
+	if cost > e.limiter.Burst() {
+		cost = e.limiter.Burst()
+	}
+	return e.limiter.WaitN(ctx, cost)
+}
+
+// backOff stops requests to the endpoint for retryAfter or, if the endpoint
+// gave none, for a delay that doubles on every consecutive rate limit.
+func (e *endpoint) backOff(retryAfter time.Duration) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delay := retryAfter
+	if delay <= 0 {
+		delay = minBackoff << e.backoffStep
+		if delay >= maxBackoff {
+			delay = maxBackoff
+		} else {
+			e.backoffStep++
+		}
+	}
+	e.backoffUntil = time.Now().Add(delay)
+	return delay
+}
+
+func (e *endpoint) resetBackoff() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.backoffStep = 0
+}
+
+// backedOff returns when the endpoint's backoff ends and whether it is
+// still running.
+func (e *endpoint) backedOff() (time.Time, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.backoffUntil, time.Now().Before(e.backoffUntil)
+}
+
+// throttle backs ep off after a rate limit response and returns the error
+// send reports for it.
+func (p *Pool) throttle(ep *endpoint, retryAfter time.Duration) error {
+	delay := ep.backOff(retryAfter)
+	metrics.RPCRateLimited.WithLabelValues(fmt.Sprint(p.chainID), ep.label).Inc()
+	log.Printf(" RPC endpoint %s on chain %d rate limited, backing off %s", ep.label, p.chainID, delay)
+	return fmt.Errorf("%w, retry in %s", errRateLimited, delay)
+}
+
+// nextAvailable returns when the first backed-off endpoint comes back, or
+// false if none is backed off.
+func (p *Pool) nextAvailable() (time.Time, bool) {
+	var next time.Time
+	found := false
+	for _, ep := range p.endpoints {
+		until, backedOff := ep.backedOff()
+		if backedOff && (!found || until.Before(next)) {
+			next = until
+			found = true
+		}
+	}
+	return next, found
+}
+
+// retryAfter parses a Retry-After header given in seconds.
+func retryAfter(header http.Header) time.Duration {
+	secs, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	delay := time.Duration(secs) * time.Second
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// limitExceeded reports whether a single JSON-RPC response is a rate limit
+// error. Infura also uses -32005 for oversized log queries, so the message
+// is checked too.
+func limitExceeded(resp []byte) bool {
+	var msg struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &msg); err != nil || msg.Error == nil {
+		return false
+	}
+	if msg.Error.Code != codeLimitExceeded && msg.Error.Code != http.StatusTooManyRequests {
+		return false
+	}
+	return strings.Contains(strings.ToLower(msg.Error.Message), "rate")
+}
@@ -0,0 +1,149 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"relayer/internal/config"
+	"testing"
+	"time"
+)
+
+func TestBackOff(t *testing.T) {
+	ep := &endpoint{}
+
+	var got []time.Duration
+	for i := 0; i < 7; i++ {
+		got = append(got, ep.backOff(0))
+	}
+	want := []time.Duration{1, 2, 4, 8, 16, 30, 30}
+	for i := range want {
+		if got[i] != want[i]*time.Second {
+			t.Fatalf("backOff delays = %v, want doubling from 1s up to 30s", got)
+		}
+	}
+	if _, backedOff := ep.backedOff(); !backedOff {
+		t.Error("endpoint not backed off")
+	}
+
+	// Retry-After wins over the doubling delay
+	if d := ep.backOff(3 * time.Second); d != 3*time.Second {
+		t.Errorf("backOff(3s) = %s", d)
+	}
+
+	ep.resetBackoff()
+	if d := ep.backOff(0); d != time.Second {
+		t.Errorf("backOff after reset = %s, want 1s", d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"0", 0},
+		{"-1", 0},
+		{"3600", maxBackoff},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.header != "" {
+			header.Set("Retry-After", tt.header)
+		}
+		if got := retryAfter(header); got != tt.want {
+			t.Errorf("retryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLimitExceeded(t *testing.T) {
+	tests := []struct {
+		resp string
+		want bool
+	}{
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"daily request count exceeded, request rate limited"}}`, true},
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":429,"message":"Rate limit reached"}}`, true},
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"query returned more than 10000 results"}}`, false},
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"rate limited"}}`, false},
+		{`{"jsonrpc":"2.0","id":1,"result":"0x1"}`, false},
+		{`[{"jsonrpc":"2.0","id":1,"result":"0x1"}]`, false},
+	}
+
+	for _, tt := range tests {
+		if got := limitExceeded([]byte(tt.resp)); got != tt.want {
+			t.Errorf("limitExceeded(%s) = %v, want %v", tt.resp, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitedEndpointBackedOff(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	limited, limitedHits := server(t, func(w http.ResponseWriter, _ string) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	jsonLimited, _ := server(t, reply(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"rate limit exceeded"}}`))
+	up, upHits := server(t, reply(result("1", `"0x10"`)))
+	p := newTestPool(t, 1, config.RPCConfig{}, limited, jsonLimited, up)
+
+	for i := 0; i < 3; i++ {
+		if _, err := p.failoverCall(t.Context(), nil, body, "eth_blockNumber", 1); err != nil {
+			t.Fatalf("failoverCall: %v", err)
+		}
+	}
+	if n := limitedHits.Load(); n != 1 {
+		t.Errorf("rate limited endpoint got %d requests, want 1", n)
+	}
+	if n := upHits.Load(); n != 3 {
+		t.Errorf("healthy endpoint got %d requests, want 3", n)
+	}
+	for _, ep := range p.endpoints[:2] {
+		if _, backedOff := ep.backedOff(); !backedOff {
+			t.Errorf("%s not backed off", ep.label)
+		}
+		// A rate limit says nothing about the endpoint's health
+		if !ep.isHealthy() {
+			t.Errorf("%s marked unhealthy", ep.label)
+		}
+	}
+	if until, _ := p.endpoints[0].backedOff(); time.Until(until) < 20*time.Second {
+		t.Errorf("Retry-After ignored, backed off until %s", until)
+	}
+}
+
+func TestAllEndpointsRateLimited(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	limited, _ := server(t, func(w http.ResponseWriter, _ string) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	p := newTestPool(t, 1, config.RPCConfig{}, limited)
+
+	// The call waits for the backoff, so give up before it ends
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	_, err := p.failoverCall(ctx, nil, body, "eth_blockNumber", 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("failoverCall error = %v, want the deadline", err)
+	}
+}
+
+func TestTakeCapsCostAtBurst(t *testing.T) {
+	ep := &endpoint{limiter: newLimiter(&config.RPCConfig{RateLimit: 1000, Burst: 5})}
+	// A batch larger than the burst waits for the burst instead of failing
+	if err := ep.take(t.Context(), maxBatchSize); err != nil {
+		t.Fatalf("take: %v", err)
+	}
+
+	if newLimiter(&config.RPCConfig{}) != nil {
+		t.Error("limiter created without a rate limit")
+	}
+	if l := newLimiter(&config.RPCConfig{RateLimit: 10}); l.Burst() != 1 {
+		t.Errorf("default burst = %d, want 1", l.Burst())
+	}
+}
@@ -0,0 +1,102 @@
+package rpcpool
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// hashPinnedMethods take a block parameter last. Their result is cached
+// only when that parameter is a block hash.
+var hashPinnedMethods = map[string]bool{
+	"eth_call":         true,
+	"eth_getBalance":   true,
+	"eth_getCode":      true,
+	"eth_getProof":     true,
+	"eth_getStorageAt": true,
+}
+
+// cache holds results that cannot change, evicting the oldest entry once
+// size is reached.
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]json.RawMessage
+	order   []string
+}
+
+func newCache(size int) *cache {
+	return &cache{
+		size:    size,
+		entries: make(map[string]json.RawMessage, size),
+	}
+}
+
+func (c *cache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.entries[key]
+	return result, ok
+}
+
+func (c *cache) put(key string, result json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = result
+	c.order = append(c.order, key)
+}
+
+// cacheKey returns the cache key for calls whose result is immutable: the
+// chain ID, lookups by block hash and state reads pinned to a block hash.
+// Anything addressed by block number or tag can change with a reorg.
+func cacheKey(call rpcCall) (string, bool) {
+	switch {
+	case call.Method == "eth_chainId" || call.Method == "net_version":
+		return call.Method, true
+	case call.Method == "eth_getBlockByHash":
+	case call.Method == "eth_getLogs":
+		var params []struct {
+			BlockHash *string `json:"blockHash"`
+		}
+		if err := json.Unmarshal(call.Params, &params); err != nil || len(params) != 1 || params[0].BlockHash == nil {
+			return "", false
+		}
+	case hashPinnedMethods[call.Method]:
+		var params []json.RawMessage
+		if err := json.Unmarshal(call.Params, &params); err != nil || len(params) == 0 {
+			return "", false
+		}
+		if !pinnedToHash(params[len(params)-1]) {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, call.Params); err != nil {
+		return "", false
+	}
+	return call.Method + buf.String(), true
+}
+
+// pinnedToHash reports whether a block parameter names a block by hash,
+// either as a bare hash or as an EIP-1898 object.
+func pinnedToHash(raw json.RawMessage) bool {
+	var hash string
+	if err := json.Unmarshal(raw, &hash); err == nil {
+		return len(hash) == 66
+	}
+	var block struct {
+		BlockHash *string `json:"blockHash"`
+	}
+	return json.Unmarshal(raw, &block) == nil && block.BlockHash != nil
+}
@@ -0,0 +1,100 @@
+package rpcpool
+
+import (
+	"encoding/json"
+	"relayer/internal/config"
+	"testing"
+)
+
+const blockHash = `"0x00000000000000000000000000000000000000000000000000000000000000aa"`
+
+func TestCacheKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		params    string
+		cacheable bool
+	}{
+		{"chain id", "eth_chainId", `[]`, true},
+		{"net version", "net_version", `[]`, true},
+		{"block by hash", "eth_getBlockByHash", `[` + blockHash + `,false]`, true},
+		{"block by number", "eth_getBlockByNumber", `["0x10",false]`, false},
+		{"logs by block hash", "eth_getLogs", `[{"blockHash":` + blockHash + `}]`, true},
+		{"logs by range", "eth_getLogs", `[{"fromBlock":"0x1","toBlock":"0x10"}]`, false},
+		{"call at block hash", "eth_call", `[{"to":"0x01"},` + blockHash + `]`, true},
+		{"call at EIP-1898 hash", "eth_call", `[{"to":"0x01"},{"blockHash":` + blockHash + `}]`, true},
+		{"call at latest", "eth_call", `[{"to":"0x01"},"latest"]`, false},
+		{"call at EIP-1898 number", "eth_call", `[{"to":"0x01"},{"blockNumber":"0x10"}]`, false},
+		{"storage at block hash", "eth_getStorageAt", `["0x01","0x0",` + blockHash + `]`, true},
+		{"storage at number", "eth_getStorageAt", `["0x01","0x0","0x10"]`, false},
+		{"call without block", "eth_call", `[]`, false},
+		{"receipt", "eth_getTransactionReceipt", `[` + blockHash + `]`, false},
+		{"block number", "eth_blockNumber", `[]`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := cacheKey(rpcCall{Method: tt.method, Params: json.RawMessage(tt.params)})
+			if ok != tt.cacheable {
+				t.Errorf("cacheKey cacheable = %v, want %v", ok, tt.cacheable)
+			}
+		})
+	}
+
+	// Keys ignore whitespace but not the parameters
+	a, _ := cacheKey(rpcCall{Method: "eth_getBlockByHash", Params: json.RawMessage(`[` + blockHash + `, false]`)})
+	b, _ := cacheKey(rpcCall{Method: "eth_getBlockByHash", Params: json.RawMessage(`[` + blockHash + `,false]`)})
+	c, _ := cacheKey(rpcCall{Method: "eth_getBlockByHash", Params: json.RawMessage(`[` + blockHash + `,true]`)})
+	if a != b {
+		t.Errorf("cacheKey differs on whitespace: %q, %q", a, b)
+	}
+	if a == c {
+		t.Errorf("cacheKey ignores parameters: %q", a)
+	}
+}
+
+func TestCacheEvictsOldest(t *testing.T) {
+	c := newCache(2)
+	c.put("a", json.RawMessage(`1`))
+	c.put("b", json.RawMessage(`2`))
+	c.put("a", json.RawMessage(`3`))
+	c.put("c", json.RawMessage(`4`))
+
+	if _, ok := c.get("a"); ok {
+		t.Error("oldest entry not evicted")
+	}
+	for key, want := range map[string]string{"b": "2", "c": "4"} {
+		if got, ok := c.get(key); !ok || string(got) != want {
+			t.Errorf("get(%s) = %s, %v, want %s", key, got, ok, want)
+		}
+	}
+}
+
+func TestRoundTripCache(t *testing.T) {
+	srv, hits := server(t, reply(result("1", `"0x1"`)))
+	p := newTestPool(t, 1, config.RPCConfig{CacheSize: 10}, srv)
+	ctx := t.Context()
+
+	for i := 0; i < 3; i++ {
+		id, err := p.Client().ChainID(ctx)
+		if err != nil {
+			t.Fatalf("ChainID: %v", err)
+		}
+		if id.Int64() != 1 {
+			t.Fatalf("ChainID = %s, want 1", id)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("eth_chainId sent %d times, want 1", n)
+	}
+
+	// Block numbers are never cached
+	for i := 0; i < 2; i++ {
+		if _, err := p.Client().BlockNumber(ctx); err != nil {
+			t.Fatalf("BlockNumber: %v", err)
+		}
+	}
+	if n := hits.Load(); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+}
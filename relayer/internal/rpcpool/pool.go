@@ -0,0 +1,672 @@
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"relayer/internal/config"
+	"relayer/internal/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	healthInterval = 15 * time.Second
+	attemptTimeout = 20 * time.Second
+	// Endpoints further than this behind the best head are marked unhealthy
+	maxHeadLag = 20
+)
+
+// quorumMethods are the calls whose results must match on RpcQuorum
+// endpoints: the logs messages are detected from and delivery receipts.
+var quorumMethods = map[string]bool{
+	"eth_getLogs":               true,
+	"eth_getTransactionReceipt": true,
+}
+
+type endpoint struct {
+	url     *url.URL
+	label   string
+	limiter *rate.Limiter
+
+	mu           sync.Mutex
+	healthy      bool
+	head         uint64
+	backoffUntil time.Time
+	backoffStep  int
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthy
+}
+
+// Pool spreads a chain's JSON-RPC traffic over several HTTP endpoints. It
+// is the http.RoundTripper behind the chain's ethclient: requests go to the
+// first healthy endpoint and fail over to the next on transport errors or
+// non-2xx responses. With a quorum above one, logs and receipts are fetched
+// from every endpoint and only returned once enough of them agree.
+//
+// Every endpoint has its own request budget and is backed off when it
+// answers with a rate limit error. Concurrent eth_call and receipt lookups
+// are batched, and results pinned to a block hash are cached.
+type Pool struct {
+	chainID   int64
+	endpoints []*endpoint
+	quorum    int
+	transport http.RoundTripper
+	client    *ethclient.Client
+	batcher   *batcher
+	cache     *cache
+}
+
+// NewPool builds the pool for a chain. It does not contact any endpoint, so
+// an unreachable provider does not stop the relayer from starting. A single
+// non-HTTP endpoint (e.g. ws://) is dialled directly, without failover,
+// budgeting, batching or caching.
+func NewPool(ctx context.Context, chain *config.ChainConfig, cfg *config.RPCConfig) (*Pool, error) {
+	urls := chain.GetRpcURLs()
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no rpc_url configured for %s", chain.Name)
+	}
+
+	p := &Pool{
+		chainID:   chain.ChainID,
+		quorum:    chain.RpcQuorum,
+		transport: http.DefaultTransport,
+	}
+	if p.quorum < 1 {
+		p.quorum = 1
+	}
+	if p.quorum > len(urls) {
+		return nil, fmt.Errorf("rpc_quorum %d exceeds the %d endpoints of %s", p.quorum, len(urls), chain.Name)
+	}
+
+	if len(urls) == 1 && !isHTTP(urls[0]) {
+		client, err := ethclient.DialContext(ctx, urls[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", chain.Name, err)
+		}
+		p.client = client
+		return p, nil
+	}
+
+	if cfg.RateLimit < 0 {
+		return nil, fmt.Errorf("rpc rate_limit must not be negative")
+	}
+	if cfg.BatchWindow != "" {
+		window, err := time.ParseDuration(cfg.BatchWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc batch_window: %w", err)
+		}
+		if window > 0 {
+			p.batcher = &batcher{pool: p, window: window}
+		}
+	}
+	if cfg.CacheSize > 0 {
+		p.cache = newCache(cfg.CacheSize)
+	}
+
+	labels := make(map[string]int)
+	for _, raw := range urls {
+		if !isHTTP(raw) {
+			return nil, fmt.Errorf("%s: only http(s) endpoints can be pooled", chain.Name)
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc url for %s: %w", chain.Name, err)
+		}
+		// Label by host only, since API keys usually live in the path
+		label := u.Host
+		labels[u.Host]++
+		if n := labels[u.Host]; n > 1 {
+			label = fmt.Sprintf("%s#%d", u.Host, n)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:     u,
+			label:   label,
+			limiter: newLimiter(cfg),
+			healthy: true,
+		})
+	}
+
+	rpcClient, err := rpc.DialOptions(ctx, urls[0], rpc.WithHTTPClient(&http.Client{Transport: p}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rpc client for %s: %w", chain.Name, err)
+	}
+	p.client = ethclient.NewClient(rpcClient)
+
+	return p, nil
+}
+
+func isHTTP(raw string) bool {
+	return strings.HasPrefix(raw, "http://") || strings.HasPrefix(raw, "https://")
+}
+
+// Client returns the ethclient that routes through the pool.
+func (p *Pool) Client() *ethclient.Client {
+	return p.client
+}
+
+// Start health-checks every endpoint until ctx is cancelled. Endpoints that
+// fail or lag the best head by more than maxHeadLag blocks are tried last.
+// Backed-off endpoints are not probed and keep their last state.
+func (p *Pool) Start(ctx context.Context) {
+	if len(p.endpoints) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+
+	for {
+		p.checkHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pool) checkHealth(ctx context.Context) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+
+	heads := make([]uint64, len(p.endpoints))
+	ok := make([]bool, len(p.endpoints))
+	skipped := make([]bool, len(p.endpoints))
+
+	var wg sync.WaitGroup
+	for i, ep := range p.endpoints {
+		if _, backedOff := ep.backedOff(); backedOff {
+			skipped[i] = true
+			continue
+		}
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			if err := ep.take(ctx, 1); err != nil {
+				skipped[i] = true
+				return
+			}
+			resp, err := p.send(ctx, ep, nil, body, "eth_blockNumber")
+			if errors.Is(err, errRateLimited) {
+				skipped[i] = true
+				return
+			}
+			if err != nil {
+				return
+			}
+			var result struct {
+				Result hexutil.Uint64 `json:"result"`
+			}
+			if err := json.Unmarshal(resp, &result); err != nil {
+				return
+			}
+			heads[i] = uint64(result.Result)
+			ok[i] = true
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var best uint64
+	for i := range heads {
+		if ok[i] && heads[i] > best {
+			best = heads[i]
+		}
+	}
+
+	for i, ep := range p.endpoints {
+		if skipped[i] {
+			continue
+		}
+		healthy := ok[i] && heads[i]+maxHeadLag >= best
+		ep.mu.Lock()
+		changed := ep.healthy != healthy
+		ep.healthy = healthy
+		ep.head = heads[i]
+		ep.mu.Unlock()
+
+		if changed {
+			if healthy {
+				log.Printf(" RPC endpoint %s on chain %d recovered", ep.label, p.chainID)
+			} else {
+				log.Printf(" RPC endpoint %s on chain %d unhealthy (head %d, best %d)", ep.label, p.chainID, heads[i], best)
+			}
+		}
+		p.setUp(ep, healthy)
+	}
+}
+
+// RoundTrip implements http.RoundTripper for the pool's rpc client.
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx := req.Context()
+
+	call, batch := parseRequest(body)
+	method := call.Method
+	if batch > 0 {
+		method = "batch"
+	}
+
+	key, cacheable := "", false
+	if batch == 0 && p.cache != nil {
+		key, cacheable = cacheKey(call)
+	}
+	if cacheable {
+		if result, ok := p.cache.get(key); ok {
+			metrics.RPCCacheHits.WithLabelValues(fmt.Sprint(p.chainID), method).Inc()
+			return response(req, envelope(call.ID, result)), nil
+		}
+	}
+
+	var (
+		resp []byte
+		err  error
+	)
+	switch {
+	case batch > 0:
+		resp, err = p.failoverCall(ctx, req.Header, body, method, batch)
+	case p.quorum > 1 && quorumMethods[method]:
+		resp, err = p.quorumCall(ctx, req.Header, body, method)
+	case p.batcher != nil && batchMethods[method]:
+		resp, err = p.batcher.call(ctx, call, body)
+	default:
+		resp, err = p.failoverCall(ctx, req.Header, body, method, 1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		if result, ok := successResult(resp); ok {
+			p.cache.put(key, result)
+		}
+	}
+	return response(req, resp), nil
+}
+
+func response(req *http.Request, resp []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(resp)),
+		ContentLength: int64(len(resp)),
+		Request:       req,
+	}
+}
+
+// failoverCall tries healthy endpoints first, in configured order, then the
+// unhealthy ones as a last resort. Backed-off endpoints are skipped; if
+// every endpoint is backed off the call waits for the first to come back.
+// cost is the number of requests body counts against an endpoint's budget.
+func (p *Pool) failoverCall(ctx context.Context, header http.Header, body []byte, method string, cost int) ([]byte, error) {
+	var lastErr error
+	for round := 0; ; round++ {
+		for _, ep := range p.ordered() {
+			if _, backedOff := ep.backedOff(); backedOff {
+				continue
+			}
+			if err := ep.take(ctx, cost); err != nil {
+				return nil, err
+			}
+			resp, err := p.send(ctx, ep, header, body, method)
+			if err == nil {
+				return resp, nil
+			}
+			lastErr = err
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !errors.Is(err, errRateLimited) {
+				log.Printf(" RPC endpoint %s on chain %d failed %s: %v", ep.label, p.chainID, method, err)
+			}
+		}
+
+		until, ok := p.nextAvailable()
+		if !ok || round >= maxBackoffRounds {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Until(until)):
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = errRateLimited
+	}
+	return nil, fmt.Errorf("all rpc endpoints for chain %d failed: %w", p.chainID, lastErr)
+}
+
+// quorumCall sends the request to every endpoint that is not backed off and
+// returns the first response whose result quorum endpoints agree on.
+func (p *Pool) quorumCall(ctx context.Context, header http.Header, body []byte, method string) ([]byte, error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type reply struct {
+		key  string
+		resp []byte
+	}
+	replies := make(chan reply, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		go func(ep *endpoint) {
+			if _, backedOff := ep.backedOff(); backedOff {
+				replies <- reply{}
+				return
+			}
+			if err := ep.take(ctx, 1); err != nil {
+				replies <- reply{}
+				return
+			}
+			resp, err := p.send(ctx, ep, header, body, method)
+			if err != nil {
+				replies <- reply{}
+				return
+			}
+			replies <- reply{key: resultKey(method, resp), resp: resp}
+		}(ep)
+	}
+
+	counts := make(map[string]int)
+	for range p.endpoints {
+		r := <-replies
+		if r.key == "" {
+			continue
+		}
+		counts[r.key]++
+		if counts[r.key] >= p.quorum {
+			return r.resp, nil
+		}
+	}
+
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	metrics.RPCQuorumFailures.WithLabelValues(fmt.Sprint(p.chainID), method).Inc()
+	return nil, fmt.Errorf("rpc quorum of %d not reached on chain %d for %s", p.quorum, p.chainID, method)
+}
+
+// send posts body to one endpoint and returns the response body. Transport
+// errors and non-2xx statuses mark the endpoint unhealthy until it passes a
+// health check. Rate limit responses back the endpoint off instead.
+func (p *Pool) send(ctx context.Context, ep *endpoint, header http.Header, body []byte, method string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		req.Header = header.Clone()
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := p.transport.RoundTrip(req)
+	if err == nil {
+		var data []byte
+		data, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		switch {
+		case err != nil:
+		case resp.StatusCode == http.StatusTooManyRequests:
+			err = p.throttle(ep, retryAfter(resp.Header))
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		case limitExceeded(data):
+			err = p.throttle(ep, 0)
+		default:
+			ep.resetBackoff()
+			p.record(ep, method, time.Since(start), nil)
+			return data, nil
+		}
+	}
+
+	p.record(ep, method, time.Since(start), err)
+	return nil, err
+}
+
+func (p *Pool) record(ep *endpoint, method string, latency time.Duration, err error) {
+	chainID := fmt.Sprint(p.chainID)
+	status := "ok"
+	switch {
+	case errors.Is(err, errRateLimited):
+		status = "rate_limited"
+	case err != nil:
+		status = "error"
+	}
+	metrics.RPCRequests.WithLabelValues(chainID, ep.label, method, status).Inc()
+	metrics.RPCLatency.WithLabelValues(chainID, ep.label).Observe(latency.Seconds())
+
+	// A cancelled caller or a rate limit says nothing about the endpoint's
+	// health. Only the health check marks an endpoint healthy again.
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, errRateLimited) {
+		return
+	}
+	ep.mu.Lock()
+	ep.healthy = false
+	ep.mu.Unlock()
+	p.setUp(ep, false)
+}
+
+func (p *Pool) setUp(ep *endpoint, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	metrics.RPCEndpointUp.WithLabelValues(fmt.Sprint(p.chainID), ep.label).Set(value)
+}
+
+func (p *Pool) ordered() []*endpoint {
+	out := make([]*endpoint, 0, len(p.endpoints))
+	var unhealthy []*endpoint
+	for _, ep := range p.endpoints {
+		if ep.isHealthy() {
+			out = append(out, ep)
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+	return append(out, unhealthy...)
+}
+
+// rpcCall is a single JSON-RPC request.
+type rpcCall struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// parseRequest decodes a single request. For a batch it returns the number
+// of requests in it instead.
+func parseRequest(body []byte) (rpcCall, int) {
+	var call rpcCall
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var calls []json.RawMessage
+		if err := json.Unmarshal(trimmed, &calls); err != nil || len(calls) == 0 {
+			return call, 1
+		}
+		return call, len(calls)
+	}
+	if err := json.Unmarshal(trimmed, &call); err != nil || call.Method == "" {
+		call.Method = "unknown"
+	}
+	return call, 0
+}
+
+// envelope wraps a cached result in a response to the request with id.
+func envelope(id json.RawMessage, result json.RawMessage) []byte {
+	resp, _ := json.Marshal(struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result"`
+	}{"2.0", id, result})
+	return resp
+}
+
+// successResult returns the result of a response that has neither an error
+// nor a null result.
+func successResult(resp []byte) (json.RawMessage, bool) {
+	var msg struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &msg); err != nil {
+		return nil, false
+	}
+	if len(msg.Error) > 0 && string(msg.Error) != "null" {
+		return nil, false
+	}
+	if len(msg.Result) == 0 || string(msg.Result) == "null" {
+		return nil, false
+	}
+	return msg.Result, true
+}
+
+// resultKey identifies a response by its result or error, ignoring the
+// envelope, so responses from different endpoints can be compared. Logs and
+// receipts are reduced to their consensus fields, since providers differ in
+// optional fields (e.g. blockTimestamp) and in key order.
+func resultKey(method string, resp []byte) string {
+	var msg struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp, &msg); err != nil {
+		return ""
+	}
+	if msg.Error != nil {
+		return fmt.Sprintf("error:%d:%s", msg.Error.Code, msg.Error.Message)
+	}
+
+	if significant, ok := significantResult(method, msg.Result); ok {
+		key, err := json.Marshal(significant)
+		if err != nil {
+			return ""
+		}
+		return "result:" + string(key)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, msg.Result); err != nil {
+		return ""
+	}
+	return "result:" + buf.String()
+}
+
+// logFields are the fields of a log that every endpoint must agree on.
+type logFields struct {
+	Address     common.Address `json:"address"`
+	Topics      []common.Hash  `json:"topics"`
+	Data        hexutil.Bytes  `json:"data"`
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     uint           `json:"transactionIndex"`
+	Index       uint           `json:"logIndex"`
+	Removed     bool           `json:"removed"`
+}
+
+// receiptFields are the fields of a receipt that every endpoint must agree on.
+type receiptFields struct {
+	Status          uint64         `json:"status"`
+	TxHash          common.Hash    `json:"transactionHash"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     string         `json:"blockNumber"`
+	GasUsed         uint64         `json:"gasUsed"`
+	ContractAddress common.Address `json:"contractAddress"`
+	Logs            []logFields    `json:"logs"`
+}
+
+// significantResult decodes the result of a quorum method and keeps only
+// its consensus fields. It returns false for other methods, null results
+// and results that do not decode, which are then compared as they are.
+func significantResult(method string, result json.RawMessage) (any, bool) {
+	if len(result) == 0 || string(result) == "null" {
+		return nil, false
+	}
+
+	switch method {
+	case "eth_getLogs":
+		var logs []*types.Log
+		if err := json.Unmarshal(result, &logs); err != nil {
+			return nil, false
+		}
+		return toLogFields(logs), true
+	case "eth_getTransactionReceipt":
+		var receipt types.Receipt
+		if err := json.Unmarshal(result, &receipt); err != nil {
+			return nil, false
+		}
+		var blockNumber string
+		if receipt.BlockNumber != nil {
+			blockNumber = receipt.BlockNumber.String()
+		}
+		return receiptFields{
+			Status:          receipt.Status,
+			TxHash:          receipt.TxHash,
+			BlockHash:       receipt.BlockHash,
+			BlockNumber:     blockNumber,
+			GasUsed:         receipt.GasUsed,
+			ContractAddress: receipt.ContractAddress,
+			Logs:            toLogFields(receipt.Logs),
+		}, true
+	}
+	return nil, false
+}
+
+func toLogFields(logs []*types.Log) []logFields {
+	out := make([]logFields, len(logs))
+	for i, l := range logs {
+		out[i] = logFields{
+			Address:     l.Address,
+			Topics:      l.Topics,
+			Data:        l.Data,
+			BlockNumber: l.BlockNumber,
+			BlockHash:   l.BlockHash,
+			TxHash:      l.TxHash,
+			TxIndex:     l.TxIndex,
+			Index:       l.Index,
+			Removed:     l.Removed,
+		}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package rpcpool
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"relayer/internal/config"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	logA = `{"address":"0x5fbdb2315678afecb367f032d93f642f64180aa3","topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x01","blockNumber":"0x10","blockHash":"0x00000000000000000000000000000000000000000000000000000000000000aa","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000bb","transactionIndex":"0x0","logIndex":"0x0","removed":false}`
+	// logA with its keys reordered and an optional field some providers add
+	logAReordered = `{"blockTimestamp":"0x64","removed":false,"logIndex":"0x0","transactionIndex":"0x0","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000bb","blockHash":"0x00000000000000000000000000000000000000000000000000000000000000aa","blockNumber":"0x10","data":"0x01","topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"address":"0x5fbdb2315678afecb367f032d93f642f64180aa3"}`
+	// logA with different data
+	logB = `{"address":"0x5fbdb2315678afecb367f032d93f642f64180aa3","topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],"data":"0x02","blockNumber":"0x10","blockHash":"0x00000000000000000000000000000000000000000000000000000000000000aa","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000bb","transactionIndex":"0x0","logIndex":"0x0","removed":false}`
+)
+
+// result wraps a JSON-RPC result in a response envelope.
+func result(id, result string) string {
+	return `{"jsonrpc":"2.0","id":` + id + `,"result":` + result + `}`
+}
+
+// server answers every request with handler and counts the requests.
+func server(t *testing.T, handler func(w http.ResponseWriter, body string)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		handler(w, string(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+// reply returns a handler that always answers with resp.
+func reply(resp string) func(http.ResponseWriter, string) {
+	return func(w http.ResponseWriter, _ string) {
+		io.WriteString(w, resp)
+	}
+}
+
+func newTestPool(t *testing.T, quorum int, cfg config.RPCConfig, servers ...*httptest.Server) *Pool {
+	t.Helper()
+	chain := &config.ChainConfig{Name: "test", ChainID: 1, RpcURL: servers[0].URL, RpcQuorum: quorum}
+	for _, srv := range servers[1:] {
+		chain.RpcURLs = append(chain.RpcURLs, srv.URL)
+	}
+	p, err := NewPool(context.Background(), chain, &cfg)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	return p
+}
+
+func TestResultKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		a, b   string
+		equal  bool
+	}{
+		{"same logs", "eth_getLogs", result("1", "["+logA+"]"), result("2", "["+logA+"]"), true},
+		{"logs with extra fields and key order", "eth_getLogs", result("1", "["+logA+"]"), result("1", "["+logAReordered+"]"), true},
+		{"logs with different data", "eth_getLogs", result("1", "["+logA+"]"), result("1", "["+logB+"]"), false},
+		{"missing log", "eth_getLogs", result("1", "["+logA+","+logA+"]"), result("1", "["+logA+"]"), false},
+		{"receipt status", "eth_getTransactionReceipt",
+			result("1", `{"status":"0x1","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000bb","blockNumber":"0x10","gasUsed":"0x5208","cumulativeGasUsed":"0x5208","logs":[],"logsBloom":"0x`+strings.Repeat("00", 256)+`"}`),
+			result("1", `{"status":"0x0","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000bb","blockNumber":"0x10","gasUsed":"0x5208","cumulativeGasUsed":"0x5208","logs":[],"logsBloom":"0x`+strings.Repeat("00", 256)+`"}`),
+			false},
+		{"null receipts", "eth_getTransactionReceipt", result("1", "null"), result("2", "null"), true},
+		{"null and found receipt", "eth_getTransactionReceipt", result("1", "null"), result("1", `{"status":"0x1"}`), false},
+		{"same error", "eth_getLogs", `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`, `{"id":2,"error":{"message":"header not found","code":-32000}}`, true},
+		{"error and result", "eth_getLogs", `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`, result("1", "[]"), false},
+		{"other method whitespace", "eth_blockNumber", result("1", `"0x10"`), `{"id":1, "result": "0x10"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := resultKey(tt.method, []byte(tt.a)), resultKey(tt.method, []byte(tt.b))
+			if a == "" || b == "" {
+				t.Fatalf("resultKey = %q, %q, want both set", a, b)
+			}
+			if (a == b) != tt.equal {
+				t.Errorf("resultKey equal = %v, want %v\n%s\n%s", a == b, tt.equal, a, b)
+			}
+		})
+	}
+
+	if key := resultKey("eth_getLogs", []byte("not json")); key != "" {
+		t.Errorf("resultKey of invalid response = %q, want empty", key)
+	}
+}
+
+func TestQuorumCall(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{}]}`)
+	a, _ := server(t, reply(result("1", "["+logA+"]")))
+	a2, _ := server(t, reply(result("2", "["+logA+"]")))
+	reordered, _ := server(t, reply(result("1", "["+logAReordered+"]")))
+	b, _ := server(t, reply(result("1", "["+logB+"]")))
+	down, _ := server(t, func(w http.ResponseWriter, _ string) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	tests := []struct {
+		name    string
+		quorum  int
+		servers []*httptest.Server
+		want    string
+		err     string
+	}{
+		{"agreeing majority", 2, []*httptest.Server{b, a, reordered}, "0x01", ""},
+		{"failed endpoint ignored", 2, []*httptest.Server{down, a, a2}, "0x01", ""},
+		{"disagreement", 2, []*httptest.Server{a, b, down}, "", "rpc quorum of 2 not reached"},
+		{"unanimity", 3, []*httptest.Server{a, reordered, b}, "", "rpc quorum of 3 not reached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPool(t, tt.quorum, config.RPCConfig{}, tt.servers...)
+			resp, err := p.quorumCall(context.Background(), nil, body, "eth_getLogs")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("quorumCall error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("quorumCall: %v", err)
+			}
+			if !strings.Contains(string(resp), `"data":"`+tt.want+`"`) {
+				t.Errorf("quorumCall = %s, want data %s", resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailoverCall(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	down, downHits := server(t, func(w http.ResponseWriter, _ string) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	up, _ := server(t, reply(result("1", `"0x10"`)))
+	p := newTestPool(t, 1, config.RPCConfig{}, down, up)
+
+	for i := 0; i < 2; i++ {
+		resp, err := p.failoverCall(context.Background(), nil, body, "eth_blockNumber", 1)
+		if err != nil {
+			t.Fatalf("failoverCall: %v", err)
+		}
+		if !strings.Contains(string(resp), `"0x10"`) {
+			t.Errorf("failoverCall = %s", resp)
+		}
+	}
+	// The failed endpoint is tried last until a health check passes
+	if p.endpoints[0].isHealthy() {
+		t.Error("failed endpoint still healthy")
+	}
+	if n := downHits.Load(); n != 1 {
+		t.Errorf("failed endpoint got %d requests, want 1", n)
+	}
+}
@@ -45,4 +45,15 @@ func (s *Signer) GetTransactor(chainID *big.Int) (*bind.TransactOpts, error) {
 		return nil, fmt.Errorf("failed to create transactor: %w", err)
 	}
 	return auth, nil
-}
\ No newline at end of file
+}
+
+// SignHash signs a 32-byte digest and returns the 65-byte [R || S || V]
+// signature with V as 27/28, the form ecrecover expects.
+func (s *Signer) SignHash(hash []byte) ([]byte, error) {
+	sig, err := crypto.Sign(hash, s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	customTypes "relayer/internal/types"
+)
+
+var ErrNotFound = errors.New("message not found")
+
+// Store keeps every detected message and the per-chain listener checkpoints.
+// State is held in memory and flushed to a JSON file on every write so that
+// a restarted relayer resumes where it stopped. Delivered messages are
+// dropped by Prune once they are old enough, which keeps the file small.
+type Store struct {
+	mu          sync.RWMutex
+	path        string
+	messages    map[common.Hash]*customTypes.CrossChainMessage
+	checkpoints map[int64]uint64
+}
+
+type snapshot struct {
+	Messages    []*customTypes.CrossChainMessage `json:"messages"`
+	Checkpoints map[int64]uint64                 `json:"checkpoints"`
+}
+
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:        path,
+		messages:    make(map[common.Hash]*customTypes.CrossChainMessage),
+		checkpoints: make(map[int64]uint64),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse store: %w", err)
+	}
+	for _, msg := range snap.Messages {
+		s.messages[msg.MessageHash] = msg
+	}
+	for chainID, block := range snap.Checkpoints {
+		s.checkpoints[chainID] = block
+	}
+
+	return s, nil
+}
+
+// SaveMessage inserts or replaces a message, keyed by its hash.
+func (s *Store) SaveMessage(msg *customTypes.CrossChainMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *msg
+	s.messages[msg.MessageHash] = &stored
+	return s.flush()
+}
+
+// GetMessage returns a copy of the stored message.
+func (s *Store) GetMessage(hash common.Hash) (*customTypes.CrossChainMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	msg, ok := s.messages[hash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *msg
+	return &out, nil
+}
+
+// ListMessages returns copies of all messages with the given status.
+func (s *Store) ListMessages(status customTypes.MessageStatus) []*customTypes.CrossChainMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []*customTypes.CrossChainMessage
+	for _, msg := range s.messages {
+		if msg.Status != status {
+			continue
+		}
+		m := *msg
+		out = append(out, &m)
+	}
+	return out
+}
+
+// ListFromBlock returns messages sent from sourceChainID at or after
+// fromBlock, in any status.
+func (s *Store) ListFromBlock(sourceChainID int64, fromBlock uint64) []*customTypes.CrossChainMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []*customTypes.CrossChainMessage
+	for _, msg := range s.messages {
+		if msg.SourceChainID.Int64() != sourceChainID || msg.SourceBlock < fromBlock {
+			continue
+		}
+		m := *msg
+		out = append(out, &m)
+	}
+	return out
+}
+
+// GetCheckpoint returns the next block to scan for a chain.
+func (s *Store) GetCheckpoint(chainID int64) (uint64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	block, ok := s.checkpoints[chainID]
+	return block, ok
+}
+
+func (s *Store) SetCheckpoint(chainID int64, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[chainID] = block
+	return s.flush()
+}
+
+// Prune removes delivered messages processed before the cutoff, and returns
+// how many it removed. Messages in any other status are kept.
+func (s *Store) Prune(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := 0
+	for hash, msg := range s.messages {
+		if msg.Status != customTypes.StatusCompleted && msg.Status != customTypes.StatusExecutionFailed {
+			continue
+		}
+		done := msg.CreatedAt
+		if msg.ProcessedAt != nil {
+			done = *msg.ProcessedAt
+		}
+		if done.Before(before) {
+			delete(s.messages, hash)
+			pruned++
+		}
+	}
+	if pruned == 0 {
+		return 0, nil
+	}
+	return pruned, s.flush()
+}
+
+// flush writes the snapshot to a temp file and renames it into place.
+// Callers must hold the write lock.
+func (s *Store) flush() error {
+	snap := snapshot{Checkpoints: s.checkpoints}
+	for _, msg := range s.messages {
+		snap.Messages = append(snap.Messages, msg)
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace store: %w", err)
+	}
+	return nil
+}
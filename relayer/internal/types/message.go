@@ -14,12 +14,18 @@ type CrossChainMessage struct {
 	Sender           common.Address
 	Payload          []byte
 	Timestamp        *big.Int
+	FeePaid          *big.Int
+	Target           common.Address
+	ExecGasLimit     uint64
+	ExecError        string
 	MessageHash      common.Hash
 	SourceTxHash     common.Hash
+	SourceBlock      uint64
 	DestTxHash       common.Hash
 	Status           MessageStatus
 	CreatedAt        time.Time
 	ProcessedAt      *time.Time
+	ExecutableAt     *time.Time
 	RetryCount       int
 	LastRetryAt      *time.Time
 }
@@ -27,10 +33,17 @@ type CrossChainMessage struct {
 type MessageStatus string
 
 const (
-	StatusPending   MessageStatus = "pending"
-	StatusRelaying  MessageStatus = "relaying"
-	StatusCompleted MessageStatus = "completed"
-	StatusFailed    MessageStatus = "failed"
+	StatusPending         MessageStatus = "pending"
+	StatusRelaying        MessageStatus = "relaying"
+	StatusCompleted       MessageStatus = "completed"
+	StatusFailed          MessageStatus = "failed"
+	StatusRejected        MessageStatus = "rejected"
+	StatusUnderfunded     MessageStatus = "underfunded"
+	StatusExecutionFailed MessageStatus = "execution_failed"
+	StatusAttested        MessageStatus = "attested"
+	StatusProposed        MessageStatus = "proposed"
+	StatusChallenged      MessageStatus = "challenged"
+	StatusOrphaned        MessageStatus = "orphaned"
 )
 
 type ChainConfig struct {
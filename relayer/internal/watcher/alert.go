@@ -0,0 +1,131 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"relayer/internal/metrics"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Alert kinds
+const (
+	KindForged      = "forged"
+	KindMismatched  = "mismatched"
+	KindUndelivered = "undelivered"
+	// A forged proposal could not be challenged, e.g. because the watcher
+	// key is not an allowed challenger or has no funds
+	KindChallengeFailed = "challenge_failed"
+)
+
+// Alert is a delivery the watcher could not reconcile with the source chain.
+// It is also the JSON body posted to the webhook.
+type Alert struct {
+	Kind          string      `json:"kind"`
+	MessageHash   common.Hash `json:"message_hash"`
+	SourceChainID int64       `json:"source_chain_id"`
+	DestChainID   int64       `json:"dest_chain_id"`
+	Detail        string      `json:"detail"`
+	Time          time.Time   `json:"time"`
+}
+
+// alertRetention is how long an alert is remembered so that rescanning the
+// same blocks does not raise it again.
+const alertRetention = 24 * time.Hour
+
+// Alerter fans alerts out to the log, metrics and an optional webhook. Each
+// kind of alert is raised once per message.
+type Alerter struct {
+	webhookURL string
+	client     *http.Client
+
+	mu     sync.Mutex
+	raised map[alertKey]time.Time
+}
+
+type alertKey struct {
+	kind        string
+	messageHash common.Hash
+}
+
+func NewAlerter(webhookURL string) *Alerter {
+	return &Alerter{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		raised:     make(map[alertKey]time.Time),
+	}
+}
+
+func (a *Alerter) Alert(ctx context.Context, alert Alert) {
+	alert.Time = time.Now()
+	if !a.firstRaise(alert) {
+		return
+	}
+
+	log.Printf(" ALERT [%s] message %s (%d -> %d): %s",
+		alert.Kind, alert.MessageHash.Hex(), alert.SourceChainID, alert.DestChainID, alert.Detail)
+	metrics.WatcherAlerts.WithLabelValues(
+		alert.Kind,
+		fmt.Sprint(alert.SourceChainID),
+		fmt.Sprint(alert.DestChainID),
+	).Inc()
+
+	if a.webhookURL == "" {
+		return
+	}
+	go func() {
+		if err := a.post(ctx, alert); err != nil {
+			log.Printf(" Failed to post alert webhook: %v", err)
+		}
+	}()
+}
+
+// firstRaise records alert and reports whether it was not already raised
+// within alertRetention.
+func (a *Alerter) firstRaise(alert Alert) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, raisedAt := range a.raised {
+		if alert.Time.Sub(raisedAt) > alertRetention {
+			delete(a.raised, key)
+		}
+	}
+
+	key := alertKey{alert.Kind, alert.MessageHash}
+	if _, ok := a.raised[key]; ok {
+		return false
+	}
+	a.raised[key] = alert.Time
+	return true
+}
+
+func (a *Alerter) post(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"relayer/pkg/contracts"
+)
+
+// SLAChecker flags messages that were sent but not delivered within the SLA.
+type SLAChecker struct {
+	tracker *Tracker
+	alerter *Alerter
+	dests   map[int64]*contracts.DestinationMessenger
+	sla     time.Duration
+}
+
+// NewSLAChecker checks tracked messages against dests, the destination
+// contracts by chain ID.
+func NewSLAChecker(tracker *Tracker, alerter *Alerter, dests map[int64]*contracts.DestinationMessenger, sla time.Duration) *SLAChecker {
+	return &SLAChecker{
+		tracker: tracker,
+		alerter: alerter,
+		dests:   dests,
+		sla:     sla,
+	}
+}
+
+func (c *SLAChecker) Start(ctx context.Context) error {
+	log.Printf("Starting delivery SLA checks (SLA: %s)", c.sla)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *SLAChecker) check(ctx context.Context) {
+	for _, msg := range c.tracker.Overdue(c.sla) {
+		destChainID := msg.DestChainID.Int64()
+
+		// The delivery may predate our lookback window
+		if dest, ok := c.dests[destChainID]; ok {
+			processed, err := dest.IsProcessed(nil, msg.MessageHash)
+			if err != nil {
+				log.Printf(" Failed to check delivery of %s: %v", msg.MessageHash.Hex(), err)
+			} else if processed {
+				continue
+			}
+		}
+
+		c.alerter.Alert(ctx, Alert{
+			Kind:          KindUndelivered,
+			MessageHash:   msg.MessageHash,
+			SourceChainID: msg.SourceChainID.Int64(),
+			DestChainID:   destChainID,
+			Detail: fmt.Sprintf("sent in tx %s at %s, not delivered within %s",
+				msg.SourceTxHash.Hex(), time.Unix(msg.Timestamp.Int64(), 0).UTC().Format(time.RFC3339), c.sla),
+		})
+	}
+}
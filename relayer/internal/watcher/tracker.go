@@ -0,0 +1,145 @@
+package watcher
+
+import (
+	"context"
+	"math/big"
+	"relayer/internal/metrics"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	customTypes "relayer/internal/types"
+)
+
+// DefaultRetention is how long the tracker keeps sends and deliveries it
+// could not match, unless a longer delivery SLA needs them.
+const DefaultRetention = 24 * time.Hour
+
+// Tracker matches messages sent on source chains with their deliveries.
+// It is shared by the watchers of all chains.
+type Tracker struct {
+	mu        sync.Mutex
+	sent      map[common.Hash]*customTypes.CrossChainMessage
+	delivered map[common.Hash]time.Time
+	bySend    map[sendKey]*customTypes.CrossChainMessage
+}
+
+// sendKey identifies a message by its source chain and nonce, which a
+// tampered delivery cannot change without pointing at another message.
+type sendKey struct {
+	sourceChainID int64
+	nonce         string
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		sent:      make(map[common.Hash]*customTypes.CrossChainMessage),
+		delivered: make(map[common.Hash]time.Time),
+		bySend:    make(map[sendKey]*customTypes.CrossChainMessage),
+	}
+}
+
+// Sent records a message seen on its source chain, unless its delivery was
+// already seen.
+func (t *Tracker) Sent(msg *customTypes.CrossChainMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.bySend[sendKey{msg.SourceChainID.Int64(), msg.Nonce.String()}] = msg
+
+	if _, ok := t.delivered[msg.MessageHash]; ok {
+		delete(t.delivered, msg.MessageHash)
+		return
+	}
+	t.sent[msg.MessageHash] = msg
+	metrics.WatcherOutstanding.Set(float64(len(t.sent)))
+}
+
+// Delivered marks a message delivered and returns the source record, if
+// the source chain's MessageSent has been seen.
+func (t *Tracker) Delivered(hash common.Hash) (*customTypes.CrossChainMessage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	msg, ok := t.sent[hash]
+	if ok {
+		delete(t.sent, hash)
+		metrics.WatcherOutstanding.Set(float64(len(t.sent)))
+	} else {
+		t.delivered[hash] = time.Now()
+	}
+	return msg, ok
+}
+
+// SentWithNonce returns the message sent from sourceChainID with nonce,
+// delivered or not, if its MessageSent has been seen.
+func (t *Tracker) SentWithNonce(sourceChainID int64, nonce *big.Int) (*customTypes.CrossChainMessage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	msg, ok := t.bySend[sendKey{sourceChainID, nonce.String()}]
+	return msg, ok
+}
+
+// Overdue removes and returns messages sent more than sla ago and still
+// not delivered.
+func (t *Tracker) Overdue(sla time.Duration) []*customTypes.CrossChainMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var overdue []*customTypes.CrossChainMessage
+	for hash, msg := range t.sent {
+		sentAt := time.Unix(msg.Timestamp.Int64(), 0)
+		if now.Sub(sentAt) > sla {
+			overdue = append(overdue, msg)
+			delete(t.sent, hash)
+		}
+	}
+
+	metrics.WatcherOutstanding.Set(float64(len(t.sent)))
+	return overdue
+}
+
+// Start prunes the tracker every minute until ctx is cancelled, whether or
+// not an SLA is checked, so memory stays bounded.
+func (t *Tracker) Start(ctx context.Context, retention time.Duration) error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			t.Prune(retention)
+		}
+	}
+}
+
+// Prune forgets undelivered sends, unmatched deliveries and sends kept for
+// SentWithNonce that are older than retention.
+func (t *Tracker) Prune(retention time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for hash, msg := range t.sent {
+		if now.Sub(time.Unix(msg.Timestamp.Int64(), 0)) > retention {
+			delete(t.sent, hash)
+		}
+	}
+	for hash, seenAt := range t.delivered {
+		if now.Sub(seenAt) > retention {
+			delete(t.delivered, hash)
+		}
+	}
+	for key, msg := range t.bySend {
+		if now.Sub(time.Unix(msg.Timestamp.Int64(), 0)) > retention {
+			delete(t.bySend, key)
+		}
+	}
+
+	metrics.WatcherOutstanding.Set(float64(len(t.sent)))
+}
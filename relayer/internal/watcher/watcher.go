@@ -0,0 +1,339 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"relayer/internal/config"
+	"relayer/internal/finality"
+	"relayer/internal/listener"
+	"relayer/internal/signer"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	customTypes "relayer/internal/types"
+	"relayer/pkg/contracts"
+)
+
+var (
+	messageSentTopic     = crypto.Keccak256Hash([]byte("MessageSent(uint256,uint256,address,bytes,uint256)"))
+	messageReceivedTopic = crypto.Keccak256Hash([]byte("MessageReceived(bytes32,uint256,address,bytes,uint256)"))
+	messageProposedTopic = crypto.Keccak256Hash([]byte("MessageProposed(bytes32,uint256,address,bytes,uint256,uint256)"))
+)
+
+// Watcher audits one chain. Messages sent from its source contract are
+// handed to the tracker; every message proposed or received on its
+// destination contract is matched with the tracker's record, or else looked
+// up with SourceMessenger.verifyMessage on the source chain. Proposals the
+// source chain does not know about are challenged.
+type Watcher struct {
+	client         *ethclient.Client
+	chainConfig    *config.ChainConfig
+	sourceContract *contracts.SourceMessenger
+	destContract   *contracts.DestinationMessenger
+	sources        map[int64]*contracts.SourceMessenger
+	tracker        *Tracker
+	alerter        *Alerter
+	signer         *signer.Signer
+	finality       finality.Policy
+	lookback       uint64
+	fromBlock      uint64
+}
+
+// NewWatcher watches the contracts of chainConfig. sources maps each source
+// chain ID to its SourceMessenger. With a nil signer the watcher only
+// reports.
+func NewWatcher(
+	client *ethclient.Client,
+	chainConfig *config.ChainConfig,
+	sources map[int64]*contracts.SourceMessenger,
+	tracker *Tracker,
+	alerter *Alerter,
+	signer *signer.Signer,
+	lookback uint64,
+) (*Watcher, error) {
+	destContract, err := contracts.NewDestinationMessenger(chainConfig.GetDestContract(), client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate destination contract: %w", err)
+	}
+
+	policy, err := finality.ChainPolicy(chainConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid finality for %s: %w", chainConfig.Name, err)
+	}
+
+	return &Watcher{
+		client:         client,
+		chainConfig:    chainConfig,
+		sourceContract: sources[chainConfig.ChainID],
+		destContract:   destContract,
+		sources:        sources,
+		tracker:        tracker,
+		alerter:        alerter,
+		signer:         signer,
+		finality:       policy,
+		lookback:       lookback,
+	}, nil
+}
+
+// DestContract returns the destination contract binding.
+func (w *Watcher) DestContract() *contracts.DestinationMessenger {
+	return w.destContract
+}
+
+func (w *Watcher) Start(ctx context.Context) error {
+	log.Printf("Starting watcher for %s (Chain ID: %d)", w.chainConfig.Name, w.chainConfig.ChainID)
+
+	head, err := w.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	if head > w.lookback {
+		w.fromBlock = head - w.lookback
+	}
+
+	w.checkChallenger()
+
+	headers := make(chan *types.Header)
+	subErr, err := listener.WatchHeads(ctx, w.client, w.chainConfig.Name, config.DefaultPollInterval, headers)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subErr:
+			return fmt.Errorf("subscription error: %w", err)
+		case header := <-headers:
+			confirmedBlock, ok, err := finality.ConfirmedBlock(ctx, w.client, w.finality, header.Number.Uint64())
+			if err != nil {
+				log.Printf("Error getting confirmed block: %v", err)
+				continue
+			}
+			if !ok || w.fromBlock > confirmedBlock {
+				continue
+			}
+
+			if err := w.processBlocks(ctx, w.fromBlock, confirmedBlock); err != nil {
+				log.Printf("Error processing blocks: %v", err)
+				continue
+			}
+			w.fromBlock = confirmedBlock + 1
+		}
+	}
+}
+
+func (w *Watcher) processBlocks(ctx context.Context, from, to uint64) error {
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{w.chainConfig.GetSourceContract(), w.chainConfig.GetDestContract()},
+		Topics:    [][]common.Hash{{messageSentTopic, messageProposedTopic, messageReceivedTopic}},
+	}
+
+	logs, err := w.client.FilterLogs(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to filter logs: %w", err)
+	}
+
+	for _, vLog := range logs {
+		var err error
+		switch {
+		case vLog.Topics[0] == messageSentTopic && vLog.Address == w.chainConfig.GetSourceContract():
+			err = w.handleSent(vLog)
+		case vLog.Topics[0] == messageProposedTopic && vLog.Address == w.chainConfig.GetDestContract():
+			err = w.handleProposed(ctx, vLog)
+		case vLog.Topics[0] == messageReceivedTopic && vLog.Address == w.chainConfig.GetDestContract():
+			err = w.handleReceived(ctx, vLog)
+		}
+		if err != nil {
+			// Retried with the same range on the next head; alerts already
+			// raised for it are not raised again
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Watcher) handleSent(vLog types.Log) error {
+	event, err := w.sourceContract.ParseMessageSent(vLog)
+	if err != nil {
+		return fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	sourceChainID := w.chainConfig.GetChainID()
+	w.tracker.Sent(&customTypes.CrossChainMessage{
+		Nonce:         event.Nonce,
+		SourceChainID: sourceChainID,
+		DestChainID:   event.DestinationChainId,
+		Sender:        event.Sender,
+		Payload:       event.Payload,
+		Timestamp:     event.Timestamp,
+		MessageHash: listener.ComputeMessageHash(
+			event.Nonce,
+			sourceChainID,
+			event.DestinationChainId,
+			event.Sender,
+			event.Payload,
+			event.Timestamp,
+		),
+		SourceTxHash: vLog.TxHash,
+		SourceBlock:  vLog.BlockNumber,
+	})
+	return nil
+}
+
+func (w *Watcher) handleProposed(ctx context.Context, vLog types.Log) error {
+	event, err := w.destContract.ParseMessageProposed(vLog)
+	if err != nil {
+		return fmt.Errorf("failed to parse proposal: %w", err)
+	}
+
+	exists, err := w.verify(event.SourceChainId, event.MessageHash)
+	if err != nil || exists {
+		return err
+	}
+
+	alert := Alert{
+		Kind:          KindForged,
+		MessageHash:   event.MessageHash,
+		SourceChainID: event.SourceChainId.Int64(),
+		DestChainID:   w.chainConfig.ChainID,
+		Detail:        fmt.Sprintf("proposal in tx %s not found on source chain", vLog.TxHash.Hex()),
+	}
+	w.alerter.Alert(ctx, alert)
+
+	// A failed challenge is not retried with the block range: a key that is
+	// not allowed or has no funds would stall auditing of the whole chain
+	if err := w.challenge(event.MessageHash, event.ExecutableAt); err != nil {
+		log.Printf(" Failed to challenge %x: %v", event.MessageHash, err)
+		alert.Kind = KindChallengeFailed
+		alert.Detail = fmt.Sprintf("proposal in tx %s not challenged: %v", vLog.TxHash.Hex(), err)
+		w.alerter.Alert(ctx, alert)
+	}
+	return nil
+}
+
+func (w *Watcher) handleReceived(ctx context.Context, vLog types.Log) error {
+	event, err := w.destContract.ParseMessageReceived(vLog)
+	if err != nil {
+		return fmt.Errorf("failed to parse delivery: %w", err)
+	}
+
+	alert := Alert{
+		MessageHash:   event.MessageHash,
+		SourceChainID: event.SourceChainId.Int64(),
+		DestChainID:   w.chainConfig.ChainID,
+	}
+
+	// A delivery of a hash that was sent is genuine, since the hash covers
+	// every field of the message
+	if _, ok := w.tracker.Delivered(event.MessageHash); ok {
+		return nil
+	}
+
+	exists, err := w.verify(event.SourceChainId, event.MessageHash)
+	if err != nil || exists {
+		return err
+	}
+
+	// Unknown to the source chain: either made up, or a real message with
+	// tampered fields, which we find by its source chain and nonce
+	if sent, ok := w.tracker.SentWithNonce(event.SourceChainId.Int64(), event.Nonce); ok {
+		alert.Kind = KindMismatched
+		alert.Detail = fmt.Sprintf("delivery in tx %s: %s", vLog.TxHash.Hex(), mismatch(sent, event, w.chainConfig.ChainID))
+	} else {
+		alert.Kind = KindForged
+		alert.Detail = fmt.Sprintf("delivery in tx %s not found on source chain", vLog.TxHash.Hex())
+	}
+	w.alerter.Alert(ctx, alert)
+	return nil
+}
+
+// mismatch describes how a delivery differs from the message sent with the
+// same source chain and nonce.
+func mismatch(sent *customTypes.CrossChainMessage, event *contracts.DestinationMessengerMessageReceived, destChainID int64) string {
+	switch {
+	case sent.DestChainID.Int64() != destChainID:
+		return fmt.Sprintf("sent to chain %s", sent.DestChainID)
+	case sent.Sender != event.Sender:
+		return fmt.Sprintf("sender %s, sent by %s", event.Sender.Hex(), sent.Sender.Hex())
+	case !bytes.Equal(sent.Payload, event.Payload):
+		return "payload differs from the one sent"
+	}
+	// The delivery event has no timestamp; it is all that is left
+	return "timestamp differs from the one sent"
+}
+
+// verify reports whether the source chain has the message. Messages from
+// chains without a known SourceMessenger are logged and skipped.
+func (w *Watcher) verify(sourceChainID *big.Int, messageHash [32]byte) (bool, error) {
+	source, ok := w.sources[sourceChainID.Int64()]
+	if !ok {
+		log.Printf(" No source contract for chain %s, skipping message %x", sourceChainID, messageHash)
+		return true, nil
+	}
+
+	exists, err := source.VerifyMessage(nil, messageHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify message on chain %s: %w", sourceChainID, err)
+	}
+	return exists, nil
+}
+
+// checkChallenger warns if the watcher has a key that the destination
+// contract does not allow to challenge, since its challenges would fail.
+func (w *Watcher) checkChallenger() {
+	if w.signer == nil {
+		return
+	}
+
+	allowed, err := w.destContract.IsChallenger(nil, w.signer.GetAddress())
+	if err != nil {
+		log.Printf(" Failed to check challenger on %s: %v", w.chainConfig.Name, err)
+		return
+	}
+	if !allowed {
+		log.Printf(" WARNING: %s is not an allowed challenger on %s; forged proposals there will only be alerted. Allow it with setChallenger.",
+			w.signer.GetAddress().Hex(), w.chainConfig.Name)
+	}
+}
+
+func (w *Watcher) challenge(messageHash [32]byte, executableAt *big.Int) error {
+	if w.signer == nil {
+		return nil
+	}
+
+	challenged, err := w.destContract.Challenged(nil, messageHash)
+	if err != nil {
+		return fmt.Errorf("failed to check challenge: %w", err)
+	}
+	if challenged {
+		return nil
+	}
+	if time.Now().Unix() >= executableAt.Int64() {
+		log.Printf(" Challenge window for %x has closed", messageHash)
+		return nil
+	}
+
+	auth, err := w.signer.GetTransactor(w.chainConfig.GetChainID())
+	if err != nil {
+		return fmt.Errorf("failed to get transactor: %w", err)
+	}
+
+	tx, err := w.destContract.Challenge(auth, messageHash)
+	if err != nil {
+		return fmt.Errorf("failed to send challenge: %w", err)
+	}
+	log.Printf(" Challenged message %x. Tx: %s", messageHash, tx.Hash().Hex())
+	return nil
+}
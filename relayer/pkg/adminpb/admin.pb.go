@@ -0,0 +1,1318 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: admin/v1/admin.proto
+
+package adminpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Route struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SourceChainId int64                  `protobuf:"varint,1,opt,name=source_chain_id,json=sourceChainId,proto3" json:"source_chain_id,omitempty"`
+	DestChainId   int64                  `protobuf:"varint,2,opt,name=dest_chain_id,json=destChainId,proto3" json:"dest_chain_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Route) Reset() {
+	*x = Route{}
+	mi := &file_admin_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Route) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route) ProtoMessage() {}
+
+func (x *Route) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route.ProtoReflect.Descriptor instead.
+func (*Route) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Route) GetSourceChainId() int64 {
+	if x != nil {
+		return x.SourceChainId
+	}
+	return 0
+}
+
+func (x *Route) GetDestChainId() int64 {
+	if x != nil {
+		return x.DestChainId
+	}
+	return 0
+}
+
+type PauseTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*PauseTarget_ChainId
+	//	*PauseTarget_Route
+	//	*PauseTarget_All
+	Target        isPauseTarget_Target `protobuf_oneof:"target"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseTarget) Reset() {
+	*x = PauseTarget{}
+	mi := &file_admin_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseTarget) ProtoMessage() {}
+
+func (x *PauseTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseTarget.ProtoReflect.Descriptor instead.
+func (*PauseTarget) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PauseTarget) GetTarget() isPauseTarget_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *PauseTarget) GetChainId() int64 {
+	if x != nil {
+		if x, ok := x.Target.(*PauseTarget_ChainId); ok {
+			return x.ChainId
+		}
+	}
+	return 0
+}
+
+func (x *PauseTarget) GetRoute() *Route {
+	if x != nil {
+		if x, ok := x.Target.(*PauseTarget_Route); ok {
+			return x.Route
+		}
+	}
+	return nil
+}
+
+func (x *PauseTarget) GetAll() bool {
+	if x != nil {
+		if x, ok := x.Target.(*PauseTarget_All); ok {
+			return x.All
+		}
+	}
+	return false
+}
+
+type isPauseTarget_Target interface {
+	isPauseTarget_Target()
+}
+
+type PauseTarget_ChainId struct {
+	ChainId int64 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3,oneof"`
+}
+
+type PauseTarget_Route struct {
+	Route *Route `protobuf:"bytes,2,opt,name=route,proto3,oneof"`
+}
+
+type PauseTarget_All struct {
+	// Emergency pause of every route.
+	All bool `protobuf:"varint,3,opt,name=all,proto3,oneof"`
+}
+
+func (*PauseTarget_ChainId) isPauseTarget_Target() {}
+
+func (*PauseTarget_Route) isPauseTarget_Target() {}
+
+func (*PauseTarget_All) isPauseTarget_Target() {}
+
+type PauseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        *PauseTarget           `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseRequest) Reset() {
+	*x = PauseRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseRequest) ProtoMessage() {}
+
+func (x *PauseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseRequest.ProtoReflect.Descriptor instead.
+func (*PauseRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PauseRequest) GetTarget() *PauseTarget {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+type PauseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        []*PauseTarget         `protobuf:"bytes,1,rep,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseResponse) Reset() {
+	*x = PauseResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseResponse) ProtoMessage() {}
+
+func (x *PauseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseResponse.ProtoReflect.Descriptor instead.
+func (*PauseResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PauseResponse) GetPaused() []*PauseTarget {
+	if x != nil {
+		return x.Paused
+	}
+	return nil
+}
+
+type ResumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        *PauseTarget           `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeRequest) Reset() {
+	*x = ResumeRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeRequest) ProtoMessage() {}
+
+func (x *ResumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeRequest.ProtoReflect.Descriptor instead.
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResumeRequest) GetTarget() *PauseTarget {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+type ResumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        []*PauseTarget         `protobuf:"bytes,1,rep,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeResponse) Reset() {
+	*x = ResumeResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeResponse) ProtoMessage() {}
+
+func (x *ResumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeResponse.ProtoReflect.Descriptor instead.
+func (*ResumeResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResumeResponse) GetPaused() []*PauseTarget {
+	if x != nil {
+		return x.Paused
+	}
+	return nil
+}
+
+type ListPausedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPausedRequest) Reset() {
+	*x = ListPausedRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPausedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPausedRequest) ProtoMessage() {}
+
+func (x *ListPausedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPausedRequest.ProtoReflect.Descriptor instead.
+func (*ListPausedRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+type ListPausedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        []*PauseTarget         `protobuf:"bytes,1,rep,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPausedResponse) Reset() {
+	*x = ListPausedResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPausedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPausedResponse) ProtoMessage() {}
+
+func (x *ListPausedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPausedResponse.ProtoReflect.Descriptor instead.
+func (*ListPausedResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPausedResponse) GetPaused() []*PauseTarget {
+	if x != nil {
+		return x.Paused
+	}
+	return nil
+}
+
+type ReloadPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadPolicyRequest) Reset() {
+	*x = ReloadPolicyRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadPolicyRequest) ProtoMessage() {}
+
+func (x *ReloadPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadPolicyRequest.ProtoReflect.Descriptor instead.
+func (*ReloadPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+type ReloadPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        []*PauseTarget         `protobuf:"bytes,1,rep,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadPolicyResponse) Reset() {
+	*x = ReloadPolicyResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadPolicyResponse) ProtoMessage() {}
+
+func (x *ReloadPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadPolicyResponse.ProtoReflect.Descriptor instead.
+func (*ReloadPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReloadPolicyResponse) GetPaused() []*PauseTarget {
+	if x != nil {
+		return x.Paused
+	}
+	return nil
+}
+
+type RequeueMessageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hex-encoded message hash.
+	MessageHash   string `protobuf:"bytes,1,opt,name=message_hash,json=messageHash,proto3" json:"message_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueMessageRequest) Reset() {
+	*x = RequeueMessageRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueMessageRequest) ProtoMessage() {}
+
+func (x *RequeueMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueMessageRequest.ProtoReflect.Descriptor instead.
+func (*RequeueMessageRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RequeueMessageRequest) GetMessageHash() string {
+	if x != nil {
+		return x.MessageHash
+	}
+	return ""
+}
+
+type RequeueMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageHash   string                 `protobuf:"bytes,1,opt,name=message_hash,json=messageHash,proto3" json:"message_hash,omitempty"`
+	SourceChainId int64                  `protobuf:"varint,2,opt,name=source_chain_id,json=sourceChainId,proto3" json:"source_chain_id,omitempty"`
+	DestChainId   int64                  `protobuf:"varint,3,opt,name=dest_chain_id,json=destChainId,proto3" json:"dest_chain_id,omitempty"`
+	RetryCount    int32                  `protobuf:"varint,4,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueMessageResponse) Reset() {
+	*x = RequeueMessageResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueMessageResponse) ProtoMessage() {}
+
+func (x *RequeueMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueMessageResponse.ProtoReflect.Descriptor instead.
+func (*RequeueMessageResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RequeueMessageResponse) GetMessageHash() string {
+	if x != nil {
+		return x.MessageHash
+	}
+	return ""
+}
+
+func (x *RequeueMessageResponse) GetSourceChainId() int64 {
+	if x != nil {
+		return x.SourceChainId
+	}
+	return 0
+}
+
+func (x *RequeueMessageResponse) GetDestChainId() int64 {
+	if x != nil {
+		return x.DestChainId
+	}
+	return 0
+}
+
+func (x *RequeueMessageResponse) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+type RewindCheckpointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainId       int64                  `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Block         uint64                 `protobuf:"varint,2,opt,name=block,proto3" json:"block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RewindCheckpointRequest) Reset() {
+	*x = RewindCheckpointRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RewindCheckpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewindCheckpointRequest) ProtoMessage() {}
+
+func (x *RewindCheckpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewindCheckpointRequest.ProtoReflect.Descriptor instead.
+func (*RewindCheckpointRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RewindCheckpointRequest) GetChainId() int64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *RewindCheckpointRequest) GetBlock() uint64 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+type RewindCheckpointResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainId       int64                  `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	PreviousBlock uint64                 `protobuf:"varint,2,opt,name=previous_block,json=previousBlock,proto3" json:"previous_block,omitempty"`
+	Block         uint64                 `protobuf:"varint,3,opt,name=block,proto3" json:"block,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RewindCheckpointResponse) Reset() {
+	*x = RewindCheckpointResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RewindCheckpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewindCheckpointResponse) ProtoMessage() {}
+
+func (x *RewindCheckpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewindCheckpointResponse.ProtoReflect.Descriptor instead.
+func (*RewindCheckpointResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RewindCheckpointResponse) GetChainId() int64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *RewindCheckpointResponse) GetPreviousBlock() uint64 {
+	if x != nil {
+		return x.PreviousBlock
+	}
+	return 0
+}
+
+func (x *RewindCheckpointResponse) GetBlock() uint64 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+type GetSignerStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignerStatusRequest) Reset() {
+	*x = GetSignerStatusRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignerStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignerStatusRequest) ProtoMessage() {}
+
+func (x *GetSignerStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignerStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetSignerStatusRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{14}
+}
+
+type ChainBalance struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	ChainId int64                  `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Name    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Balance in wei as a decimal string.
+	BalanceWei   string `protobuf:"bytes,3,opt,name=balance_wei,json=balanceWei,proto3" json:"balance_wei,omitempty"`
+	PendingNonce uint64 `protobuf:"varint,4,opt,name=pending_nonce,json=pendingNonce,proto3" json:"pending_nonce,omitempty"`
+	// Set when the chain could not be queried.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChainBalance) Reset() {
+	*x = ChainBalance{}
+	mi := &file_admin_v1_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChainBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChainBalance) ProtoMessage() {}
+
+func (x *ChainBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChainBalance.ProtoReflect.Descriptor instead.
+func (*ChainBalance) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ChainBalance) GetChainId() int64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *ChainBalance) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChainBalance) GetBalanceWei() string {
+	if x != nil {
+		return x.BalanceWei
+	}
+	return ""
+}
+
+func (x *ChainBalance) GetPendingNonce() uint64 {
+	if x != nil {
+		return x.PendingNonce
+	}
+	return 0
+}
+
+func (x *ChainBalance) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetSignerStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Balances      []*ChainBalance        `protobuf:"bytes,2,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSignerStatusResponse) Reset() {
+	*x = GetSignerStatusResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSignerStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignerStatusResponse) ProtoMessage() {}
+
+func (x *GetSignerStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignerStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetSignerStatusResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetSignerStatusResponse) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *GetSignerStatusResponse) GetBalances() []*ChainBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type GetRateLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateLimitsRequest) Reset() {
+	*x = GetRateLimitsRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsRequest) ProtoMessage() {}
+
+func (x *GetRateLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsRequest.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{17}
+}
+
+type RateLimitBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "sender" or "route".
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Sender address or "<source>-><dest>" chain pair.
+	Key           string  `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Tokens        float64 `protobuf:"fixed64,3,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Burst         int32   `protobuf:"varint,4,opt,name=burst,proto3" json:"burst,omitempty"`
+	PerMinute     float64 `protobuf:"fixed64,5,opt,name=per_minute,json=perMinute,proto3" json:"per_minute,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitBucket) Reset() {
+	*x = RateLimitBucket{}
+	mi := &file_admin_v1_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitBucket) ProtoMessage() {}
+
+func (x *RateLimitBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitBucket.ProtoReflect.Descriptor instead.
+func (*RateLimitBucket) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RateLimitBucket) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RateLimitBucket) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RateLimitBucket) GetTokens() float64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+func (x *RateLimitBucket) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *RateLimitBucket) GetPerMinute() float64 {
+	if x != nil {
+		return x.PerMinute
+	}
+	return 0
+}
+
+type GetRateLimitsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*RateLimitBucket     `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateLimitsResponse) Reset() {
+	*x = GetRateLimitsResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsResponse) ProtoMessage() {}
+
+func (x *GetRateLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsResponse.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRateLimitsResponse) GetBuckets() []*RateLimitBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type GetConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	mi := &file_admin_v1_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{20}
+}
+
+type GetConfigResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// YAML-encoded config with private key, admin token and RPC paths redacted.
+	Yaml          string `protobuf:"bytes,1,opt,name=yaml,proto3" json:"yaml,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigResponse) Reset() {
+	*x = GetConfigResponse{}
+	mi := &file_admin_v1_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigResponse) ProtoMessage() {}
+
+func (x *GetConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_v1_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigResponse) Descriptor() ([]byte, []int) {
+	return file_admin_v1_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetConfigResponse) GetYaml() string {
+	if x != nil {
+		return x.Yaml
+	}
+	return ""
+}
+
+var File_admin_v1_admin_proto protoreflect.FileDescriptor
+
+const file_admin_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14admin/v1/admin.proto\x12\badmin.v1\"S\n" +
+	"\x05Route\x12&\n" +
+	"\x0fsource_chain_id\x18\x01 \x01(\x03R\rsourceChainId\x12\"\n" +
+	"\rdest_chain_id\x18\x02 \x01(\x03R\vdestChainId\"q\n" +
+	"\vPauseTarget\x12\x1b\n" +
+	"\bchain_id\x18\x01 \x01(\x03H\x00R\achainId\x12'\n" +
+	"\x05route\x18\x02 \x01(\v2\x0f.admin.v1.RouteH\x00R\x05route\x12\x12\n" +
+	"\x03all\x18\x03 \x01(\bH\x00R\x03allB\b\n" +
+	"\x06target\"=\n" +
+	"\fPauseRequest\x12-\n" +
+	"\x06target\x18\x01 \x01(\v2\x15.admin.v1.PauseTargetR\x06target\">\n" +
+	"\rPauseResponse\x12-\n" +
+	"\x06paused\x18\x01 \x03(\v2\x15.admin.v1.PauseTargetR\x06paused\">\n" +
+	"\rResumeRequest\x12-\n" +
+	"\x06target\x18\x01 \x01(\v2\x15.admin.v1.PauseTargetR\x06target\"?\n" +
+	"\x0eResumeResponse\x12-\n" +
+	"\x06paused\x18\x01 \x03(\v2\x15.admin.v1.PauseTargetR\x06paused\"\x13\n" +
+	"\x11ListPausedRequest\"C\n" +
+	"\x12ListPausedResponse\x12-\n" +
+	"\x06paused\x18\x01 \x03(\v2\x15.admin.v1.PauseTargetR\x06paused\"\x15\n" +
+	"\x13ReloadPolicyRequest\"E\n" +
+	"\x14ReloadPolicyResponse\x12-\n" +
+	"\x06paused\x18\x01 \x03(\v2\x15.admin.v1.PauseTargetR\x06paused\":\n" +
+	"\x15RequeueMessageRequest\x12!\n" +
+	"\fmessage_hash\x18\x01 \x01(\tR\vmessageHash\"\xa8\x01\n" +
+	"\x16RequeueMessageResponse\x12!\n" +
+	"\fmessage_hash\x18\x01 \x01(\tR\vmessageHash\x12&\n" +
+	"\x0fsource_chain_id\x18\x02 \x01(\x03R\rsourceChainId\x12\"\n" +
+	"\rdest_chain_id\x18\x03 \x01(\x03R\vdestChainId\x12\x1f\n" +
+	"\vretry_count\x18\x04 \x01(\x05R\n" +
+	"retryCount\"J\n" +
+	"\x17RewindCheckpointRequest\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x03R\achainId\x12\x14\n" +
+	"\x05block\x18\x02 \x01(\x04R\x05block\"r\n" +
+	"\x18RewindCheckpointResponse\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x03R\achainId\x12%\n" +
+	"\x0eprevious_block\x18\x02 \x01(\x04R\rpreviousBlock\x12\x14\n" +
+	"\x05block\x18\x03 \x01(\x04R\x05block\"\x18\n" +
+	"\x16GetSignerStatusRequest\"\x99\x01\n" +
+	"\fChainBalance\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x03R\achainId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vbalance_wei\x18\x03 \x01(\tR\n" +
+	"balanceWei\x12#\n" +
+	"\rpending_nonce\x18\x04 \x01(\x04R\fpendingNonce\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"g\n" +
+	"\x17GetSignerStatusResponse\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x122\n" +
+	"\bbalances\x18\x02 \x03(\v2\x16.admin.v1.ChainBalanceR\bbalances\"\x16\n" +
+	"\x14GetRateLimitsRequest\"\x84\x01\n" +
+	"\x0fRateLimitBucket\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x16\n" +
+	"\x06tokens\x18\x03 \x01(\x01R\x06tokens\x12\x14\n" +
+	"\x05burst\x18\x04 \x01(\x05R\x05burst\x12\x1d\n" +
+	"\n" +
+	"per_minute\x18\x05 \x01(\x01R\tperMinute\"L\n" +
+	"\x15GetRateLimitsResponse\x123\n" +
+	"\abuckets\x18\x01 \x03(\v2\x19.admin.v1.RateLimitBucketR\abuckets\"\x12\n" +
+	"\x10GetConfigRequest\"'\n" +
+	"\x11GetConfigResponse\x12\x12\n" +
+	"\x04yaml\x18\x01 \x01(\tR\x04yaml2\xbd\x05\n" +
+	"\fAdminService\x128\n" +
+	"\x05Pause\x12\x16.admin.v1.PauseRequest\x1a\x17.admin.v1.PauseResponse\x12;\n" +
+	"\x06Resume\x12\x17.admin.v1.ResumeRequest\x1a\x18.admin.v1.ResumeResponse\x12G\n" +
+	"\n" +
+	"ListPaused\x12\x1b.admin.v1.ListPausedRequest\x1a\x1c.admin.v1.ListPausedResponse\x12M\n" +
+	"\fReloadPolicy\x12\x1d.admin.v1.ReloadPolicyRequest\x1a\x1e.admin.v1.ReloadPolicyResponse\x12S\n" +
+	"\x0eRequeueMessage\x12\x1f.admin.v1.RequeueMessageRequest\x1a .admin.v1.RequeueMessageResponse\x12Y\n" +
+	"\x10RewindCheckpoint\x12!.admin.v1.RewindCheckpointRequest\x1a\".admin.v1.RewindCheckpointResponse\x12V\n" +
+	"\x0fGetSignerStatus\x12 .admin.v1.GetSignerStatusRequest\x1a!.admin.v1.GetSignerStatusResponse\x12P\n" +
+	"\rGetRateLimits\x12\x1e.admin.v1.GetRateLimitsRequest\x1a\x1f.admin.v1.GetRateLimitsResponse\x12D\n" +
+	"\tGetConfig\x12\x1a.admin.v1.GetConfigRequest\x1a\x1b.admin.v1.GetConfigResponseB\x1dZ\x1brelayer/pkg/adminpb;adminpbb\x06proto3"
+
+var (
+	file_admin_v1_admin_proto_rawDescOnce sync.Once
+	file_admin_v1_admin_proto_rawDescData []byte
+)
+
+func file_admin_v1_admin_proto_rawDescGZIP() []byte {
+	file_admin_v1_admin_proto_rawDescOnce.Do(func() {
+		file_admin_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_v1_admin_proto_rawDesc), len(file_admin_v1_admin_proto_rawDesc)))
+	})
+	return file_admin_v1_admin_proto_rawDescData
+}
+
+var file_admin_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_admin_v1_admin_proto_goTypes = []any{
+	(*Route)(nil),                    // 0: admin.v1.Route
+	(*PauseTarget)(nil),              // 1: admin.v1.PauseTarget
+	(*PauseRequest)(nil),             // 2: admin.v1.PauseRequest
+	(*PauseResponse)(nil),            // 3: admin.v1.PauseResponse
+	(*ResumeRequest)(nil),            // 4: admin.v1.ResumeRequest
+	(*ResumeResponse)(nil),           // 5: admin.v1.ResumeResponse
+	(*ListPausedRequest)(nil),        // 6: admin.v1.ListPausedRequest
+	(*ListPausedResponse)(nil),       // 7: admin.v1.ListPausedResponse
+	(*ReloadPolicyRequest)(nil),      // 8: admin.v1.ReloadPolicyRequest
+	(*ReloadPolicyResponse)(nil),     // 9: admin.v1.ReloadPolicyResponse
+	(*RequeueMessageRequest)(nil),    // 10: admin.v1.RequeueMessageRequest
+	(*RequeueMessageResponse)(nil),   // 11: admin.v1.RequeueMessageResponse
+	(*RewindCheckpointRequest)(nil),  // 12: admin.v1.RewindCheckpointRequest
+	(*RewindCheckpointResponse)(nil), // 13: admin.v1.RewindCheckpointResponse
+	(*GetSignerStatusRequest)(nil),   // 14: admin.v1.GetSignerStatusRequest
+	(*ChainBalance)(nil),             // 15: admin.v1.ChainBalance
+	(*GetSignerStatusResponse)(nil),  // 16: admin.v1.GetSignerStatusResponse
+	(*GetRateLimitsRequest)(nil),     // 17: admin.v1.GetRateLimitsRequest
+	(*RateLimitBucket)(nil),          // 18: admin.v1.RateLimitBucket
+	(*GetRateLimitsResponse)(nil),    // 19: admin.v1.GetRateLimitsResponse
+	(*GetConfigRequest)(nil),         // 20: admin.v1.GetConfigRequest
+	(*GetConfigResponse)(nil),        // 21: admin.v1.GetConfigResponse
+}
+var file_admin_v1_admin_proto_depIdxs = []int32{
+	0,  // 0: admin.v1.PauseTarget.route:type_name -> admin.v1.Route
+	1,  // 1: admin.v1.PauseRequest.target:type_name -> admin.v1.PauseTarget
+	1,  // 2: admin.v1.PauseResponse.paused:type_name -> admin.v1.PauseTarget
+	1,  // 3: admin.v1.ResumeRequest.target:type_name -> admin.v1.PauseTarget
+	1,  // 4: admin.v1.ResumeResponse.paused:type_name -> admin.v1.PauseTarget
+	1,  // 5: admin.v1.ListPausedResponse.paused:type_name -> admin.v1.PauseTarget
+	1,  // 6: admin.v1.ReloadPolicyResponse.paused:type_name -> admin.v1.PauseTarget
+	15, // 7: admin.v1.GetSignerStatusResponse.balances:type_name -> admin.v1.ChainBalance
+	18, // 8: admin.v1.GetRateLimitsResponse.buckets:type_name -> admin.v1.RateLimitBucket
+	2,  // 9: admin.v1.AdminService.Pause:input_type -> admin.v1.PauseRequest
+	4,  // 10: admin.v1.AdminService.Resume:input_type -> admin.v1.ResumeRequest
+	6,  // 11: admin.v1.AdminService.ListPaused:input_type -> admin.v1.ListPausedRequest
+	8,  // 12: admin.v1.AdminService.ReloadPolicy:input_type -> admin.v1.ReloadPolicyRequest
+	10, // 13: admin.v1.AdminService.RequeueMessage:input_type -> admin.v1.RequeueMessageRequest
+	12, // 14: admin.v1.AdminService.RewindCheckpoint:input_type -> admin.v1.RewindCheckpointRequest
+	14, // 15: admin.v1.AdminService.GetSignerStatus:input_type -> admin.v1.GetSignerStatusRequest
+	17, // 16: admin.v1.AdminService.GetRateLimits:input_type -> admin.v1.GetRateLimitsRequest
+	20, // 17: admin.v1.AdminService.GetConfig:input_type -> admin.v1.GetConfigRequest
+	3,  // 18: admin.v1.AdminService.Pause:output_type -> admin.v1.PauseResponse
+	5,  // 19: admin.v1.AdminService.Resume:output_type -> admin.v1.ResumeResponse
+	7,  // 20: admin.v1.AdminService.ListPaused:output_type -> admin.v1.ListPausedResponse
+	9,  // 21: admin.v1.AdminService.ReloadPolicy:output_type -> admin.v1.ReloadPolicyResponse
+	11, // 22: admin.v1.AdminService.RequeueMessage:output_type -> admin.v1.RequeueMessageResponse
+	13, // 23: admin.v1.AdminService.RewindCheckpoint:output_type -> admin.v1.RewindCheckpointResponse
+	16, // 24: admin.v1.AdminService.GetSignerStatus:output_type -> admin.v1.GetSignerStatusResponse
+	19, // 25: admin.v1.AdminService.GetRateLimits:output_type -> admin.v1.GetRateLimitsResponse
+	21, // 26: admin.v1.AdminService.GetConfig:output_type -> admin.v1.GetConfigResponse
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_admin_v1_admin_proto_init() }
+func file_admin_v1_admin_proto_init() {
+	if File_admin_v1_admin_proto != nil {
+		return
+	}
+	file_admin_v1_admin_proto_msgTypes[1].OneofWrappers = []any{
+		(*PauseTarget_ChainId)(nil),
+		(*PauseTarget_Route)(nil),
+		(*PauseTarget_All)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_v1_admin_proto_rawDesc), len(file_admin_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_v1_admin_proto_goTypes,
+		DependencyIndexes: file_admin_v1_admin_proto_depIdxs,
+		MessageInfos:      file_admin_v1_admin_proto_msgTypes,
+	}.Build()
+	File_admin_v1_admin_proto = out.File
+	file_admin_v1_admin_proto_goTypes = nil
+	file_admin_v1_admin_proto_depIdxs = nil
+}
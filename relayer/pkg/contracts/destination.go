@@ -11,7 +11,7 @@ import (
 )
 
 // DestinationMessengerABI is the ABI of the DestinationMessenger contract
-const DestinationMessengerABI = `[{"inputs":[{"internalType":"address","name":"_relayer","type":"address"}],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[],"name":"AlreadyProcessed","type":"error"},{"inputs":[],"name":"InvalidSourceChain","type":"error"},{"inputs":[],"name":"OnlyRelayer","type":"error"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"messageHash","type":"bytes32"},{"indexed":true,"internalType":"uint256","name":"sourceChainId","type":"uint256"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"bytes","name":"payload","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"}],"name":"MessageReceived","type":"event"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"isProcessed","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"processedMessages","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"receiveMessage","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"receivedCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"relayer","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"_newRelayer","type":"address"}],"name":"updateRelayer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+const DestinationMessengerABI = `[{"inputs":[{"internalType":"address","name":"_relayer","type":"address"}],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[],"name":"AlreadyProcessed","type":"error"},{"inputs":[],"name":"AlreadyProposed","type":"error"},{"inputs":[],"name":"ChallengeWindowClosed","type":"error"},{"inputs":[{"internalType":"uint256","name":"executableAt","type":"uint256"}],"name":"ChallengeWindowOpen","type":"error"},{"inputs":[],"name":"DirectDeliveryDisabled","type":"error"},{"inputs":[],"name":"InsufficientGas","type":"error"},{"inputs":[],"name":"InsufficientSignatures","type":"error"},{"inputs":[],"name":"InvalidProof","type":"error"},{"inputs":[],"name":"InvalidRLP","type":"error"},{"inputs":[],"name":"InvalidSignature","type":"error"},{"inputs":[],"name":"InvalidSourceChain","type":"error"},{"inputs":[],"name":"InvalidThreshold","type":"error"},{"inputs":[],"name":"InvalidValidator","type":"error"},{"inputs":[],"name":"MessageChallenged","type":"error"},{"inputs":[],"name":"MessageNotProven","type":"error"},{"inputs":[],"name":"NotProposed","type":"error"},{"inputs":[],"name":"NotTargeted","type":"error"},{"inputs":[],"name":"OnlyChallenger","type":"error"},{"inputs":[],"name":"OnlyOwner","type":"error"},{"inputs":[],"name":"OnlyRelayer","type":"error"},{"inputs":[],"name":"OptimisticDeliveryDisabled","type":"error"},{"inputs":[],"name":"SignersNotSorted","type":"error"},{"inputs":[],"name":"TargetedMessage","type":"error"},{"inputs":[],"name":"UnknownBlock","type":"error"},{"inputs":[],"name":"UnknownSourceMessenger","type":"error"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"challengePeriod","type":"uint256"}],"name":"ChallengePeriodUpdated","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"challenger","type":"address"},{"indexed":false,"internalType":"bool","name":"allowed","type":"bool"}],"name":"ChallengerUpdated","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"messageHash","type":"bytes32"},{"indexed":true,"internalType":"address","name":"target","type":"address"},{"indexed":false,"internalType":"bool","name":"success","type":"bool"},{"indexed":false,"internalType":"bytes","name":"returnData","type":"bytes"}],"name":"MessageExecuted","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"messageHash","type":"bytes32"},{"indexed":true,"internalType":"uint256","name":"sourceChainId","type":"uint256"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"bytes","name":"payload","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"executableAt","type":"uint256"}],"name":"MessageProposed","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"messageHash","type":"bytes32"},{"indexed":true,"internalType":"uint256","name":"sourceChainId","type":"uint256"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"bytes","name":"payload","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"}],"name":"MessageReceived","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"messageHash","type":"bytes32"},{"indexed":true,"internalType":"address","name":"challenger","type":"address"}],"name":"ProposalChallenged","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"chainId","type":"uint256"},{"indexed":false,"internalType":"address","name":"messenger","type":"address"}],"name":"SourceMessengerUpdated","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"oracle","type":"address"}],"name":"StateRootOracleUpdated","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address[]","name":"validators","type":"address[]"},{"indexed":false,"internalType":"uint256","name":"threshold","type":"uint256"}],"name":"ValidatorsUpdated","type":"event"},{"inputs":[],"name":"MESSAGE_EXISTS_SLOT","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"attestationDigest","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"challenge","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"challengePeriod","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"challenged","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"executableAt","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"finalizeAndExecute","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"finalizeMessage","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"isChallenger","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"isProcessed","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"isValidator","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"processedMessages","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"proposeMessage","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"receiveAndExecute","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"},{"internalType":"bytes[]","name":"_signatures","type":"bytes[]"}],"name":"receiveAndExecuteAttested","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"},{"internalType":"bytes","name":"_proof","type":"bytes"}],"name":"receiveAndExecuteProven","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"},{"internalType":"bytes[]","name":"_signatures","type":"bytes[]"}],"name":"receiveAttested","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"receiveMessage","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"},{"internalType":"bytes","name":"_proof","type":"bytes"}],"name":"receiveProven","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"","type":"address"}],"name":"receivedCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"relayer","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"resolveChallenge","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_challengePeriod","type":"uint256"}],"name":"setChallengePeriod","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"_challenger","type":"address"},{"internalType":"bool","name":"_allowed","type":"bool"}],"name":"setChallenger","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_chainId","type":"uint256"},{"internalType":"address","name":"_messenger","type":"address"}],"name":"setSourceMessenger","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"_oracle","type":"address"}],"name":"setStateRootOracle","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address[]","name":"_validators","type":"address[]"},{"internalType":"uint256","name":"_threshold","type":"uint256"}],"name":"setValidators","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"sourceMessengers","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"stateRootOracle","outputs":[{"internalType":"contract IStateRootOracle","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"threshold","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"_newRelayer","type":"address"}],"name":"updateRelayer","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"validators","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
 
 // DestinationMessenger is Go binding for the DestinationMessenger contract
 type DestinationMessenger struct {
@@ -32,6 +32,36 @@ type DestinationMessengerFilterer struct {
 	contract *bind.BoundContract
 }
 
+// DestinationMessengerMessageExecuted represents a MessageExecuted event
+type DestinationMessengerMessageExecuted struct {
+	MessageHash [32]byte
+	Target      common.Address
+	Success     bool
+	ReturnData  []byte
+	Raw         types.Log
+}
+
+// DestinationMessengerMessageReceived represents a MessageReceived event
+type DestinationMessengerMessageReceived struct {
+	MessageHash   [32]byte
+	SourceChainId *big.Int
+	Sender        common.Address
+	Payload       []byte
+	Nonce         *big.Int
+	Raw           types.Log
+}
+
+// DestinationMessengerMessageProposed represents a MessageProposed event
+type DestinationMessengerMessageProposed struct {
+	MessageHash   [32]byte
+	SourceChainId *big.Int
+	Sender        common.Address
+	Payload       []byte
+	Nonce         *big.Int
+	ExecutableAt  *big.Int
+	Raw           types.Log
+}
+
 // NewDestinationMessenger creates a new instance of DestinationMessenger bound to a contract
 func NewDestinationMessenger(address common.Address, backend bind.ContractBackend) (*DestinationMessenger, error) {
 	parsed, err := abi.JSON(strings.NewReader(DestinationMessengerABI))
@@ -58,6 +88,116 @@ func (t *DestinationMessengerTransactor) ReceiveMessage(
 	return t.contract.Transact(opts, "receiveMessage", nonce, sourceChainId, sender, payload, timestamp)
 }
 
+// ReceiveAndExecute relays a targeted message and calls its receiver
+func (t *DestinationMessengerTransactor) ReceiveAndExecute(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "receiveAndExecute", nonce, sourceChainId, sender, payload, timestamp)
+}
+
+// ProposeMessage starts the challenge window for a message in optimistic mode
+func (t *DestinationMessengerTransactor) ProposeMessage(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "proposeMessage", nonce, sourceChainId, sender, payload, timestamp)
+}
+
+// FinalizeMessage records a proposed message after its challenge window
+func (t *DestinationMessengerTransactor) FinalizeMessage(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "finalizeMessage", nonce, sourceChainId, sender, payload, timestamp)
+}
+
+// FinalizeAndExecute records and executes a proposed targeted message after
+// its challenge window
+func (t *DestinationMessengerTransactor) FinalizeAndExecute(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "finalizeAndExecute", nonce, sourceChainId, sender, payload, timestamp)
+}
+
+// Challenge blocks a proposed message that does not exist on the source chain
+func (t *DestinationMessengerTransactor) Challenge(opts *bind.TransactOpts, messageHash [32]byte) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "challenge", messageHash)
+}
+
+// ReceiveAttested delivers a message authorised by validator signatures
+// sorted by signer address
+func (t *DestinationMessengerTransactor) ReceiveAttested(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	signatures [][]byte,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "receiveAttested", nonce, sourceChainId, sender, payload, timestamp, signatures)
+}
+
+// ReceiveAndExecuteAttested delivers and executes a targeted message
+// authorised by validator signatures sorted by signer address
+func (t *DestinationMessengerTransactor) ReceiveAndExecuteAttested(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	signatures [][]byte,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "receiveAndExecuteAttested", nonce, sourceChainId, sender, payload, timestamp, signatures)
+}
+
+// ReceiveProven delivers a message authorised by a storage proof of the
+// message on the source chain
+func (t *DestinationMessengerTransactor) ReceiveProven(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	proof []byte,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "receiveProven", nonce, sourceChainId, sender, payload, timestamp, proof)
+}
+
+// ReceiveAndExecuteProven delivers and executes a targeted message
+// authorised by a storage proof
+func (t *DestinationMessengerTransactor) ReceiveAndExecuteProven(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	proof []byte,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "receiveAndExecuteProven", nonce, sourceChainId, sender, payload, timestamp, proof)
+}
+
 // IsProcessed checks if a message has been processed
 func (c *DestinationMessengerCaller) IsProcessed(opts *bind.CallOpts, messageHash [32]byte) (bool, error) {
 	var out []interface{}
@@ -68,6 +208,97 @@ func (c *DestinationMessengerCaller) IsProcessed(opts *bind.CallOpts, messageHas
 	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
 }
 
+// Threshold returns the number of validator signatures required
+func (c *DestinationMessengerCaller) Threshold(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "threshold")
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// IsValidator checks if an address is in the validator set
+func (c *DestinationMessengerCaller) IsValidator(opts *bind.CallOpts, addr common.Address) (bool, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "isValidator", addr)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// AttestationDigest returns the digest validators sign for a message
+func (c *DestinationMessengerCaller) AttestationDigest(opts *bind.CallOpts, messageHash [32]byte) ([32]byte, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "attestationDigest", messageHash)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return *abi.ConvertType(out[0], new([32]byte)).(*[32]byte), nil
+}
+
+// ExecutableAt returns when a proposed message can be finalized, or zero if
+// it was never proposed
+func (c *DestinationMessengerCaller) ExecutableAt(opts *bind.CallOpts, messageHash [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "executableAt", messageHash)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// Challenged checks if a proposed message was challenged
+func (c *DestinationMessengerCaller) Challenged(opts *bind.CallOpts, messageHash [32]byte) (bool, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "challenged", messageHash)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// IsChallenger checks if an account may challenge proposed messages
+func (c *DestinationMessengerCaller) IsChallenger(opts *bind.CallOpts, account common.Address) (bool, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "isChallenger", account)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// ChallengePeriod returns the optimistic challenge window in seconds
+func (c *DestinationMessengerCaller) ChallengePeriod(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "challengePeriod")
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// StateRootOracle returns the oracle storage proofs are checked against
+func (c *DestinationMessengerCaller) StateRootOracle(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "stateRootOracle")
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// SourceMessengers returns the SourceMessenger trusted for a source chain
+func (c *DestinationMessengerCaller) SourceMessengers(opts *bind.CallOpts, chainId *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "sourceMessengers", chainId)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
 // Relayer returns the relayer address
 func (c *DestinationMessengerCaller) Relayer(opts *bind.CallOpts) (common.Address, error) {
 	var out []interface{}
@@ -88,6 +319,36 @@ func (c *DestinationMessengerCaller) ReceivedCount(opts *bind.CallOpts, addr com
 	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
 }
 
+// ParseMessageExecuted parses a MessageExecuted event from a log
+func (f *DestinationMessengerFilterer) ParseMessageExecuted(log types.Log) (*DestinationMessengerMessageExecuted, error) {
+	event := new(DestinationMessengerMessageExecuted)
+	if err := f.contract.UnpackLog(event, "MessageExecuted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ParseMessageReceived parses a MessageReceived event from a log
+func (f *DestinationMessengerFilterer) ParseMessageReceived(log types.Log) (*DestinationMessengerMessageReceived, error) {
+	event := new(DestinationMessengerMessageReceived)
+	if err := f.contract.UnpackLog(event, "MessageReceived", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ParseMessageProposed parses a MessageProposed event from a log
+func (f *DestinationMessengerFilterer) ParseMessageProposed(log types.Log) (*DestinationMessengerMessageProposed, error) {
+	event := new(DestinationMessengerMessageProposed)
+	if err := f.contract.UnpackLog(event, "MessageProposed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
 // Convenience methods on the main struct
 func (d *DestinationMessenger) ReceiveMessage(
 	opts *bind.TransactOpts,
@@ -107,3 +368,123 @@ func (d *DestinationMessenger) IsProcessed(opts *bind.CallOpts, messageHash [32]
 func (d *DestinationMessenger) Relayer(opts *bind.CallOpts) (common.Address, error) {
 	return d.DestinationMessengerCaller.Relayer(opts)
 }
+
+func (d *DestinationMessenger) ReceiveAndExecute(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.ReceiveAndExecute(opts, nonce, sourceChainId, sender, payload, timestamp)
+}
+
+func (d *DestinationMessenger) ParseMessageExecuted(log types.Log) (*DestinationMessengerMessageExecuted, error) {
+	return d.DestinationMessengerFilterer.ParseMessageExecuted(log)
+}
+
+func (d *DestinationMessenger) ReceiveAttested(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	signatures [][]byte,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.ReceiveAttested(opts, nonce, sourceChainId, sender, payload, timestamp, signatures)
+}
+
+func (d *DestinationMessenger) ReceiveAndExecuteAttested(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	signatures [][]byte,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.ReceiveAndExecuteAttested(opts, nonce, sourceChainId, sender, payload, timestamp, signatures)
+}
+
+func (d *DestinationMessenger) ReceiveProven(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	proof []byte,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.ReceiveProven(opts, nonce, sourceChainId, sender, payload, timestamp, proof)
+}
+
+func (d *DestinationMessenger) ReceiveAndExecuteProven(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+	proof []byte,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.ReceiveAndExecuteProven(opts, nonce, sourceChainId, sender, payload, timestamp, proof)
+}
+
+func (d *DestinationMessenger) StateRootOracle(opts *bind.CallOpts) (common.Address, error) {
+	return d.DestinationMessengerCaller.StateRootOracle(opts)
+}
+
+func (d *DestinationMessenger) ProposeMessage(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.ProposeMessage(opts, nonce, sourceChainId, sender, payload, timestamp)
+}
+
+func (d *DestinationMessenger) FinalizeMessage(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.FinalizeMessage(opts, nonce, sourceChainId, sender, payload, timestamp)
+}
+
+func (d *DestinationMessenger) FinalizeAndExecute(
+	opts *bind.TransactOpts,
+	nonce *big.Int,
+	sourceChainId *big.Int,
+	sender common.Address,
+	payload []byte,
+	timestamp *big.Int,
+) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.FinalizeAndExecute(opts, nonce, sourceChainId, sender, payload, timestamp)
+}
+
+func (d *DestinationMessenger) Challenge(opts *bind.TransactOpts, messageHash [32]byte) (*types.Transaction, error) {
+	return d.DestinationMessengerTransactor.Challenge(opts, messageHash)
+}
+
+func (d *DestinationMessenger) ExecutableAt(opts *bind.CallOpts, messageHash [32]byte) (*big.Int, error) {
+	return d.DestinationMessengerCaller.ExecutableAt(opts, messageHash)
+}
+
+func (d *DestinationMessenger) Challenged(opts *bind.CallOpts, messageHash [32]byte) (bool, error) {
+	return d.DestinationMessengerCaller.Challenged(opts, messageHash)
+}
+
+func (d *DestinationMessenger) ParseMessageReceived(log types.Log) (*DestinationMessengerMessageReceived, error) {
+	return d.DestinationMessengerFilterer.ParseMessageReceived(log)
+}
+
+func (d *DestinationMessenger) ParseMessageProposed(log types.Log) (*DestinationMessengerMessageProposed, error) {
+	return d.DestinationMessengerFilterer.ParseMessageProposed(log)
+}
@@ -12,7 +12,7 @@ import (
 )
 
 // SourceMessengerABI is the ABI of the SourceMessenger contract
-const SourceMessengerABI = `[{"inputs":[{"internalType":"uint256","name":"_destChainId","type":"uint256"},{"internalType":"bytes","name":"_payload","type":"bytes"}],"name":"sendMessage","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"uint256","name":"_destChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"getMessageHash","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"pure","type":"function"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"verifyMessage","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"nonce","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"messageExists","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"nonce","type":"uint256"},{"indexed":true,"internalType":"uint256","name":"destinationChainId","type":"uint256"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"bytes","name":"payload","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"timestamp","type":"uint256"}],"name":"MessageSent","type":"event"}]`
+const SourceMessengerABI = `[{"inputs":[],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[],"name":"EmptyPayload","type":"error"},{"inputs":[{"internalType":"uint256","name":"required","type":"uint256"},{"internalType":"uint256","name":"paid","type":"uint256"}],"name":"InsufficientFee","type":"error"},{"inputs":[],"name":"InvalidDestinationChain","type":"error"},{"inputs":[],"name":"InvalidTarget","type":"error"},{"inputs":[],"name":"OnlyOwner","type":"error"},{"inputs":[],"name":"ReservedPayload","type":"error"},{"inputs":[],"name":"WithdrawFailed","type":"error"},{"inputs":[{"internalType":"uint256","name":"_destChainId","type":"uint256"},{"internalType":"bytes","name":"_payload","type":"bytes"}],"name":"sendMessage","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"_nonce","type":"uint256"},{"internalType":"uint256","name":"_sourceChainId","type":"uint256"},{"internalType":"uint256","name":"_destChainId","type":"uint256"},{"internalType":"address","name":"_sender","type":"address"},{"internalType":"bytes","name":"_payload","type":"bytes"},{"internalType":"uint256","name":"_timestamp","type":"uint256"}],"name":"getMessageHash","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"pure","type":"function"},{"inputs":[{"internalType":"bytes32","name":"_messageHash","type":"bytes32"}],"name":"verifyMessage","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"nonce","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"messageExists","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"nonce","type":"uint256"},{"indexed":true,"internalType":"uint256","name":"destinationChainId","type":"uint256"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"bytes","name":"payload","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"timestamp","type":"uint256"}],"name":"MessageSent","type":"event"},{"inputs":[{"internalType":"uint256","name":"_destChainId","type":"uint256"},{"internalType":"bytes","name":"_payload","type":"bytes"}],"name":"sendMessageWithFee","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"},{"internalType":"bytes","name":"_payload","type":"bytes"}],"name":"quoteFee","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"baseFee","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"feePerByte","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"_baseFee","type":"uint256"},{"internalType":"uint256","name":"_feePerByte","type":"uint256"}],"name":"setFees","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address payable","name":"_to","type":"address"}],"name":"withdrawFees","outputs":[],"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"nonce","type":"uint256"},{"indexed":true,"internalType":"bytes32","name":"messageHash","type":"bytes32"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"}],"name":"FeePaid","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"baseFee","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"feePerByte","type":"uint256"}],"name":"FeesUpdated","type":"event"},{"inputs":[{"internalType":"uint256","name":"_destChainId","type":"uint256"},{"internalType":"address","name":"_target","type":"address"},{"internalType":"uint256","name":"_gasLimit","type":"uint256"},{"internalType":"bytes","name":"_data","type":"bytes"}],"name":"sendMessageToTarget","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"payable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"nonce","type":"uint256"},{"indexed":true,"internalType":"address","name":"target","type":"address"},{"indexed":false,"internalType":"uint256","name":"gasLimit","type":"uint256"}],"name":"MessageTargeted","type":"event"}]`
 
 // SourceMessenger is Go binding for the SourceMessenger contract
 type SourceMessenger struct {
@@ -43,6 +43,22 @@ type SourceMessengerMessageSent struct {
 	Raw                types.Log
 }
 
+// SourceMessengerFeePaid represents a FeePaid event
+type SourceMessengerFeePaid struct {
+	Nonce       *big.Int
+	MessageHash [32]byte
+	Amount      *big.Int
+	Raw         types.Log
+}
+
+// SourceMessengerMessageTargeted represents a MessageTargeted event
+type SourceMessengerMessageTargeted struct {
+	Nonce    *big.Int
+	Target   common.Address
+	GasLimit *big.Int
+	Raw      types.Log
+}
+
 // SourceMessengerMessageSentIterator is returned from FilterMessageSent
 type SourceMessengerMessageSentIterator struct {
 	Event    *SourceMessengerMessageSent
@@ -116,6 +132,26 @@ func (t *SourceMessengerTransactor) SendMessage(opts *bind.TransactOpts, destCha
 	return t.contract.Transact(opts, "sendMessage", destChainId, payload)
 }
 
+// SendMessageWithFee sends a cross-chain message paying opts.Value as the relay fee
+func (t *SourceMessengerTransactor) SendMessageWithFee(opts *bind.TransactOpts, destChainId *big.Int, payload []byte) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "sendMessageWithFee", destChainId, payload)
+}
+
+// SendMessageToTarget sends a message to be executed on target on the destination chain
+func (t *SourceMessengerTransactor) SendMessageToTarget(opts *bind.TransactOpts, destChainId *big.Int, target common.Address, gasLimit *big.Int, data []byte) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "sendMessageToTarget", destChainId, target, gasLimit, data)
+}
+
+// QuoteFee returns the minimum fee for sendMessageWithFee
+func (c *SourceMessengerCaller) QuoteFee(opts *bind.CallOpts, destChainId *big.Int, payload []byte) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "quoteFee", destChainId, payload)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
 // Nonce returns the current nonce
 func (c *SourceMessengerCaller) Nonce(opts *bind.CallOpts) (*big.Int, error) {
 	var out []interface{}
@@ -126,6 +162,16 @@ func (c *SourceMessengerCaller) Nonce(opts *bind.CallOpts) (*big.Int, error) {
 	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
 }
 
+// VerifyMessage checks if a message hash was sent from this contract
+func (c *SourceMessengerCaller) VerifyMessage(opts *bind.CallOpts, messageHash [32]byte) (bool, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "verifyMessage", messageHash)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
 // ParseMessageSent parses a MessageSent event from a log
 func (f *SourceMessengerFilterer) ParseMessageSent(log types.Log) (*SourceMessengerMessageSent, error) {
 	event := new(SourceMessengerMessageSent)
@@ -136,6 +182,26 @@ func (f *SourceMessengerFilterer) ParseMessageSent(log types.Log) (*SourceMessen
 	return event, nil
 }
 
+// ParseFeePaid parses a FeePaid event from a log
+func (f *SourceMessengerFilterer) ParseFeePaid(log types.Log) (*SourceMessengerFeePaid, error) {
+	event := new(SourceMessengerFeePaid)
+	if err := f.contract.UnpackLog(event, "FeePaid", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ParseMessageTargeted parses a MessageTargeted event from a log
+func (f *SourceMessengerFilterer) ParseMessageTargeted(log types.Log) (*SourceMessengerMessageTargeted, error) {
+	event := new(SourceMessengerMessageTargeted)
+	if err := f.contract.UnpackLog(event, "MessageTargeted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
 // Convenience method on the main struct
 func (s *SourceMessenger) SendMessage(opts *bind.TransactOpts, destChainId *big.Int, payload []byte) (*types.Transaction, error) {
 	return s.SourceMessengerTransactor.SendMessage(opts, destChainId, payload)
@@ -144,3 +210,27 @@ func (s *SourceMessenger) SendMessage(opts *bind.TransactOpts, destChainId *big.
 func (s *SourceMessenger) ParseMessageSent(log types.Log) (*SourceMessengerMessageSent, error) {
 	return s.SourceMessengerFilterer.ParseMessageSent(log)
 }
+
+func (s *SourceMessenger) SendMessageWithFee(opts *bind.TransactOpts, destChainId *big.Int, payload []byte) (*types.Transaction, error) {
+	return s.SourceMessengerTransactor.SendMessageWithFee(opts, destChainId, payload)
+}
+
+func (s *SourceMessenger) QuoteFee(opts *bind.CallOpts, destChainId *big.Int, payload []byte) (*big.Int, error) {
+	return s.SourceMessengerCaller.QuoteFee(opts, destChainId, payload)
+}
+
+func (s *SourceMessenger) ParseFeePaid(log types.Log) (*SourceMessengerFeePaid, error) {
+	return s.SourceMessengerFilterer.ParseFeePaid(log)
+}
+
+func (s *SourceMessenger) SendMessageToTarget(opts *bind.TransactOpts, destChainId *big.Int, target common.Address, gasLimit *big.Int, data []byte) (*types.Transaction, error) {
+	return s.SourceMessengerTransactor.SendMessageToTarget(opts, destChainId, target, gasLimit, data)
+}
+
+func (s *SourceMessenger) ParseMessageTargeted(log types.Log) (*SourceMessengerMessageTargeted, error) {
+	return s.SourceMessengerFilterer.ParseMessageTargeted(log)
+}
+
+func (s *SourceMessenger) VerifyMessage(opts *bind.CallOpts, messageHash [32]byte) (bool, error) {
+	return s.SourceMessengerCaller.VerifyMessage(opts, messageHash)
+}
@@ -33,7 +33,7 @@ cd ../cli
 go run cmd/messenger-cli/main.go send \
   --rpc http://localhost:8545 \
   --contract 0x5FbDB2315678afecb367f032d93F642f64180aa3 \
-  --key 0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80 \
+  --insecure-key 0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80 \
   --dest-chain 31338 \
   --message "E2E test"
 